@@ -1,6 +1,10 @@
 package agent
 
-import "github.com/rannday/kea-api/client"
+import (
+	"context"
+
+	"github.com/rannday/kea-api/client"
+)
 
 /*
  * Control Agent API
@@ -12,25 +16,50 @@ import "github.com/rannday/kea-api/client"
 
 // BuildReport fetches the build configuration report of the control-agent.
 func BuildReport(c *client.Client) (string, error) {
-	return client.BuildReport(c, client.Services.Agent)
+	return BuildReportContext(context.Background(), c)
+}
+
+// BuildReportContext is like BuildReport but honours ctx.
+func BuildReportContext(ctx context.Context, c *client.Client) (string, error) {
+	return client.BuildReportContext(ctx, c, client.Services.Agent)
 }
 
 // ConfigGet retrieves the configuration from the control-agent.
 func ConfigGet(c *client.Client) (CtrlAgentConfig, error) {
-	return client.ConfigGet[CtrlAgentConfig](c, client.Services.Agent)
+	return ConfigGetContext(context.Background(), c)
+}
+
+// ConfigGetContext is like ConfigGet but honours ctx.
+func ConfigGetContext(ctx context.Context, c *client.Client) (CtrlAgentConfig, error) {
+	return client.ConfigGetContext[CtrlAgentConfig](ctx, c, client.Services.Agent)
 }
 
 // ListCommands fetches the list of commands for the control-agent.
 func ListCommands(c *client.Client) ([]string, error) {
-	return client.ListCommands(c, client.Services.Agent)
+	return ListCommandsContext(context.Background(), c)
+}
+
+// ListCommandsContext is like ListCommands but honours ctx.
+func ListCommandsContext(ctx context.Context, c *client.Client) ([]string, error) {
+	return client.ListCommandsContext(ctx, c, client.Services.Agent)
 }
 
 // StatusGet fetches the status of the control-agent.
 func StatusGet(c *client.Client) (CtrlAgentStatus, error) {
-	return client.StatusGet[CtrlAgentStatus](c, client.Services.Agent)
+	return StatusGetContext(context.Background(), c)
+}
+
+// StatusGetContext is like StatusGet but honours ctx.
+func StatusGetContext(ctx context.Context, c *client.Client) (CtrlAgentStatus, error) {
+	return client.StatusGetContext[CtrlAgentStatus](ctx, c, client.Services.Agent)
 }
 
 // VersionGet fetches the version of the control-agent.
 func VersionGet(c *client.Client) (string, CtrlAgentVersion, error) {
-	return client.VersionGet[CtrlAgentVersion](c, client.Services.Agent)
+	return VersionGetContext(context.Background(), c)
+}
+
+// VersionGetContext is like VersionGet but honours ctx.
+func VersionGetContext(ctx context.Context, c *client.Client) (string, CtrlAgentVersion, error) {
+	return client.VersionGetContext[CtrlAgentVersion](ctx, c, client.Services.Agent)
 }
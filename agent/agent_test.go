@@ -1,6 +1,8 @@
 package agent
 
 import (
+	"encoding/json"
+	"os"
 	"reflect"
 	"testing"
 
@@ -93,6 +95,37 @@ func TestConfigGet(t *testing.T) {
 	}
 }
 
+// TestConfigGetRoundTrip re-encodes a config-get reply and checks that nothing was added or lost.
+func TestConfigGetRoundTrip(t *testing.T) {
+	t.Parallel()
+
+	raw, err := os.ReadFile("testdata/config-get.json")
+	if err != nil {
+		t.Fatalf("failed to read fixture: %v", err)
+	}
+	mockClient := testenv.NewMockClient(t,
+		testenv.ExpectCommand(t, "config-get"),
+		[]client.CommandResponse{{Result: client.ResultSuccess, Arguments: raw}},
+	)
+
+	cfg, err := ConfigGet(mockClient)
+	if err != nil {
+		t.Fatalf("ConfigGet() error = %v", err)
+	}
+	out, err := json.Marshal(cfg)
+	if err != nil {
+		t.Fatalf("Marshal() error = %v", err)
+	}
+	testenv.ExpectJSONEquivalent(t, out, raw)
+
+	if _, ok := cfg.ControlAgent.Extra["cert-required"]; !ok {
+		t.Errorf("ControlAgent.Extra = %v, want cert-required", cfg.ControlAgent.Extra)
+	}
+	if _, ok := cfg.ControlAgent.ControlSockets["dhcp4"].Extra["user-context"]; !ok {
+		t.Errorf("ControlSockets[dhcp4].Extra = %v, want user-context", cfg.ControlAgent.ControlSockets["dhcp4"].Extra)
+	}
+}
+
 // TestStatusGet tests the StatusGet function for the CtrlAgentStatus type.
 func TestStatusGet(t *testing.T) {
 	t.Parallel()
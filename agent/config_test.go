@@ -0,0 +1,60 @@
+package agent
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/rannday/kea-api/client"
+	"github.com/rannday/kea-api/internal/testenv"
+)
+
+// TestConfigApply checks the hash, then tests and sets the configuration without its hash.
+func TestConfigApply(t *testing.T) {
+	t.Parallel()
+
+	var cfg CtrlAgentConfig
+	if err := json.Unmarshal([]byte(`{"Control-agent": {"http-host": "127.0.0.1", "http-port": 8000}, "hash": "ABC123"}`), &cfg); err != nil {
+		t.Fatalf("failed to decode test config: %v", err)
+	}
+	sent := map[string]any{"Control-agent": json.RawMessage(`{"http-host": "127.0.0.1", "http-port": 8000}`)}
+
+	mockClient := testenv.NewSequenceMockClient(t,
+		testenv.MockStep{
+			Validate:  testenv.ExpectCommandArgs(t, "config-hash-get", nil),
+			Responses: []client.CommandResponse{{Result: client.ResultSuccess, Arguments: json.RawMessage(`{"hash": "ABC123"}`)}},
+		},
+		testenv.MockStep{
+			Validate:  testenv.ExpectCommandArgs(t, "config-test", sent),
+			Responses: []client.CommandResponse{{Result: client.ResultSuccess}},
+		},
+		testenv.MockStep{
+			Validate:  testenv.ExpectCommandArgs(t, "config-set", sent),
+			Responses: []client.CommandResponse{{Result: client.ResultSuccess, Text: "Configuration applied successfully."}},
+		},
+	)
+
+	report, err := ConfigApply(mockClient, cfg, client.ConfigApplyOptions{})
+	if err != nil {
+		t.Fatalf("ConfigApply() error = %v", err)
+	}
+	if !report.Applied || report.Written != nil || len(report.Steps) != 3 {
+		t.Errorf("ConfigApply() report = %+v", report)
+	}
+}
+
+// TestLoadConfigFile reads a commented kea-ctrl-agent.conf into the typed configuration.
+func TestLoadConfigFile(t *testing.T) {
+	t.Parallel()
+
+	cfg, err := LoadConfigFile("testdata/kea-ctrl-agent.conf")
+	if err != nil {
+		t.Fatalf("LoadConfigFile() error = %v", err)
+	}
+	a := cfg.ControlAgent
+	if a.HTTPHost != "127.0.0.1" || a.HTTPPort != 8000 {
+		t.Errorf("LoadConfigFile() http = %s:%d", a.HTTPHost, a.HTTPPort)
+	}
+	if s := a.ControlSockets["dhcp4"]; s.SocketName != "/run/kea/kea4-ctrl-socket" {
+		t.Errorf("LoadConfigFile() control-sockets = %+v", a.ControlSockets)
+	}
+}
@@ -0,0 +1,45 @@
+package agent
+
+import "github.com/rannday/kea-api/internal/utils"
+
+// JSON codecs keeping the config types of this package lossless, Extra included; see utils.DecodeObject.
+
+func (c *CtrlAgentConfig) UnmarshalJSON(data []byte) error {
+	type plain CtrlAgentConfig
+	return utils.DecodeObject(data, (*plain)(c), &c.Extra, &c.absent)
+}
+
+func (c CtrlAgentConfig) MarshalJSON() ([]byte, error) {
+	type plain CtrlAgentConfig
+	return utils.EncodeObject(plain(c), c.Extra, c.absent)
+}
+
+func (c *CtrlAgentBlock) UnmarshalJSON(data []byte) error {
+	type plain CtrlAgentBlock
+	return utils.DecodeObject(data, (*plain)(c), &c.Extra, &c.absent)
+}
+
+func (c CtrlAgentBlock) MarshalJSON() ([]byte, error) {
+	type plain CtrlAgentBlock
+	return utils.EncodeObject(plain(c), c.Extra, c.absent)
+}
+
+func (a *AuthConfig) UnmarshalJSON(data []byte) error {
+	type plain AuthConfig
+	return utils.DecodeObject(data, (*plain)(a), &a.Extra, &a.absent)
+}
+
+func (a AuthConfig) MarshalJSON() ([]byte, error) {
+	type plain AuthConfig
+	return utils.EncodeObject(plain(a), a.Extra, a.absent)
+}
+
+func (a *AuthClient) UnmarshalJSON(data []byte) error {
+	type plain AuthClient
+	return utils.DecodeObject(data, (*plain)(a), &a.Extra, &a.absent)
+}
+
+func (a AuthClient) MarshalJSON() ([]byte, error) {
+	type plain AuthClient
+	return utils.EncodeObject(plain(a), a.Extra, a.absent)
+}
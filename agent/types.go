@@ -1,6 +1,11 @@
 package agent
 
-import "github.com/rannday/kea-api/types"
+import (
+	"encoding/json"
+
+	"github.com/rannday/kea-api/internal/utils"
+	"github.com/rannday/kea-api/types"
+)
 
 // CtrlAgentStatus represents the response from "status-get" when no service is specified.
 type CtrlAgentStatus struct {
@@ -17,8 +22,10 @@ type CtrlAgentVersion struct {
 // CtrlAgentConfig is the response from "config-get" on the control-agent.
 // It contains configuration parameters and control-socket definitions.
 type CtrlAgentConfig struct {
-	ControlAgent CtrlAgentBlock `json:"Control-agent"` // Main configuration block
-	Hash         string         `json:"hash"`          // SHA256 hash of the current config
+	ControlAgent CtrlAgentBlock             `json:"Control-agent"` // Main configuration block
+	Hash         string                     `json:"hash"`          // SHA256 hash of the current config
+	Extra        map[string]json.RawMessage `json:"-"`
+	absent       utils.Absent
 }
 
 // CtrlAgentBlock describes the Control-agent settings block.
@@ -29,18 +36,24 @@ type CtrlAgentBlock struct {
 	HTTPHost       string                        `json:"http-host"`       // HTTP bind address
 	HTTPPort       int                           `json:"http-port"`       // HTTP bind port
 	Loggers        []types.LoggerConfig          `json:"loggers"`         // Logging configuration
+	Extra          map[string]json.RawMessage    `json:"-"`
+	absent         utils.Absent
 }
 
 // AuthConfig defines HTTP Basic Authentication parameters.
 type AuthConfig struct {
-	Clients   []AuthClient `json:"clients"`   // List of valid users
-	Directory string       `json:"directory"` // Directory where password file is stored
-	Realm     string       `json:"realm"`     // HTTP auth realm name
-	Type      string       `json:"type"`      // Authentication type (e.g. "basic")
+	Clients   []AuthClient               `json:"clients"`   // List of valid users
+	Directory string                     `json:"directory"` // Directory where password file is stored
+	Realm     string                     `json:"realm"`     // HTTP auth realm name
+	Type      string                     `json:"type"`      // Authentication type (e.g. "basic")
+	Extra     map[string]json.RawMessage `json:"-"`
+	absent    utils.Absent
 }
 
 // AuthClient represents a single user credential entry.
 type AuthClient struct {
-	PasswordFile string `json:"password-file"` // Filename of password hash file
-	User         string `json:"user"`          // Username
+	PasswordFile string                     `json:"password-file"` // Filename of password hash file
+	User         string                     `json:"user"`          // Username
+	Extra        map[string]json.RawMessage `json:"-"`
+	absent       utils.Absent
 }
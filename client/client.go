@@ -1,9 +1,13 @@
 package client
 
 import (
+	"bytes"
+	"context"
+	"encoding/json"
 	"errors"
 	"fmt"
 	"os"
+	"reflect"
 	"time"
 )
 
@@ -12,14 +16,96 @@ type Transport interface {
 	Call(req CommandRequest, out interface{}) error
 }
 
+// ContextTransport is a Transport that honours the deadline and cancellation of ctx.
+// Call is equivalent to CallContext with context.Background(). The transports of this
+// package implement it; calls through other transports ignore ctx.
+type ContextTransport interface {
+	Transport
+	CallContext(ctx context.Context, req CommandRequest, out interface{}) error
+}
+
+// callContext sends req through t, with ctx if t is a ContextTransport.
+func callContext(ctx context.Context, t Transport, req CommandRequest, out interface{}) error {
+	if ct, ok := t.(ContextTransport); ok {
+		return ct.CallContext(ctx, req, out)
+	}
+	return t.Call(req, out)
+}
+
+// decodeResponse unmarshals a Kea reply into out. The Control Agent answers with a list
+// holding one response per service, while daemons answering directly send a single
+// response object; when out points to a slice such an object is decoded as a list of one.
+func decodeResponse(data []byte, out interface{}) error {
+	data = bytes.TrimSpace(data)
+	if len(data) > 0 && data[0] == '{' {
+		if v := reflect.ValueOf(out); v.Kind() == reflect.Pointer && v.Elem().Kind() == reflect.Slice {
+			data = append(append([]byte{'['}, data...), ']')
+		}
+	}
+	return json.Unmarshal(data, out)
+}
+
+// checkResponses returns the *CommandError for the first failed response to req when
+// out holds a []CommandResponse, and fails if that list is empty.
+func checkResponses(req CommandRequest, out interface{}) error {
+	responses, ok := out.(*[]CommandResponse)
+	if !ok {
+		return nil
+	}
+	if len(*responses) == 0 {
+		return ErrEmptyResponse
+	}
+	for i, r := range *responses {
+		if err := commandError(req, i, r); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// TransportFunc adapts a function to the ContextTransport interface; Call runs it with
+// context.Background().
+type TransportFunc func(ctx context.Context, req CommandRequest, out interface{}) error
+
+// Call implements Transport.
+func (f TransportFunc) Call(req CommandRequest, out interface{}) error {
+	return f(context.Background(), req, out)
+}
+
+// CallContext implements ContextTransport.
+func (f TransportFunc) CallContext(ctx context.Context, req CommandRequest, out interface{}) error {
+	return f(ctx, req, out)
+}
+
 // Client routes Kea API calls to the underlying Transport.
 type Client struct {
 	transport Transport
 }
 
+// ClientOption configures a Client.
+type ClientOption func(*Client)
+
+// WithMiddleware wraps the client's transport in mw. The first middleware is the
+// outermost: it sees each call first and its result last.
+func WithMiddleware(mw ...Middleware) ClientOption {
+	return func(c *Client) {
+		c.transport = chain(c.transport, mw)
+	}
+}
+
 // NewClient returns a new Kea client using the provided transport.
-func NewClient(t Transport) *Client {
-	return &Client{transport: t}
+func NewClient(t Transport, opts ...ClientOption) *Client {
+	c := &Client{transport: t}
+	for _, opt := range opts {
+		opt(c)
+	}
+	return c
+}
+
+// With returns a client sharing c's transport with mw wrapped around it, outside any
+// middleware c already has. c itself is unchanged.
+func (c *Client) With(mw ...Middleware) *Client {
+	return &Client{transport: chain(c.transport, mw)}
 }
 
 // NewHTTP returns a Kea client using HTTP transport.
@@ -61,3 +147,9 @@ func NewSocket(network, address string, timeout ...time.Duration) (*Client, erro
 func (c *Client) Call(req CommandRequest, out interface{}) error {
 	return c.transport.Call(req, out)
 }
+
+// CallContext sends the request using the chosen transport, aborting when ctx is done.
+// If the transport is not a ContextTransport, ctx is ignored.
+func (c *Client) CallContext(ctx context.Context, req CommandRequest, out interface{}) error {
+	return callContext(ctx, c.transport, req, out)
+}
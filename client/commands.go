@@ -1,13 +1,30 @@
 package client
 
+import (
+	"context"
+	"fmt"
+
+	"github.com/rannday/kea-api/types"
+)
+
 // BuildReport fetches the build-report for a single service.
 func BuildReport(c *Client, service Service) (string, error) {
-	return CallAndExtractText(c, "build-report", service)
+	return BuildReportContext(context.Background(), c, service)
+}
+
+// BuildReportContext is like BuildReport but honours ctx.
+func BuildReportContext(ctx context.Context, c *Client, service Service) (string, error) {
+	return CallAndExtractTextContext(ctx, c, "build-report", service)
 }
 
 // BuildReportMulti fetches the build-report for multiple services.
 func BuildReportMulti(c *Client, services ...Service) ([]string, error) {
-	responses, err := CallCommand(c, "build-report", services...)
+	return BuildReportMultiContext(context.Background(), c, services...)
+}
+
+// BuildReportMultiContext is like BuildReportMulti but honours ctx.
+func BuildReportMultiContext(ctx context.Context, c *Client, services ...Service) ([]string, error) {
+	responses, err := CallCommandContext(ctx, c, "build-report", services...)
 	if err != nil {
 		return nil, err
 	}
@@ -19,44 +36,210 @@ func BuildReportMulti(c *Client, services ...Service) ([]string, error) {
 	return reports, nil
 }
 
+// BuildReportEach fetches the build-report of each service, keeping the answers of the
+// services that respond when others fail.
+func BuildReportEach(c *Client, services ...Service) (map[Service]Result[string], error) {
+	return BuildReportEachContext(context.Background(), c, services...)
+}
+
+// BuildReportEachContext is like BuildReportEach but honours ctx.
+func BuildReportEachContext(ctx context.Context, c *Client, services ...Service) (map[Service]Result[string], error) {
+	return textEach(ctx, c, "build-report", services)
+}
+
 // ConfigGet fetches the config for a service and decodes it into T.
 func ConfigGet[T any](c *Client, service Service) (T, error) {
-	return DecodeFirst[T](c, "config-get", service)
+	return ConfigGetContext[T](context.Background(), c, service)
+}
+
+// ConfigGetContext is like ConfigGet but honours ctx.
+func ConfigGetContext[T any](ctx context.Context, c *Client, service Service) (T, error) {
+	return DecodeFirstContext[T](ctx, c, "config-get", service)
 }
 
 // ConfigGetMulti fetches and decodes the configuration of multiple services into []T.
 func ConfigGetMulti[T any](c *Client, services ...Service) ([]T, error) {
-	return CallAndDecode[T](c, "config-get", services...)
+	return ConfigGetMultiContext[T](context.Background(), c, services...)
+}
+
+// ConfigGetMultiContext is like ConfigGetMulti but honours ctx.
+func ConfigGetMultiContext[T any](ctx context.Context, c *Client, services ...Service) ([]T, error) {
+	return CallAndDecodeContext[T](ctx, c, "config-get", services...)
+}
+
+// ConfigGetEach fetches and decodes the configuration of each service, keeping the
+// answers of the services that respond when others fail.
+func ConfigGetEach[T any](c *Client, services ...Service) (map[Service]Result[T], error) {
+	return ConfigGetEachContext[T](context.Background(), c, services...)
+}
+
+// ConfigGetEachContext is like ConfigGetEach but honours ctx.
+func ConfigGetEachContext[T any](ctx context.Context, c *Client, services ...Service) (map[Service]Result[T], error) {
+	return DecodeEachContext[T](ctx, c, "config-get", nil, services...)
+}
+
+// ConfigHashGet fetches the hash of the configuration a service is running.
+// The hash changes whenever the configuration is set or reloaded.
+func ConfigHashGet(c *Client, service Service) (string, error) {
+	return ConfigHashGetContext(context.Background(), c, service)
+}
+
+// ConfigHashGetContext is like ConfigHashGet but honours ctx.
+func ConfigHashGetContext(ctx context.Context, c *Client, service Service) (string, error) {
+	res, err := DecodeFirstContext[types.ConfigHash](ctx, c, "config-hash-get", service)
+	return res.Hash, err
+}
+
+// ConfigTest asks a service to validate config without applying it and returns the server's verdict.
+// config is a value such as dhcp4.Dhcp4Config; its hash member, if any, is not sent.
+func ConfigTest(c *Client, service Service, config interface{}) (string, error) {
+	return ConfigTestContext(context.Background(), c, service, config)
+}
+
+// ConfigTestContext is like ConfigTest but honours ctx.
+func ConfigTestContext(ctx context.Context, c *Client, service Service, config interface{}) (string, error) {
+	res, err := callConfig(ctx, c, "config-test", service, config)
+	if err != nil {
+		return "", err
+	}
+	return res.Text, nil
+}
+
+// ConfigSet replaces the running configuration of a service with config.
+// The change is not persisted until ConfigWrite is called.
+func ConfigSet(c *Client, service Service, config interface{}) (string, error) {
+	return ConfigSetContext(context.Background(), c, service, config)
+}
+
+// ConfigSetContext is like ConfigSet but honours ctx.
+func ConfigSetContext(ctx context.Context, c *Client, service Service, config interface{}) (string, error) {
+	res, err := callConfig(ctx, c, "config-set", service, config)
+	if err != nil {
+		return "", err
+	}
+	return res.Text, nil
+}
+
+// ConfigWrite saves the running configuration of a service to filename, or to the file
+// it was loaded from when filename is empty.
+func ConfigWrite(c *Client, service Service, filename string) (types.ConfigWriteResult, error) {
+	return ConfigWriteContext(context.Background(), c, service, filename)
+}
+
+// ConfigWriteContext is like ConfigWrite but honours ctx.
+func ConfigWriteContext(ctx context.Context, c *Client, service Service, filename string) (types.ConfigWriteResult, error) {
+	var args map[string]interface{}
+	if filename != "" {
+		args = map[string]interface{}{"filename": filename}
+	}
+	return DecodeFirstWithArgsContext[types.ConfigWriteResult](ctx, c, "config-write", args, service)
+}
+
+// ConfigReload makes a service re-read its configuration file and returns the server's message.
+func ConfigReload(c *Client, service Service) (string, error) {
+	return ConfigReloadContext(context.Background(), c, service)
+}
+
+// ConfigReloadContext is like ConfigReload but honours ctx.
+func ConfigReloadContext(ctx context.Context, c *Client, service Service) (string, error) {
+	return CallAndExtractTextContext(ctx, c, "config-reload", service)
+}
+
+// callConfig sends a command whose arguments are a full configuration, without its hash member.
+func callConfig(ctx context.Context, c *Client, cmd string, service Service, config interface{}) (CommandResponse, error) {
+	args, err := EncodeArguments(config)
+	if err != nil {
+		return CommandResponse{}, fmt.Errorf("%s: %w", cmd, err)
+	}
+	delete(args, "hash")
+
+	responses, err := CallWithArgsContext(ctx, c, cmd, args, service)
+	if err != nil {
+		return CommandResponse{}, err
+	}
+	return responses[0], nil
 }
 
 // ListCommands fetches the list of supported commands for a service.
 func ListCommands(c *Client, service Service) ([]string, error) {
-	return DecodeFirst[[]string](c, "list-commands", service)
+	return ListCommandsContext(context.Background(), c, service)
+}
+
+// ListCommandsContext is like ListCommands but honours ctx.
+func ListCommandsContext(ctx context.Context, c *Client, service Service) ([]string, error) {
+	return DecodeFirstContext[[]string](ctx, c, "list-commands", service)
 }
 
 // ListCommandsMulti fetches the list of supported commands for multiple services.
 func ListCommandsMulti(c *Client, services ...Service) ([][]string, error) {
-	return CallAndDecode[[]string](c, "list-commands", services...)
+	return ListCommandsMultiContext(context.Background(), c, services...)
+}
+
+// ListCommandsMultiContext is like ListCommandsMulti but honours ctx.
+func ListCommandsMultiContext(ctx context.Context, c *Client, services ...Service) ([][]string, error) {
+	return CallAndDecodeContext[[]string](ctx, c, "list-commands", services...)
+}
+
+// ListCommandsEach fetches the list of supported commands of each service, keeping the
+// answers of the services that respond when others fail.
+func ListCommandsEach(c *Client, services ...Service) (map[Service]Result[[]string], error) {
+	return ListCommandsEachContext(context.Background(), c, services...)
+}
+
+// ListCommandsEachContext is like ListCommandsEach but honours ctx.
+func ListCommandsEachContext(ctx context.Context, c *Client, services ...Service) (map[Service]Result[[]string], error) {
+	return DecodeEachContext[[]string](ctx, c, "list-commands", nil, services...)
 }
 
 // StatusGet fetches status information for a service and decodes into T.
 func StatusGet[T any](c *Client, service Service) (T, error) {
-	return DecodeFirst[T](c, "status-get", service)
+	return StatusGetContext[T](context.Background(), c, service)
+}
+
+// StatusGetContext is like StatusGet but honours ctx.
+func StatusGetContext[T any](ctx context.Context, c *Client, service Service) (T, error) {
+	return DecodeFirstContext[T](ctx, c, "status-get", service)
 }
 
 // StatusGetMulti fetches status information for multiple services and decodes into []T.
 func StatusGetMulti[T any](c *Client, services ...Service) ([]T, error) {
-	return CallAndDecode[T](c, "status-get", services...)
+	return StatusGetMultiContext[T](context.Background(), c, services...)
+}
+
+// StatusGetMultiContext is like StatusGetMulti but honours ctx.
+func StatusGetMultiContext[T any](ctx context.Context, c *Client, services ...Service) ([]T, error) {
+	return CallAndDecodeContext[T](ctx, c, "status-get", services...)
+}
+
+// StatusGetEach fetches the status of each service and decodes it into T, keeping the
+// answers of the services that respond when others fail.
+func StatusGetEach[T any](c *Client, services ...Service) (map[Service]Result[T], error) {
+	return StatusGetEachContext[T](context.Background(), c, services...)
+}
+
+// StatusGetEachContext is like StatusGetEach but honours ctx.
+func StatusGetEachContext[T any](ctx context.Context, c *Client, services ...Service) (map[Service]Result[T], error) {
+	return DecodeEachContext[T](ctx, c, "status-get", nil, services...)
 }
 
 // VersionGet fetches version info for a service, returning both the full text and the decoded T.
 func VersionGet[T any](c *Client, service Service) (string, T, error) {
-	return DecodeFirstWithText[T](c, "version-get", service)
+	return VersionGetContext[T](context.Background(), c, service)
+}
+
+// VersionGetContext is like VersionGet but honours ctx.
+func VersionGetContext[T any](ctx context.Context, c *Client, service Service) (string, T, error) {
+	return DecodeFirstWithTextContext[T](ctx, c, "version-get", service)
 }
 
 // VersionGetMulti fetches version info for multiple services, returning the raw text for each.
 func VersionGetMulti(c *Client, services ...Service) ([]string, error) {
-	responses, err := CallCommand(c, "version-get", services...)
+	return VersionGetMultiContext(context.Background(), c, services...)
+}
+
+// VersionGetMultiContext is like VersionGetMulti but honours ctx.
+func VersionGetMultiContext(ctx context.Context, c *Client, services ...Service) ([]string, error) {
+	responses, err := CallCommandContext(ctx, c, "version-get", services...)
 	if err != nil {
 		return nil, err
 	}
@@ -67,3 +250,15 @@ func VersionGetMulti(c *Client, services ...Service) ([]string, error) {
 	}
 	return versions, nil
 }
+
+// VersionGetEach fetches the version of each service, decoding the arguments into T and
+// keeping the full version text in Result.Text. The answers of the services that
+// respond are kept when others fail.
+func VersionGetEach[T any](c *Client, services ...Service) (map[Service]Result[T], error) {
+	return VersionGetEachContext[T](context.Background(), c, services...)
+}
+
+// VersionGetEachContext is like VersionGetEach but honours ctx.
+func VersionGetEachContext[T any](ctx context.Context, c *Client, services ...Service) (map[Service]Result[T], error) {
+	return DecodeEachContext[T](ctx, c, "version-get", nil, services...)
+}
@@ -1,6 +1,7 @@
 package client
 
 import (
+	"context"
 	"encoding/json"
 	"errors"
 	"reflect"
@@ -23,6 +24,10 @@ type mockClient struct {
 }
 
 func (m *mockClient) Call(req CommandRequest, out interface{}) error {
+	return m.CallContext(context.Background(), req, out)
+}
+
+func (m *mockClient) CallContext(ctx context.Context, req CommandRequest, out interface{}) error {
 	if m.err != nil {
 		return m.err
 	}
@@ -0,0 +1,113 @@
+package client
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/rannday/kea-api/types"
+)
+
+// ErrConfigHashMismatch is returned by ConfigApply when the configuration running on the
+// server is no longer the one the new configuration was derived from.
+var ErrConfigHashMismatch = errors.New("configuration changed on server")
+
+// ConfigApplyOptions controls ConfigApply.
+type ConfigApplyOptions struct {
+	SkipHashCheck bool   // apply even if the config carries no hash or the server's hash differs
+	Write         bool   // persist the configuration with config-write after config-set
+	Filename      string // config-write target; empty writes back to the file the server loaded
+}
+
+// ConfigApplyStep records one command run by ConfigApply.
+type ConfigApplyStep struct {
+	Command string
+	Text    string // message returned by the server
+	Err     error
+}
+
+// ConfigApplyReport describes what ConfigApply did. Steps are listed in the order they ran;
+// if ConfigApply failed, the last step carries the error.
+type ConfigApplyReport struct {
+	Service      Service
+	PreviousHash string                   // server hash before the change, unless SkipHashCheck was set
+	Hash         string                   // hash of the new configuration, if config-set reported one
+	Applied      bool                     // config-set succeeded
+	Written      *types.ConfigWriteResult // set when config-write succeeded
+	Steps        []ConfigApplyStep
+}
+
+// ConfigApply pushes a configuration obtained from ConfigGet, and then modified, back to a service:
+//   - config-hash-get, checking the server still runs the configuration whose hash config carries,
+//   - config-test, so an invalid configuration is rejected before anything changes,
+//   - config-set,
+//   - config-write, if opts.Write is set.
+//
+// The hash check narrows the window for lost updates but cannot close it, since Kea
+// has no conditional config-set.
+func ConfigApply(c *Client, service Service, config interface{}, opts ConfigApplyOptions) (ConfigApplyReport, error) {
+	return ConfigApplyContext(context.Background(), c, service, config, opts)
+}
+
+// ConfigApplyContext is like ConfigApply but honours ctx.
+func ConfigApplyContext(ctx context.Context, c *Client, service Service, config interface{}, opts ConfigApplyOptions) (ConfigApplyReport, error) {
+	report := ConfigApplyReport{Service: service}
+
+	args, err := EncodeArguments(config)
+	if err != nil {
+		return report, fmt.Errorf("config apply: %w", err)
+	}
+	expected, _ := args["hash"].(string)
+	delete(args, "hash")
+
+	step := func(cmd, text string, err error) error {
+		report.Steps = append(report.Steps, ConfigApplyStep{Command: cmd, Text: text, Err: err})
+		return err
+	}
+
+	if !opts.SkipHashCheck {
+		if expected == "" {
+			return report, errors.New("config apply: config has no hash to check against")
+		}
+		res, err := DecodeFirstContext[types.ConfigHash](ctx, c, "config-hash-get", service)
+		if err != nil {
+			return report, step("config-hash-get", "", err)
+		}
+		report.PreviousHash = res.Hash
+		if !strings.EqualFold(res.Hash, expected) {
+			err := fmt.Errorf("%w: expected hash %s, server has %s", ErrConfigHashMismatch, expected, res.Hash)
+			return report, step("config-hash-get", "", err)
+		}
+		step("config-hash-get", "", nil)
+	}
+
+	res, err := callConfig(ctx, c, "config-test", service, args)
+	if err != nil {
+		return report, step("config-test", "", err)
+	}
+	step("config-test", res.Text, nil)
+
+	res, err = callConfig(ctx, c, "config-set", service, args)
+	if err != nil {
+		return report, step("config-set", "", err)
+	}
+	report.Applied = true
+	var hash types.ConfigHash
+	if len(res.Arguments) > 0 && json.Unmarshal(res.Arguments, &hash) == nil {
+		report.Hash = hash.Hash
+	}
+	step("config-set", res.Text, nil)
+
+	if opts.Write {
+		written, err := ConfigWriteContext(ctx, c, service, opts.Filename)
+		if err != nil {
+			return report, step("config-write", "", err)
+		}
+		report.Written = &written
+		step("config-write", "", nil)
+	}
+
+	return report, nil
+}
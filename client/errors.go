@@ -0,0 +1,114 @@
+package client
+
+import (
+	"errors"
+	"fmt"
+)
+
+// Sentinel errors matched by errors.Is against a *CommandError with the
+// corresponding result code.
+var (
+	ErrUnsupported = errors.New("unsupported command")
+	ErrNotFound    = errors.New("resource not found")
+	ErrConflict    = errors.New("conflict")
+)
+
+// Sentinel errors for requests a transport refuses and replies it cannot use.
+var (
+	// ErrSingleDaemon is returned, wrapped, when a command addressed to several
+	// services is sent to an endpoint that serves one daemon only.
+	ErrSingleDaemon = errors.New("endpoint serves a single daemon")
+	// ErrEmptyResponse is returned when Kea answers with an empty list of responses.
+	ErrEmptyResponse = errors.New("empty response from Kea")
+)
+
+// CommandError is returned when Kea answers a command with a result code other than
+// ResultSuccess. Command and Service are filled in when the error is produced by a
+// transport or by CallCommand; Service is empty for the Control Agent and for
+// daemons reached through their own control socket.
+type CommandError struct {
+	Command string
+	Service Service
+	Code    ResultCode
+	Text    string
+}
+
+func (e *CommandError) Error() string {
+	switch e.Code {
+	case ResultGeneralFailure:
+		return fmt.Sprintf("general error: %s", e.Text)
+	case ResultUnsupported:
+		return fmt.Sprintf("unsupported command: %s", e.Text)
+	case ResultNotFound:
+		return fmt.Sprintf("resource not found: %s", e.Text)
+	case ResultConflict:
+		return fmt.Sprintf("conflict: %s", e.Text)
+	default:
+		return fmt.Sprintf("unknown result code %d: %s", e.Code, e.Text)
+	}
+}
+
+// Is matches ErrUnsupported, ErrNotFound and ErrConflict by result code.
+func (e *CommandError) Is(target error) bool {
+	switch target {
+	case ErrUnsupported:
+		return e.Code == ResultUnsupported
+	case ErrNotFound:
+		return e.Code == ResultNotFound
+	case ErrConflict:
+		return e.Code == ResultConflict
+	}
+	return false
+}
+
+// HTTPStatusError is returned when the server answers with a non-2xx HTTP status.
+type HTTPStatusError struct {
+	StatusCode int
+	Body       string
+}
+
+func (e *HTTPStatusError) Error() string {
+	return fmt.Sprintf("kea error: status=%d, body=%s", e.StatusCode, e.Body)
+}
+
+// TransportError is returned when a command could not be delivered or its reply
+// could not be read or decoded. Op names the failed step, such as "marshal request",
+// "connect", "send request" or "decode response"; Err is the underlying error, which may be a
+// context error when the call was cancelled.
+type TransportError struct {
+	Op  string
+	Err error
+}
+
+func (e *TransportError) Error() string { return e.Op + ": " + e.Err.Error() }
+
+func (e *TransportError) Unwrap() error { return e.Err }
+
+// IsNotFound reports whether err was caused by a Kea reply with ResultNotFound,
+// which commands such as lease4-get use to signal that nothing matched. It is
+// equivalent to errors.Is(err, ErrNotFound).
+func IsNotFound(err error) bool {
+	return errors.Is(err, ErrNotFound)
+}
+
+// emptyResponseError is ErrEmptyResponse for a command whose transport does not
+// check for empty replies itself.
+type emptyResponseError struct {
+	command string
+}
+
+func (e *emptyResponseError) Error() string { return e.command + " returned empty response" }
+
+func (e *emptyResponseError) Is(target error) bool { return target == ErrEmptyResponse }
+
+// commandError returns the error for the i-th response to req, or nil if it succeeded.
+func commandError(req CommandRequest, i int, r CommandResponse) error {
+	if r.Result == ResultSuccess {
+		return nil
+	}
+	e := &CommandError{Command: req.Command, Code: r.Result, Text: r.Text}
+	if i < len(req.Service) {
+		e.Service = req.Service[i]
+	}
+	return e
+}
@@ -0,0 +1,122 @@
+package client
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+)
+
+// TestCommandError_Is matches the sentinels by result code only.
+func TestCommandError_Is(t *testing.T) {
+	tests := []struct {
+		code ResultCode
+		want error
+	}{
+		{ResultUnsupported, ErrUnsupported},
+		{ResultNotFound, ErrNotFound},
+		{ResultConflict, ErrConflict},
+	}
+	for _, tt := range tests {
+		err := tt.code.ResultError("x")
+		for _, sentinel := range []error{ErrUnsupported, ErrNotFound, ErrConflict} {
+			if got := errors.Is(err, sentinel); got != (sentinel == tt.want) {
+				t.Errorf("errors.Is(code %d, %v) = %v", tt.code, sentinel, got)
+			}
+		}
+	}
+	if err := ResultGeneralFailure.ResultError("x"); errors.Is(err, ErrNotFound) || errors.Is(err, ErrConflict) {
+		t.Errorf("general failure matched a sentinel: %v", err)
+	}
+}
+
+// TestCommandError_HTTP identifies the failing command and service in a multi-service reply.
+func TestCommandError_HTTP(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		json.NewEncoder(w).Encode([]CommandResponse{
+			{Result: ResultSuccess},
+			{Result: ResultConflict, Text: "subnet exists"},
+		})
+	}))
+	defer srv.Close()
+
+	_, err := CallCommand(NewHTTP(srv.URL), "subnet4-add", Services.DHCP4, Services.DHCP6)
+	if !errors.Is(err, ErrConflict) {
+		t.Fatalf("CallCommand() error = %v, want ErrConflict", err)
+	}
+	var cmdErr *CommandError
+	if !errors.As(err, &cmdErr) {
+		t.Fatalf("CallCommand() error = %T, want *CommandError", err)
+	}
+	want := CommandError{Command: "subnet4-add", Service: Services.DHCP6, Code: ResultConflict, Text: "subnet exists"}
+	if *cmdErr != want {
+		t.Errorf("CommandError = %+v, want %+v", *cmdErr, want)
+	}
+	if err.Error() != "subnet4-add failed: conflict: subnet exists" {
+		t.Errorf("Error() = %q", err.Error())
+	}
+}
+
+// TestCommandError_Helpers fills in the command when the transport does not check results.
+func TestCommandError_Helpers(t *testing.T) {
+	c := NewClient(TransportFunc(func(ctx context.Context, req CommandRequest, out interface{}) error {
+		*out.(*[]CommandResponse) = []CommandResponse{{Result: ResultNotFound, Text: "no such host"}}
+		return nil
+	}))
+	_, err := CallCommand(c, "reservation-get", Services.DHCP4)
+	var cmdErr *CommandError
+	if !errors.As(err, &cmdErr) || cmdErr.Command != "reservation-get" || cmdErr.Service != Services.DHCP4 || !IsNotFound(err) {
+		t.Errorf("CallCommand() error = %v (%+v)", err, cmdErr)
+	}
+}
+
+// TestHTTPStatusError exposes the status code of a non-2xx reply.
+func TestHTTPStatusError(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		http.Error(w, "Unauthorized", http.StatusUnauthorized)
+	}))
+	defer srv.Close()
+
+	_, err := CallCommand(NewHTTP(srv.URL), "config-get", Services.DHCP4)
+	var statusErr *HTTPStatusError
+	if !errors.As(err, &statusErr) || statusErr.StatusCode != http.StatusUnauthorized || statusErr.Body != "Unauthorized\n" {
+		t.Errorf("CallCommand() error = %v, want *HTTPStatusError 401", err)
+	}
+}
+
+// TestTransportError wraps connection failures of both transports, keeping the cause.
+func TestTransportError(t *testing.T) {
+	l, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	addr := l.Addr().String()
+	l.Close()
+
+	for name, c := range map[string]*Client{
+		"http":   NewHTTP("http://" + addr),
+		"socket": NewClient(NewSocketTransport("tcp", addr, time.Second)),
+	} {
+		_, err := CallCommand(c, "status-get")
+		var trErr *TransportError
+		if !errors.As(err, &trErr) {
+			t.Errorf("%s: error = %v, want *TransportError", name, err)
+			continue
+		}
+		var opErr *net.OpError
+		if !errors.As(err, &opErr) || opErr.Op != "dial" {
+			t.Errorf("%s: TransportError %q does not wrap the dial error", name, trErr.Op)
+		}
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	_, err = CallCommandContext(ctx, NewHTTP("http://"+addr), "status-get")
+	if !errors.Is(err, context.Canceled) {
+		t.Errorf("cancelled call error = %v, want context.Canceled", err)
+	}
+}
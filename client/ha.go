@@ -0,0 +1,159 @@
+package client
+
+import (
+	"context"
+	"time"
+
+	"github.com/rannday/kea-api/types"
+)
+
+/*
+ * High Availability commands of the ha hook library, shared by kea-dhcp4 and kea-dhcp6.
+ * serverName selects the relationship on servers configured in hub-and-spoke mode and
+ * is omitted when empty; ha-sync, ha-sync-complete-notify and ha-maintenance-notify are
+ * normally sent by the partner rather than by operators.
+ */
+
+// HAStatusGet fetches the high-availability section of status-get: one entry per HA
+// relationship, or none when the ha hook library is not loaded.
+func HAStatusGet(c *Client, service Service) ([]types.HAStatus, error) {
+	return HAStatusGetContext(context.Background(), c, service)
+}
+
+// HAStatusGetContext is like HAStatusGet but honours ctx.
+func HAStatusGetContext(ctx context.Context, c *Client, service Service) ([]types.HAStatus, error) {
+	status, err := DecodeFirstContext[struct {
+		HighAvailability []types.HAStatus `json:"high-availability"`
+	}](ctx, c, "status-get", service)
+	return status.HighAvailability, err
+}
+
+// HAHeartbeat fetches the HA state of a server, as its partner sees it.
+func HAHeartbeat(c *Client, service Service, serverName string) (types.HAHeartbeat, error) {
+	return HAHeartbeatContext(context.Background(), c, service, serverName)
+}
+
+// HAHeartbeatContext is like HAHeartbeat but honours ctx.
+func HAHeartbeatContext(ctx context.Context, c *Client, service Service, serverName string) (types.HAHeartbeat, error) {
+	return DecodeFirstWithArgsContext[types.HAHeartbeat](ctx, c, "ha-heartbeat", haArgs(serverName), service)
+}
+
+// HAScopes sets the scopes a server serves in load-balancing or hot-standby mode; an
+// empty list makes it serve none.
+func HAScopes(c *Client, service Service, scopes []string, serverName string) error {
+	return HAScopesContext(context.Background(), c, service, scopes, serverName)
+}
+
+// HAScopesContext is like HAScopes but honours ctx.
+func HAScopesContext(ctx context.Context, c *Client, service Service, scopes []string, serverName string) error {
+	if scopes == nil {
+		scopes = []string{}
+	}
+	args := haArgs(serverName)
+	args["scopes"] = scopes
+	_, err := CallWithArgsContext(ctx, c, "ha-scopes", args, service)
+	return err
+}
+
+// HASync makes a server fetch the leases of its partner serverName, which is required
+// in ha-sync, pausing the partner's DHCP service for at most maxPeriod. Kea rounds
+// maxPeriod to seconds; 0 uses its default of 60 seconds.
+func HASync(c *Client, service Service, serverName string, maxPeriod time.Duration) error {
+	return HASyncContext(context.Background(), c, service, serverName, maxPeriod)
+}
+
+// HASyncContext is like HASync but honours ctx.
+func HASyncContext(ctx context.Context, c *Client, service Service, serverName string, maxPeriod time.Duration) error {
+	args := haArgs(serverName)
+	if maxPeriod > 0 {
+		args["max-period"] = int64(maxPeriod.Round(time.Second) / time.Second)
+	}
+	_, err := CallWithArgsContext(ctx, c, "ha-sync", args, service)
+	return err
+}
+
+// HASyncCompleteNotify tells a server that its partner finished synchronizing leases,
+// so it re-enables its DHCP service.
+func HASyncCompleteNotify(c *Client, service Service, serverName string) error {
+	return HASyncCompleteNotifyContext(context.Background(), c, service, serverName)
+}
+
+// HASyncCompleteNotifyContext is like HASyncCompleteNotify but honours ctx.
+func HASyncCompleteNotifyContext(ctx context.Context, c *Client, service Service, serverName string) error {
+	_, err := CallWithArgsContext(ctx, c, "ha-sync-complete-notify", haArgs(serverName), service)
+	return err
+}
+
+// HAContinue resumes the HA state machine of a server paused in a state configured
+// with pause "always" or "once".
+func HAContinue(c *Client, service Service, serverName string) error {
+	return HAContinueContext(context.Background(), c, service, serverName)
+}
+
+// HAContinueContext is like HAContinue but honours ctx.
+func HAContinueContext(ctx context.Context, c *Client, service Service, serverName string) error {
+	_, err := CallWithArgsContext(ctx, c, "ha-continue", haArgs(serverName), service)
+	return err
+}
+
+// HAMaintenanceStart moves a server to partner-in-maintenance and its partner to
+// in-maintenance, so the partner can be shut down without clients noticing. Send it to
+// the server that keeps running.
+func HAMaintenanceStart(c *Client, service Service, serverName string) error {
+	return HAMaintenanceStartContext(context.Background(), c, service, serverName)
+}
+
+// HAMaintenanceStartContext is like HAMaintenanceStart but honours ctx.
+func HAMaintenanceStartContext(ctx context.Context, c *Client, service Service, serverName string) error {
+	_, err := CallWithArgsContext(ctx, c, "ha-maintenance-start", haArgs(serverName), service)
+	return err
+}
+
+// HAMaintenanceNotify tells a server that its partner is entering maintenance, or with
+// cancel, that it left it. Servers send it to their partner on ha-maintenance-start and
+// ha-maintenance-cancel.
+func HAMaintenanceNotify(c *Client, service Service, cancel bool, serverName string) error {
+	return HAMaintenanceNotifyContext(context.Background(), c, service, cancel, serverName)
+}
+
+// HAMaintenanceNotifyContext is like HAMaintenanceNotify but honours ctx.
+func HAMaintenanceNotifyContext(ctx context.Context, c *Client, service Service, cancel bool, serverName string) error {
+	args := haArgs(serverName)
+	args["cancel"] = cancel
+	_, err := CallWithArgsContext(ctx, c, "ha-maintenance-notify", args, service)
+	return err
+}
+
+// HAMaintenanceCancel returns a server in partner-in-maintenance, and its partner, to
+// the states they had before HAMaintenanceStart.
+func HAMaintenanceCancel(c *Client, service Service, serverName string) error {
+	return HAMaintenanceCancelContext(context.Background(), c, service, serverName)
+}
+
+// HAMaintenanceCancelContext is like HAMaintenanceCancel but honours ctx.
+func HAMaintenanceCancelContext(ctx context.Context, c *Client, service Service, serverName string) error {
+	_, err := CallWithArgsContext(ctx, c, "ha-maintenance-cancel", haArgs(serverName), service)
+	return err
+}
+
+// HAReset moves a server to the waiting state, from which it resynchronizes with its
+// partner. It has no effect on a server already waiting.
+func HAReset(c *Client, service Service, serverName string) error {
+	return HAResetContext(context.Background(), c, service, serverName)
+}
+
+// HAResetContext is like HAReset but honours ctx.
+func HAResetContext(ctx context.Context, c *Client, service Service, serverName string) error {
+	_, err := CallWithArgsContext(ctx, c, "ha-reset", haArgs(serverName), service)
+	return err
+}
+
+// haArgs returns the arguments of an HA command, selecting the relationship of
+// serverName if given.
+func haArgs(serverName string) map[string]interface{} {
+	args := map[string]interface{}{}
+	if serverName != "" {
+		args["server-name"] = serverName
+	}
+	return args
+}
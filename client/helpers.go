@@ -1,6 +1,8 @@
 package client
 
 import (
+	"bytes"
+	"context"
 	"encoding/json"
 	"fmt"
 )
@@ -8,25 +10,98 @@ import (
 // CallCommand sends a command to one or more services and returns validated responses.
 // If only Services.Agent is specified or no services are passed, the request is sent to the control-agent itself.
 func CallCommand(c *Client, cmd string, services ...Service) ([]CommandResponse, error) {
-	req := CommandRequest{Command: cmd}
+	return CallCommandContext(context.Background(), c, cmd, services...)
+}
+
+// CallCommandContext is like CallCommand but aborts when ctx is cancelled or its deadline passes.
+func CallCommandContext(ctx context.Context, c *Client, cmd string, services ...Service) ([]CommandResponse, error) {
+	return callRequest(ctx, c, newRequest(cmd, nil, services))
+}
+
+// CallWithArgs sends a command with arguments and returns validated responses.
+// args may be a map[string]interface{} or any value that marshals to a JSON object; nil sends no arguments.
+func CallWithArgs(c *Client, cmd string, args interface{}, services ...Service) ([]CommandResponse, error) {
+	return CallWithArgsContext(context.Background(), c, cmd, args, services...)
+}
+
+// CallWithArgsContext is like CallWithArgs but honours ctx.
+func CallWithArgsContext(ctx context.Context, c *Client, cmd string, args interface{}, services ...Service) ([]CommandResponse, error) {
+	encoded, err := EncodeArguments(args)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", cmd, err)
+	}
+	return callRequest(ctx, c, newRequest(cmd, encoded, services))
+}
+
+// DecodeFirstWithArgs sends a command with arguments and decodes the first response into T.
+func DecodeFirstWithArgs[T any](c *Client, cmd string, args interface{}, service Service) (T, error) {
+	return DecodeFirstWithArgsContext[T](context.Background(), c, cmd, args, service)
+}
+
+// DecodeFirstWithArgsContext is like DecodeFirstWithArgs but honours ctx.
+func DecodeFirstWithArgsContext[T any](ctx context.Context, c *Client, cmd string, args interface{}, service Service) (T, error) {
+	var decoded T
+	responses, err := CallWithArgsContext(ctx, c, cmd, args, service)
+	if err != nil {
+		return decoded, err
+	}
+	if err := json.Unmarshal(responses[0].Arguments, &decoded); err != nil {
+		return decoded, fmt.Errorf("decode %s arguments: %w", cmd, err)
+	}
+	return decoded, nil
+}
+
+// EncodeArguments converts a typed argument value into the map used by CommandRequest.Arguments.
+// Numbers are preserved exactly, so 64-bit identifiers survive the round trip.
+func EncodeArguments(v interface{}) (map[string]interface{}, error) {
+	switch args := v.(type) {
+	case nil:
+		return nil, nil
+	case map[string]interface{}:
+		return args, nil
+	}
+
+	b, err := json.Marshal(v)
+	if err != nil {
+		return nil, fmt.Errorf("encode arguments: %w", err)
+	}
+
+	var out map[string]interface{}
+	dec := json.NewDecoder(bytes.NewReader(b))
+	dec.UseNumber()
+	if err := dec.Decode(&out); err != nil {
+		return nil, fmt.Errorf("encode arguments: %w", err)
+	}
+	return out, nil
+}
+
+// newRequest builds a CommandRequest for the given services.
+func newRequest(cmd string, args map[string]interface{}, services []Service) CommandRequest {
+	req := CommandRequest{Command: cmd, Arguments: args}
 
 	// Only set the "service" field if not targeting the control agent directly
 	if len(services) > 0 && !(len(services) == 1 && services[0] == Services.Agent) {
 		req.Service = services
 	}
+	return req
+}
+
+// callRequest sends req and validates that every response succeeded.
+func callRequest(ctx context.Context, c *Client, req CommandRequest) ([]CommandResponse, error) {
+	cmd := req.Command
 
 	var res []CommandResponse
-	if err := c.Call(req, &res); err != nil {
+	if err := c.CallContext(ctx, req, &res); err != nil {
 		return nil, fmt.Errorf("%s failed: %w", cmd, err)
 	}
 
 	if len(res) == 0 {
-		return nil, fmt.Errorf("%s returned empty response", cmd)
+		return nil, &emptyResponseError{command: cmd}
 	}
 
-	for _, r := range res {
-		if r.Result != ResultSuccess {
-			return nil, r.Result.ResultError(r.Text)
+	for i, r := range res {
+		if err := commandError(req, i, r); err != nil {
+			return nil, err
 		}
 	}
 
@@ -35,17 +110,27 @@ func CallCommand(c *Client, cmd string, services ...Service) ([]CommandResponse,
 
 // CallAndExtractText sends a command and returns only the .Text from the first successful response.
 func CallAndExtractText(c *Client, cmd string, service Service) (string, error) {
-  responses, err := CallCommand(c, cmd, service)
-  if err != nil {
-    return "", err
-  }
+	return CallAndExtractTextContext(context.Background(), c, cmd, service)
+}
 
-  return responses[0].Text, nil
+// CallAndExtractTextContext is like CallAndExtractText but honours ctx.
+func CallAndExtractTextContext(ctx context.Context, c *Client, cmd string, service Service) (string, error) {
+	responses, err := CallCommandContext(ctx, c, cmd, service)
+	if err != nil {
+		return "", err
+	}
+
+	return responses[0].Text, nil
 }
 
 // CallAndDecode sends a command and decodes all successful responses into a slice of T.
 func CallAndDecode[T any](c *Client, cmd string, services ...Service) ([]T, error) {
-	responses, err := CallCommand(c, cmd, services...)
+	return CallAndDecodeContext[T](context.Background(), c, cmd, services...)
+}
+
+// CallAndDecodeContext is like CallAndDecode but honours ctx.
+func CallAndDecodeContext[T any](ctx context.Context, c *Client, cmd string, services ...Service) ([]T, error) {
+	responses, err := CallCommandContext(ctx, c, cmd, services...)
 	if err != nil {
 		return nil, err
 	}
@@ -64,7 +149,12 @@ func CallAndDecode[T any](c *Client, cmd string, services ...Service) ([]T, erro
 
 // DecodeFirst sends a command and decodes the first response into T.
 func DecodeFirst[T any](c *Client, cmd string, service Service) (T, error) {
-	vals, err := CallAndDecode[T](c, cmd, service)
+	return DecodeFirstContext[T](context.Background(), c, cmd, service)
+}
+
+// DecodeFirstContext is like DecodeFirst but honours ctx.
+func DecodeFirstContext[T any](ctx context.Context, c *Client, cmd string, service Service) (T, error) {
+	vals, err := CallAndDecodeContext[T](ctx, c, cmd, service)
 	if err != nil {
 		var zero T
 		return zero, err
@@ -74,7 +164,12 @@ func DecodeFirst[T any](c *Client, cmd string, service Service) (T, error) {
 
 // DecodeFirstWithText sends a command and decodes the first response into T, also returning the text field.
 func DecodeFirstWithText[T any](c *Client, cmd string, service Service) (string, T, error) {
-	responses, err := CallCommand(c, cmd, service)
+	return DecodeFirstWithTextContext[T](context.Background(), c, cmd, service)
+}
+
+// DecodeFirstWithTextContext is like DecodeFirstWithText but honours ctx.
+func DecodeFirstWithTextContext[T any](ctx context.Context, c *Client, cmd string, service Service) (string, T, error) {
+	responses, err := CallCommandContext(ctx, c, cmd, service)
 	if err != nil {
 		var zero T
 		return "", zero, err
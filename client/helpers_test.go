@@ -1,6 +1,7 @@
 package client
 
 import (
+	"context"
 	"encoding/json"
 	"errors"
 	"strings"
@@ -35,7 +36,7 @@ func TestCallCommand_Error(t *testing.T) {
 func TestCallCommand_Empty(t *testing.T) {
 	client := newMockClient([]CommandResponse{}, nil)
 	_, err := CallCommand(client, "empty-reply", "svc")
-	if err == nil || err.Error() != "empty-reply returned empty response" {
+	if err == nil || err.Error() != "empty-reply returned empty response" || !errors.Is(err, ErrEmptyResponse) {
 		t.Errorf("expected empty response error, got %v", err)
 	}
 }
@@ -51,6 +52,61 @@ func TestCallCommand_BadResult(t *testing.T) {
 	}
 }
 
+// TestCallCommandContext verifies the context is passed through to the transport.
+func TestCallCommandContext(t *testing.T) {
+	var seen context.Context
+	client := NewClient(&ctxRecorder{seen: &seen})
+
+	type key struct{}
+	ctx := context.WithValue(context.Background(), key{}, "marker")
+	if _, err := CallCommandContext(ctx, client, "status-get", "svc"); err != nil {
+		t.Fatalf("CallCommandContext() error = %v", err)
+	}
+	if seen == nil || seen.Value(key{}) != "marker" {
+		t.Errorf("transport did not receive caller context")
+	}
+}
+
+// ctxRecorder is a Transport that records the context it was called with.
+type ctxRecorder struct {
+	seen *context.Context
+}
+
+func (r *ctxRecorder) Call(req CommandRequest, out interface{}) error {
+	return r.CallContext(context.Background(), req, out)
+}
+
+func (r *ctxRecorder) CallContext(ctx context.Context, req CommandRequest, out interface{}) error {
+	*r.seen = ctx
+	*out.(*[]CommandResponse) = []CommandResponse{{Result: ResultSuccess}}
+	return nil
+}
+
+// callOnly is a Transport without CallContext.
+type callOnly struct {
+	calls int
+}
+
+func (c *callOnly) Call(req CommandRequest, out interface{}) error {
+	c.calls++
+	*out.(*[]CommandResponse) = []CommandResponse{{Result: ResultSuccess, Text: req.Command}}
+	return nil
+}
+
+// TestCallCommandContext_CallOnly verifies transports implementing only Call still
+// work through the context-aware path and middleware.
+func TestCallCommandContext_CallOnly(t *testing.T) {
+	tr := &callOnly{}
+	client := NewClient(tr, WithMiddleware(RequestID(nil)))
+	res, err := CallCommandContext(context.Background(), client, "status-get", "svc")
+	if err != nil {
+		t.Fatalf("CallCommandContext() error = %v", err)
+	}
+	if tr.calls != 1 || len(res) != 1 || res[0].Text != "status-get" {
+		t.Errorf("calls = %d, res = %+v", tr.calls, res)
+	}
+}
+
 // TestCallAndDecode verifies arguments are decoded into a typed slice.
 func TestCallAndDecode(t *testing.T) {
 	client := newMockClient([]CommandResponse{
@@ -211,3 +267,73 @@ func TestCallAndExtractText_Error(t *testing.T) {
 		t.Errorf("expected empty response error, got %v", err)
 	}
 }
+
+// TestCallWithArgs verifies arguments are sent and responses validated.
+func TestCallWithArgs(t *testing.T) {
+	var got CommandRequest
+	client := NewClient(&reqRecorder{req: &got, responses: []CommandResponse{{Result: ResultSuccess}}})
+
+	type args struct {
+		IP string `json:"ip-address"`
+	}
+	if _, err := CallWithArgs(client, "lease4-get", args{IP: "192.0.2.1"}, Services.DHCP4); err != nil {
+		t.Fatalf("CallWithArgs() error = %v", err)
+	}
+	if got.Command != "lease4-get" || got.Arguments["ip-address"] != "192.0.2.1" {
+		t.Errorf("unexpected request: %+v", got)
+	}
+	if len(got.Service) != 1 || got.Service[0] != Services.DHCP4 {
+		t.Errorf("unexpected service: %v", got.Service)
+	}
+}
+
+// TestCallWithArgs_EncodeError verifies unencodable arguments are rejected before sending.
+func TestCallWithArgs_EncodeError(t *testing.T) {
+	client := newMockClient(nil, errors.New("should not be called"))
+	_, err := CallWithArgs(client, "lease4-get", struct{ C chan int }{}, Services.DHCP4)
+	if err == nil || !strings.Contains(err.Error(), "lease4-get: encode arguments") {
+		t.Errorf("expected encode error, got %v", err)
+	}
+}
+
+// TestDecodeFirstWithArgs_NotFound verifies that result 3 can be detected with IsNotFound.
+func TestDecodeFirstWithArgs_NotFound(t *testing.T) {
+	client := newMockClient([]CommandResponse{{Result: ResultNotFound, Text: "Lease not found."}}, nil)
+	_, err := DecodeFirstWithArgs[map[string]any](client, "lease4-get", nil, Services.DHCP4)
+	if !IsNotFound(err) {
+		t.Errorf("expected not found error, got %v", err)
+	}
+	if IsNotFound(errors.New("resource not found: fake")) {
+		t.Error("IsNotFound matched a plain error")
+	}
+}
+
+// TestEncodeArguments_PreservesNumbers verifies large integers are not rounded through float64.
+func TestEncodeArguments_PreservesNumbers(t *testing.T) {
+	got, err := EncodeArguments(struct {
+		ID uint64 `json:"id"`
+	}{ID: 1<<63 + 1})
+	if err != nil {
+		t.Fatalf("EncodeArguments() error = %v", err)
+	}
+	b, _ := json.Marshal(got)
+	if string(b) != `{"id":9223372036854775809}` {
+		t.Errorf("EncodeArguments() = %s", b)
+	}
+}
+
+// reqRecorder is a Transport that records the request it was called with.
+type reqRecorder struct {
+	req       *CommandRequest
+	responses []CommandResponse
+}
+
+func (r *reqRecorder) Call(req CommandRequest, out interface{}) error {
+	return r.CallContext(context.Background(), req, out)
+}
+
+func (r *reqRecorder) CallContext(ctx context.Context, req CommandRequest, out interface{}) error {
+	*r.req = req
+	*out.(*[]CommandResponse) = r.responses
+	return nil
+}
@@ -2,6 +2,7 @@ package client
 
 import (
 	"bytes"
+	"context"
 	"encoding/json"
 	"fmt"
 	"io"
@@ -14,6 +15,7 @@ type HTTPTransport struct {
 	endpoint   string
 	httpClient *http.Client
 	auth       AuthProvider
+	direct     bool
 }
 
 // HTTPOption configures an HTTPTransport.
@@ -31,6 +33,15 @@ func WithAuth(auth AuthProvider) HTTPOption {
 	}
 }
 
+// WithDirect talks to a daemon's own HTTP control socket (Kea 2.7 and later) instead of
+// the Control Agent. Such endpoints serve a single daemon and reject the service field,
+// so it is dropped from requests; a request naming more than one service fails.
+func WithDirect() HTTPOption {
+	return func(t *HTTPTransport) {
+		t.direct = true
+	}
+}
+
 // NewHTTPTransport returns a configured HTTPTransport.
 func NewHTTPTransport(endpoint string, opts ...HTTPOption) *HTTPTransport {
 	t := &HTTPTransport{
@@ -45,49 +56,57 @@ func NewHTTPTransport(endpoint string, opts ...HTTPOption) *HTTPTransport {
 
 // Call implements the Transport interface for HTTP.
 func (t *HTTPTransport) Call(req CommandRequest, out interface{}) error {
+	return t.CallContext(context.Background(), req, out)
+}
+
+// CallContext implements the ContextTransport interface for HTTP.
+// The request is bound to ctx, so cancelling it aborts the dial, write and read.
+func (t *HTTPTransport) CallContext(ctx context.Context, req CommandRequest, out interface{}) error {
+	orig := req
+	if t.direct {
+		if len(req.Service) > 1 {
+			return fmt.Errorf("direct %w, cannot send %s to %d services", ErrSingleDaemon, req.Command, len(req.Service))
+		}
+		req.Service = nil
+	}
+
 	body, err := json.Marshal(req)
 	if err != nil {
-		return fmt.Errorf("marshal request: %w", err)
+		return &TransportError{Op: "marshal request", Err: err}
 	}
 
-	httpReq, err := http.NewRequest("POST", t.endpoint, bytes.NewReader(body))
+	httpReq, err := http.NewRequestWithContext(ctx, "POST", t.endpoint, bytes.NewReader(body))
 	if err != nil {
-		return fmt.Errorf("create request: %w", err)
+		return &TransportError{Op: "create request", Err: err}
 	}
 	httpReq.Header.Set("Content-Type", "application/json")
+	if id, ok := RequestIDFromContext(ctx); ok {
+		httpReq.Header.Set("X-Request-ID", id)
+	}
 
 	if t.auth != nil {
 		if err := t.auth.Apply(httpReq); err != nil {
-			return fmt.Errorf("apply auth: %w", err)
+			return &TransportError{Op: "apply auth", Err: err}
 		}
 	}
 
 	resp, err := t.httpClient.Do(httpReq)
 	if err != nil {
-		return fmt.Errorf("send request: %w", err)
+		return &TransportError{Op: "send request", Err: err}
 	}
 	defer resp.Body.Close()
 
 	if resp.StatusCode >= 300 {
 		body, _ := io.ReadAll(resp.Body)
-		return fmt.Errorf("kea error: status=%d, body=%s", resp.StatusCode, string(body))
+		return &HTTPStatusError{StatusCode: resp.StatusCode, Body: string(body)}
 	}
 
-	decoder := json.NewDecoder(resp.Body)
-	if err := decoder.Decode(out); err != nil {
-		return fmt.Errorf("unmarshal response: %w", err)
+	data, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return &TransportError{Op: "read response", Err: err}
 	}
-
-	if responses, ok := out.(*[]CommandResponse); ok {
-		if len(*responses) == 0 {
-			return fmt.Errorf("empty response from Kea")
-		}
-		for _, r := range *responses {
-			if err := r.Result.ResultError(r.Text); err != nil {
-				return err
-			}
-		}
+	if err := decodeResponse(data, out); err != nil {
+		return &TransportError{Op: "unmarshal response", Err: err}
 	}
-
-	return nil
+	return checkResponses(orig, out)
 }
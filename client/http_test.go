@@ -2,11 +2,13 @@ package client
 
 import (
 	"bytes"
+	"context"
 	"encoding/json"
 	"errors"
 	"net/http"
 	"net/http/httptest"
 	"testing"
+	"time"
 )
 
 // TestWithHTTPClientOption verifies that WithHTTPClient sets the custom HTTP client.
@@ -30,7 +32,8 @@ func TestHTTPTransport_ApplyAuthError(t *testing.T) {
 	var out []CommandResponse
 	err := tp.Call(CommandRequest{Command: "status-get"}, &out)
 
-	if err == nil || err.Error() != "apply auth: boom" {
+	var trErr *TransportError
+	if err == nil || err.Error() != "apply auth: boom" || !errors.As(err, &trErr) {
 		t.Errorf("expected auth error, got %v", err)
 	}
 }
@@ -64,7 +67,8 @@ func TestHTTPTransport_RequestFail(t *testing.T) {
 	var out []CommandResponse
 	err := tp.Call(CommandRequest{Command: "status-get"}, &out)
 
-	if err == nil || !contains(err.Error(), "create request") {
+	var trErr *TransportError
+	if err == nil || !contains(err.Error(), "create request") || !errors.As(err, &trErr) {
 		t.Errorf("expected request creation error, got %v", err)
 	}
 }
@@ -113,7 +117,8 @@ func TestHTTPTransport_MarshalFail(t *testing.T) {
 	}
 
 	err := tp.Call(req, &out)
-	if err == nil || !contains(err.Error(), "marshal request") {
+	var trErr *TransportError
+	if err == nil || !contains(err.Error(), "marshal request") || !errors.As(err, &trErr) {
 		t.Errorf("expected marshal error, got: %v", err)
 	}
 }
@@ -173,11 +178,127 @@ func TestHTTPTransport_EmptyResponse(t *testing.T) {
 	var out []CommandResponse
 	err := tp.Call(CommandRequest{Command: "status-get"}, &out)
 
-	if err == nil || err.Error() != "empty response from Kea" {
+	if err == nil || err.Error() != "empty response from Kea" || !errors.Is(err, ErrEmptyResponse) {
 		t.Errorf("expected empty response error, got %v", err)
 	}
 }
 
+// TestHTTPTransport_ContextDeadline checks that a context deadline aborts a slow request.
+func TestHTTPTransport_ContextDeadline(t *testing.T) {
+	release := make(chan struct{})
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		select {
+		case <-release:
+		case <-r.Context().Done():
+		}
+	}))
+	defer srv.Close()
+	defer close(release)
+
+	ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+	defer cancel()
+
+	tp := NewHTTPTransport(srv.URL)
+	var out []CommandResponse
+	err := tp.CallContext(ctx, CommandRequest{Command: "status-get"}, &out)
+
+	if !errors.Is(err, context.DeadlineExceeded) {
+		t.Errorf("expected deadline exceeded, got %v", err)
+	}
+}
+
+// TestHTTPTransport_ContextCanceled checks that an already cancelled context is honoured.
+func TestHTTPTransport_ContextCanceled(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		t.Error("request should not reach the server")
+	}))
+	defer srv.Close()
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	tp := NewHTTPTransport(srv.URL)
+	var out []CommandResponse
+	err := tp.CallContext(ctx, CommandRequest{Command: "status-get"}, &out)
+
+	if !errors.Is(err, context.Canceled) {
+		t.Errorf("expected context canceled, got %v", err)
+	}
+}
+
+// TestHTTPTransport_Direct drops the service field and accepts a single response object.
+func TestHTTPTransport_Direct(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var body map[string]interface{}
+		if err := json.NewDecoder(r.Body).Decode(&body); err != nil {
+			t.Errorf("decode request: %v", err)
+			http.Error(w, "bad request", http.StatusBadRequest)
+			return
+		}
+		if _, ok := body["service"]; ok {
+			t.Errorf("direct request carries service: %v", body)
+		}
+		w.Header().Set("Content-Type", "application/json")
+		w.Write([]byte(`{"result": 0, "text": "ok", "arguments": {"pid": 42}}`))
+	}))
+	defer srv.Close()
+
+	c := NewHTTP(srv.URL, WithDirect())
+	resp, err := CallCommand(c, "status-get", Services.DHCP4)
+	if err != nil {
+		t.Fatalf("CallCommand() error = %v", err)
+	}
+	if len(resp) != 1 || resp[0].Text != "ok" || string(resp[0].Arguments) != `{"pid": 42}` {
+		t.Errorf("CallCommand() = %+v", resp)
+	}
+}
+
+// TestHTTPTransport_DirectFailure reports the result of a single response object.
+func TestHTTPTransport_DirectFailure(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte(`{"result": 2, "text": "'status-xyz' command not supported."}`))
+	}))
+	defer srv.Close()
+
+	tp := NewHTTPTransport(srv.URL, WithDirect())
+	var out []CommandResponse
+	err := tp.Call(CommandRequest{Command: "status-xyz"}, &out)
+	if err == nil || !contains(err.Error(), "unsupported command") {
+		t.Errorf("expected unsupported command error, got %v", err)
+	}
+}
+
+// TestHTTPTransport_DirectMultipleServices refuses to fan a direct request out to several daemons.
+func TestHTTPTransport_DirectMultipleServices(t *testing.T) {
+	tp := NewHTTPTransport("http://127.0.0.1:65534", WithDirect())
+	var out []CommandResponse
+	err := tp.Call(CommandRequest{Command: "config-get", Service: []Service{Services.DHCP4, Services.DHCP6}}, &out)
+	if err == nil || !contains(err.Error(), "single daemon") || !errors.Is(err, ErrSingleDaemon) {
+		t.Errorf("expected single daemon error, got %v", err)
+	}
+}
+
+// TestHTTPTransport_AgentKeepsService sends the service field to the Control Agent.
+func TestHTTPTransport_AgentKeepsService(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var req CommandRequest
+		if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+			t.Errorf("decode request: %v", err)
+			http.Error(w, "bad request", http.StatusBadRequest)
+			return
+		}
+		if len(req.Service) != 1 || req.Service[0] != Services.DHCP4 {
+			t.Errorf("request service = %v, want [dhcp4]", req.Service)
+		}
+		json.NewEncoder(w).Encode([]CommandResponse{{Result: ResultSuccess}})
+	}))
+	defer srv.Close()
+
+	if _, err := CallCommand(NewHTTP(srv.URL), "status-get", Services.DHCP4); err != nil {
+		t.Fatalf("CallCommand() error = %v", err)
+	}
+}
+
 // helper for inline AuthProvider mocks
 type AuthProviderFunc func(*http.Request) error
 
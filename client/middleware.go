@@ -0,0 +1,129 @@
+package client
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"fmt"
+	"log/slog"
+	"runtime/debug"
+	"time"
+)
+
+// Middleware wraps a Transport with cross-cutting behaviour such as logging or
+// retries. It is installed with WithMiddleware or Client.With and applies to every
+// call made through the client, including the agent, dhcp4 and dhcp6 wrappers.
+type Middleware func(next Transport) Transport
+
+// chain wraps t in mw, the first middleware outermost.
+func chain(t Transport, mw []Middleware) Transport {
+	for i := len(mw) - 1; i >= 0; i-- {
+		t = mw[i](t)
+	}
+	return t
+}
+
+type requestIDKey struct{}
+
+// WithRequestID returns a context carrying id as the request ID of calls made with it.
+func WithRequestID(ctx context.Context, id string) context.Context {
+	return context.WithValue(ctx, requestIDKey{}, id)
+}
+
+// RequestIDFromContext returns the request ID stored by WithRequestID or the RequestID middleware.
+func RequestIDFromContext(ctx context.Context) (string, bool) {
+	id, ok := ctx.Value(requestIDKey{}).(string)
+	return id, ok && id != ""
+}
+
+// RequestID gives each call a request ID, unless its context already has one. The ID
+// is available to inner middleware through RequestIDFromContext, is logged by Logging
+// and is sent by HTTPTransport as the X-Request-ID header; Kea itself ignores it.
+// newID generates IDs; nil uses 16 random hex digits.
+func RequestID(newID func() string) Middleware {
+	if newID == nil {
+		newID = randomID
+	}
+	return func(next Transport) Transport {
+		return TransportFunc(func(ctx context.Context, req CommandRequest, out interface{}) error {
+			if _, ok := RequestIDFromContext(ctx); !ok {
+				ctx = WithRequestID(ctx, newID())
+			}
+			return callContext(ctx, next, req, out)
+		})
+	}
+}
+
+func randomID() string {
+	var b [8]byte
+	_, _ = rand.Read(b[:])
+	return hex.EncodeToString(b[:])
+}
+
+// Logging logs each call with its command, services, request ID and duration: at
+// debug level when it succeeds and at error level when it fails. A nil logger uses
+// slog.Default().
+func Logging(logger *slog.Logger) Middleware {
+	if logger == nil {
+		logger = slog.Default()
+	}
+	return func(next Transport) Transport {
+		return TransportFunc(func(ctx context.Context, req CommandRequest, out interface{}) error {
+			start := time.Now()
+			err := callContext(ctx, next, req, out)
+
+			attrs := []slog.Attr{
+				slog.String("command", req.Command),
+				slog.Any("service", req.Service),
+				slog.Duration("duration", time.Since(start)),
+			}
+			if id, ok := RequestIDFromContext(ctx); ok {
+				attrs = append(attrs, slog.String("request_id", id))
+			}
+			if err != nil {
+				logger.LogAttrs(ctx, slog.LevelError, "kea command failed", append(attrs, slog.Any("error", err))...)
+			} else {
+				logger.LogAttrs(ctx, slog.LevelDebug, "kea command", attrs...)
+			}
+			return err
+		})
+	}
+}
+
+// Timing reports the duration and outcome of each call to observe, e.g. to feed a
+// latency histogram.
+func Timing(observe func(req CommandRequest, d time.Duration, err error)) Middleware {
+	return func(next Transport) Transport {
+		return TransportFunc(func(ctx context.Context, req CommandRequest, out interface{}) error {
+			start := time.Now()
+			err := callContext(ctx, next, req, out)
+			observe(req, time.Since(start), err)
+			return err
+		})
+	}
+}
+
+// PanicError is returned by the Recover middleware when a call panicked.
+type PanicError struct {
+	Command string
+	Value   interface{} // the value passed to panic
+	Stack   []byte      // stack trace of the panicking goroutine
+}
+
+func (e *PanicError) Error() string {
+	return fmt.Sprintf("panic during %s: %v", e.Command, e.Value)
+}
+
+// Recover turns a panic in the inner middleware or transport into a *PanicError.
+func Recover() Middleware {
+	return func(next Transport) Transport {
+		return TransportFunc(func(ctx context.Context, req CommandRequest, out interface{}) (err error) {
+			defer func() {
+				if r := recover(); r != nil {
+					err = &PanicError{Command: req.Command, Value: r, Stack: debug.Stack()}
+				}
+			}()
+			return callContext(ctx, next, req, out)
+		})
+	}
+}
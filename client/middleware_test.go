@@ -0,0 +1,143 @@
+package client
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"log/slog"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+)
+
+// okTransport answers every command with a single success response.
+var okTransport = TransportFunc(func(ctx context.Context, req CommandRequest, out interface{}) error {
+	*out.(*[]CommandResponse) = []CommandResponse{{Result: ResultSuccess, Text: req.Command}}
+	return nil
+})
+
+// TestWithMiddleware_Order runs the first middleware outermost.
+func TestWithMiddleware_Order(t *testing.T) {
+	var trace []string
+	record := func(name string) Middleware {
+		return func(next Transport) Transport {
+			return TransportFunc(func(ctx context.Context, req CommandRequest, out interface{}) error {
+				trace = append(trace, name+" in")
+				err := callContext(ctx, next, req, out)
+				trace = append(trace, name+" out")
+				return err
+			})
+		}
+	}
+
+	c := NewClient(okTransport, WithMiddleware(record("a"), record("b")))
+	if _, err := CallCommand(c, "status-get", Services.DHCP4); err != nil {
+		t.Fatalf("CallCommand() error = %v", err)
+	}
+	if got := strings.Join(trace, ", "); got != "a in, b in, b out, a out" {
+		t.Errorf("trace = %s", got)
+	}
+
+	trace = nil
+	outer := c.With(record("c"))
+	if _, err := CallCommand(outer, "status-get"); err != nil {
+		t.Fatalf("CallCommand() error = %v", err)
+	}
+	if got := strings.Join(trace, ", "); got != "c in, a in, b in, b out, a out, c out" {
+		t.Errorf("With() trace = %s", got)
+	}
+
+	trace = nil
+	if _, err := CallCommand(c, "status-get"); err != nil || len(trace) != 4 {
+		t.Errorf("With() changed the original client: trace = %v, err = %v", trace, err)
+	}
+}
+
+// TestRequestID sends a generated request ID as a header and keeps one already set.
+func TestRequestID(t *testing.T) {
+	var got []string
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		got = append(got, r.Header.Get("X-Request-ID"))
+		json.NewEncoder(w).Encode([]CommandResponse{{Result: ResultSuccess}})
+	}))
+	defer srv.Close()
+
+	c := NewClient(NewHTTPTransport(srv.URL), WithMiddleware(RequestID(func() string { return "generated" })))
+	if _, err := CallCommand(c, "status-get"); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := CallCommandContext(WithRequestID(context.Background(), "mine"), c, "status-get"); err != nil {
+		t.Fatal(err)
+	}
+	if len(got) != 2 || got[0] != "generated" || got[1] != "mine" {
+		t.Errorf("X-Request-ID headers = %v", got)
+	}
+}
+
+// TestLogging logs successful calls at debug level and failures at error level.
+func TestLogging(t *testing.T) {
+	var buf bytes.Buffer
+	logger := slog.New(slog.NewJSONHandler(&buf, &slog.HandlerOptions{Level: slog.LevelDebug}))
+	failing := TransportFunc(func(ctx context.Context, req CommandRequest, out interface{}) error {
+		return errors.New("boom")
+	})
+
+	ctx := WithRequestID(context.Background(), "req-1")
+	_, _ = CallCommandContext(ctx, NewClient(okTransport, WithMiddleware(Logging(logger))), "status-get", Services.DHCP4)
+	_, _ = CallCommand(NewClient(failing, WithMiddleware(Logging(logger))), "config-get")
+
+	var records []map[string]interface{}
+	dec := json.NewDecoder(&buf)
+	for dec.More() {
+		var r map[string]interface{}
+		if err := dec.Decode(&r); err != nil {
+			t.Fatal(err)
+		}
+		records = append(records, r)
+	}
+	if len(records) != 2 {
+		t.Fatalf("logged %d records, want 2", len(records))
+	}
+	if r := records[0]; r["level"] != "DEBUG" || r["command"] != "status-get" || r["request_id"] != "req-1" {
+		t.Errorf("success record = %v", r)
+	}
+	if r := records[1]; r["level"] != "ERROR" || r["command"] != "config-get" || r["error"] != "boom" {
+		t.Errorf("failure record = %v", r)
+	}
+}
+
+// TestTiming reports each call's duration and error.
+func TestTiming(t *testing.T) {
+	var calls []string
+	c := NewClient(okTransport, WithMiddleware(Timing(func(req CommandRequest, d time.Duration, err error) {
+		if d < 0 || err != nil {
+			t.Errorf("observed %v, %v", d, err)
+		}
+		calls = append(calls, req.Command)
+	})))
+	if _, err := CallCommand(c, "version-get"); err != nil {
+		t.Fatal(err)
+	}
+	if len(calls) != 1 || calls[0] != "version-get" {
+		t.Errorf("observed calls = %v", calls)
+	}
+}
+
+// TestRecover turns a panicking transport into a *PanicError.
+func TestRecover(t *testing.T) {
+	panicking := TransportFunc(func(ctx context.Context, req CommandRequest, out interface{}) error {
+		panic("kaboom")
+	})
+	_, err := CallCommand(NewClient(panicking, WithMiddleware(Recover())), "status-get")
+
+	var perr *PanicError
+	if !errors.As(err, &perr) {
+		t.Fatalf("expected *PanicError, got %v", err)
+	}
+	if perr.Command != "status-get" || perr.Value != "kaboom" || len(perr.Stack) == 0 {
+		t.Errorf("PanicError = %+v", perr)
+	}
+}
@@ -0,0 +1,106 @@
+package client
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+)
+
+// Result is the outcome of a command for one service of a multi-service call: the
+// decoded arguments and text of a successful response, or the error for that service.
+type Result[T any] struct {
+	Value T
+	Text  string
+	Err   error
+}
+
+// CallEach sends a command to several services and returns each service's response
+// separately, keyed by service. Unlike CallCommand, a failure reported by one service
+// does not discard the answers of the others: it is returned as that service's
+// *CommandError. The error return is reserved for failures of the request as a whole,
+// such as an unreachable Control Agent. With no services, or only Services.Agent, the
+// single response is keyed by Services.Agent.
+//
+// The responses are requested as raw JSON, so the transport leaves result codes to
+// CallEach; transports that only handle *[]CommandResponse cannot be used.
+func CallEach(c *Client, cmd string, args interface{}, services ...Service) (map[Service]Result[CommandResponse], error) {
+	return CallEachContext(context.Background(), c, cmd, args, services...)
+}
+
+// CallEachContext is like CallEach but honours ctx.
+func CallEachContext(ctx context.Context, c *Client, cmd string, args interface{}, services ...Service) (map[Service]Result[CommandResponse], error) {
+	encoded, err := EncodeArguments(args)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", cmd, err)
+	}
+	req := newRequest(cmd, encoded, services)
+
+	var raw []json.RawMessage
+	if err := c.CallContext(ctx, req, &raw); err != nil {
+		return nil, fmt.Errorf("%s failed: %w", cmd, err)
+	}
+
+	targets := req.Service
+	if len(targets) == 0 {
+		targets = []Service{Services.Agent}
+	}
+
+	results := make(map[Service]Result[CommandResponse], len(targets))
+	for i, service := range targets {
+		if i >= len(raw) {
+			results[service] = Result[CommandResponse]{Err: fmt.Errorf("%s: no response from %q", cmd, service)}
+			continue
+		}
+		var r CommandResponse
+		if err := json.Unmarshal(raw[i], &r); err != nil {
+			results[service] = Result[CommandResponse]{Err: fmt.Errorf("decode %s response: %w", cmd, err)}
+			continue
+		}
+		results[service] = Result[CommandResponse]{Value: r, Text: r.Text, Err: commandError(req, i, r)}
+	}
+	return results, nil
+}
+
+// DecodeEach is like CallEach but decodes the arguments of each successful response
+// into T. A response without arguments leaves Value at its zero value.
+func DecodeEach[T any](c *Client, cmd string, args interface{}, services ...Service) (map[Service]Result[T], error) {
+	return DecodeEachContext[T](context.Background(), c, cmd, args, services...)
+}
+
+// DecodeEachContext is like DecodeEach but honours ctx.
+func DecodeEachContext[T any](ctx context.Context, c *Client, cmd string, args interface{}, services ...Service) (map[Service]Result[T], error) {
+	responses, err := CallEachContext(ctx, c, cmd, args, services...)
+	if err != nil {
+		return nil, err
+	}
+
+	results := make(map[Service]Result[T], len(responses))
+	for service, r := range responses {
+		res := Result[T]{Text: r.Text, Err: r.Err}
+		if r.Err == nil && len(r.Value.Arguments) > 0 {
+			if err := json.Unmarshal(r.Value.Arguments, &res.Value); err != nil {
+				res.Err = fmt.Errorf("decode %s arguments: %w", cmd, err)
+			}
+		}
+		results[service] = res
+	}
+	return results, nil
+}
+
+// textEach sends cmd to services and keeps the text of each response as its value.
+func textEach(ctx context.Context, c *Client, cmd string, services []Service) (map[Service]Result[string], error) {
+	responses, err := CallEachContext(ctx, c, cmd, nil, services...)
+	if err != nil {
+		return nil, err
+	}
+
+	results := make(map[Service]Result[string], len(responses))
+	for service, r := range responses {
+		res := Result[string]{Text: r.Text, Err: r.Err}
+		if r.Err == nil {
+			res.Value = r.Text
+		}
+		results[service] = res
+	}
+	return results, nil
+}
@@ -0,0 +1,122 @@
+package client
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+// newMultiServer answers every request with reply, sent verbatim.
+func newMultiServer(t *testing.T, reply string) *Client {
+	t.Helper()
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte(reply))
+	}))
+	t.Cleanup(srv.Close)
+	return NewHTTP(srv.URL)
+}
+
+// TestStatusGetEach keeps the answer of a healthy service when another one fails.
+func TestStatusGetEach(t *testing.T) {
+	c := newMultiServer(t, `[
+		{"result": 0, "arguments": {"pid": 42}},
+		{"result": 1, "text": "forwarding socket is not configured for the server type d2"}
+	]`)
+
+	got, err := StatusGetEach[struct {
+		PID int `json:"pid"`
+	}](c, Services.DHCP4, Services.DDNS)
+	if err != nil {
+		t.Fatalf("StatusGetEach() error = %v", err)
+	}
+	if len(got) != 2 {
+		t.Fatalf("StatusGetEach() = %+v, want 2 results", got)
+	}
+	if r := got[Services.DHCP4]; r.Err != nil || r.Value.PID != 42 {
+		t.Errorf("dhcp4 result = %+v", r)
+	}
+	var cmdErr *CommandError
+	if r := got[Services.DDNS]; !errors.As(r.Err, &cmdErr) || cmdErr.Service != Services.DDNS || cmdErr.Code != ResultGeneralFailure {
+		t.Errorf("d2 result = %+v", r)
+	}
+
+	if _, err := StatusGetMulti[json.RawMessage](c, Services.DHCP4, Services.DDNS); err == nil {
+		t.Error("StatusGetMulti() succeeded despite the d2 failure")
+	}
+}
+
+// TestBuildReportEach keeps the text as the value of successful responses only.
+func TestBuildReportEach(t *testing.T) {
+	c := newMultiServer(t, `[{"result": 0, "text": "yes"}, {"result": 2, "text": "'build-report' command not supported"}]`)
+
+	got, err := BuildReportEach(c, Services.DHCP4, Services.DHCP6)
+	if err != nil {
+		t.Fatalf("BuildReportEach() error = %v", err)
+	}
+	if r := got[Services.DHCP4]; r.Err != nil || r.Value != "yes" {
+		t.Errorf("dhcp4 result = %+v", r)
+	}
+	if r := got[Services.DHCP6]; !errors.Is(r.Err, ErrUnsupported) || r.Value != "" || r.Text == "" {
+		t.Errorf("dhcp6 result = %+v", r)
+	}
+}
+
+// TestDecodeEach_Errors reports missing responses and undecodable arguments per service,
+// and transport failures for the call as a whole.
+func TestDecodeEach_Errors(t *testing.T) {
+	c := newMultiServer(t, `[{"result": 0, "arguments": ["not", "an", "object"]}]`)
+	got, err := ConfigGetEach[map[string]any](c, Services.DHCP4, Services.DHCP6)
+	if err != nil {
+		t.Fatalf("ConfigGetEach() error = %v", err)
+	}
+	if r := got[Services.DHCP4]; r.Err == nil || !contains(r.Err.Error(), "decode config-get arguments") {
+		t.Errorf("dhcp4 result = %+v", r)
+	}
+	if r := got[Services.DHCP6]; r.Err == nil || !contains(r.Err.Error(), "no response") {
+		t.Errorf("dhcp6 result = %+v", r)
+	}
+
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		http.Error(w, "down", http.StatusServiceUnavailable)
+	}))
+	defer srv.Close()
+	var statusErr *HTTPStatusError
+	if _, err := ListCommandsEach(NewHTTP(srv.URL), Services.DHCP4); !errors.As(err, &statusErr) {
+		t.Errorf("ListCommandsEach() error = %v, want *HTTPStatusError", err)
+	}
+}
+
+// TestCallEach_Agent keys the reply of the Control Agent itself by Services.Agent.
+func TestCallEach_Agent(t *testing.T) {
+	c := newMultiServer(t, `[{"result": 0, "text": "2.6.1", "arguments": {"extended": "2.6.1 tarball"}}]`)
+
+	got, err := VersionGetEach[struct {
+		Extended string `json:"extended"`
+	}](c)
+	if err != nil {
+		t.Fatalf("VersionGetEach() error = %v", err)
+	}
+	if r, ok := got[Services.Agent]; !ok || r.Err != nil || r.Text != "2.6.1" || r.Value.Extended != "2.6.1 tarball" {
+		t.Errorf("VersionGetEach() = %+v", got)
+	}
+}
+
+// TestCallEach_Socket decodes the single response object of a control socket.
+func TestCallEach_Socket(t *testing.T) {
+	path := TempSocketPath(t, "each")
+	startNativeSocketServer(t, path, `{"result": 3, "text": "no lease"}`, 1<<10)
+
+	c, err := NewSocket("unix", path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	got, err := CallEach(c, "lease4-get", map[string]any{"ip-address": "192.0.2.1"}, Services.DHCP4)
+	if err != nil {
+		t.Fatalf("CallEach() error = %v", err)
+	}
+	if r := got[Services.DHCP4]; !IsNotFound(r.Err) || r.Value.Result != ResultNotFound {
+		t.Errorf("CallEach() = %+v", got)
+	}
+}
@@ -0,0 +1,163 @@
+package client
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"io"
+	"net"
+	"sync"
+	"sync/atomic"
+	"syscall"
+)
+
+// PoolStats are the counters of a SocketTransport created with WithPool.
+type PoolStats struct {
+	Dials    uint64 // connections opened
+	Reuses   uint64 // commands sent on a pooled connection instead of a new one
+	Stale    uint64 // pooled connections found closed by the server and replaced
+	Failures uint64 // failed dials and connections dropped after an I/O error
+	Idle     int    // connections open and waiting for a command
+	InUse    int    // connections carrying a command
+}
+
+// connPool holds the open connections of a pooled SocketTransport. slots bounds the
+// number of connections, idle or in use; idle holds those waiting for a command.
+type connPool struct {
+	slots chan struct{}
+
+	mu     sync.Mutex
+	idle   []net.Conn
+	closed bool
+
+	dials, reuses, stale, failures atomic.Uint64
+}
+
+func newConnPool(size int) *connPool {
+	return &connPool{slots: make(chan struct{}, size)}
+}
+
+// call sends req on a pooled connection. A pooled connection that turns out to have
+// been closed by the server is replaced once, provided the command cannot have run:
+// nothing was written, or the command only reads state. Otherwise the server may have
+// executed it before hanging up, and sending it again could apply it twice.
+func (p *connPool) call(ctx context.Context, s *SocketTransport, req CommandRequest) (json.RawMessage, error) {
+	select {
+	case p.slots <- struct{}{}:
+	case <-ctx.Done():
+		return nil, ctx.Err()
+	}
+	defer func() { <-p.slots }()
+
+	for retried := false; ; retried = true {
+		conn, reused, err := p.get(ctx, s)
+		if err != nil {
+			p.failures.Add(1)
+			return nil, err
+		}
+
+		raw, reusable, err := s.roundTrip(ctx, conn, req)
+		if err != nil {
+			conn.Close()
+			if reused && !retried && ctx.Err() == nil && isHangup(err) && (isUnsent(err) || IsReadOnlyCommand(req.Command)) {
+				p.stale.Add(1)
+				continue
+			}
+			p.failures.Add(1)
+			return nil, err
+		}
+		if reusable {
+			p.put(conn)
+		} else {
+			conn.Close()
+		}
+		return raw, nil
+	}
+}
+
+// get returns a live idle connection, or dials a new one. The caller holds a slot.
+func (p *connPool) get(ctx context.Context, s *SocketTransport) (conn net.Conn, reused bool, err error) {
+	for {
+		p.mu.Lock()
+		if n := len(p.idle); n > 0 {
+			conn = p.idle[n-1]
+			p.idle = p.idle[:n-1]
+		}
+		p.mu.Unlock()
+		if conn == nil {
+			break
+		}
+		if connAlive(conn) {
+			p.reuses.Add(1)
+			return conn, true, nil
+		}
+		conn.Close()
+		conn = nil
+		p.stale.Add(1)
+	}
+
+	conn, err = s.dial(ctx)
+	if err != nil {
+		return nil, false, err
+	}
+	p.dials.Add(1)
+	return conn, false, nil
+}
+
+// put returns conn to the idle list, or closes it once the pool is closed.
+func (p *connPool) put(conn net.Conn) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	if p.closed {
+		conn.Close()
+		return
+	}
+	p.idle = append(p.idle, conn)
+}
+
+func (p *connPool) close() error {
+	p.mu.Lock()
+	idle := p.idle
+	p.idle, p.closed = nil, true
+	p.mu.Unlock()
+
+	var errs []error
+	for _, conn := range idle {
+		errs = append(errs, conn.Close())
+	}
+	return errors.Join(errs...)
+}
+
+func (p *connPool) stats() PoolStats {
+	p.mu.Lock()
+	idle := len(p.idle)
+	p.mu.Unlock()
+	return PoolStats{
+		Dials:    p.dials.Load(),
+		Reuses:   p.reuses.Load(),
+		Stale:    p.stale.Load(),
+		Failures: p.failures.Load(),
+		Idle:     idle,
+		InUse:    len(p.slots),
+	}
+}
+
+// isHangup reports whether err shows the peer had closed the connection before the
+// command was answered: nothing at all was read back, or the write was refused.
+func isHangup(err error) bool {
+	return errors.Is(err, io.EOF) || errors.Is(err, syscall.EPIPE) || errors.Is(err, syscall.ECONNRESET)
+}
+
+// unsentError marks a write that failed before any byte of the command was written.
+type unsentError struct {
+	err error
+}
+
+func (e *unsentError) Error() string { return e.err.Error() }
+func (e *unsentError) Unwrap() error { return e.err }
+
+// isUnsent reports whether err shows the command never left the client.
+func isUnsent(err error) bool {
+	var u *unsentError
+	return errors.As(err, &u)
+}
@@ -0,0 +1,11 @@
+//go:build !unix
+
+package client
+
+import "net"
+
+// connAlive cannot peek at conn on this platform; a connection the server has closed
+// is detected when the next command fails and is then redialled.
+func connAlive(conn net.Conn) bool {
+	return true
+}
@@ -0,0 +1,240 @@
+package client
+
+import (
+	"encoding/json"
+	"net"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+// startPersistentServer answers every request on a connection until the client hangs
+// up, or only the first one when hangUp is set, as Kea daemons do. It reports the
+// highest number of connections open at once.
+func startPersistentServer(t *testing.T, hangUp bool) (string, *atomic.Int32) {
+	t.Helper()
+
+	l, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatalf("failed to start listener: %v", err)
+	}
+	t.Cleanup(func() { l.Close() })
+
+	var open, peak atomic.Int32
+	go func() {
+		for {
+			conn, err := l.Accept()
+			if err != nil {
+				return
+			}
+			if n := open.Add(1); n > peak.Load() {
+				peak.Store(n)
+			}
+			go func() {
+				defer open.Add(-1)
+				defer conn.Close()
+				dec, enc := json.NewDecoder(conn), json.NewEncoder(conn)
+				for {
+					var req CommandRequest
+					if err := dec.Decode(&req); err != nil {
+						return
+					}
+					time.Sleep(time.Millisecond)
+					if err := enc.Encode(CommandResponse{Result: ResultSuccess, Text: req.Command}); err != nil || hangUp {
+						return
+					}
+				}
+			}()
+		}
+	}()
+	return l.Addr().String(), &peak
+}
+
+// TestSocketPool_Reuse sends every command over one connection.
+func TestSocketPool_Reuse(t *testing.T) {
+	addr, _ := startPersistentServer(t, false)
+	tr := NewSocketTransport("tcp", addr, 2*time.Second, WithPool(2))
+	defer tr.Close()
+
+	for i := 0; i < 20; i++ {
+		var out []CommandResponse
+		if err := tr.Call(CommandRequest{Command: "lease4-get-page"}, &out); err != nil {
+			t.Fatalf("Call() %d error = %v", i, err)
+		}
+		if len(out) != 1 || out[0].Text != "lease4-get-page" {
+			t.Fatalf("Call() %d = %+v", i, out)
+		}
+	}
+
+	want := PoolStats{Dials: 1, Reuses: 19, Idle: 1}
+	if got := tr.PoolStats(); got != want {
+		t.Errorf("PoolStats() = %+v, want %+v", got, want)
+	}
+}
+
+// TestSocketPool_Concurrent bounds the number of connections under concurrent use.
+func TestSocketPool_Concurrent(t *testing.T) {
+	addr, peak := startPersistentServer(t, false)
+	tr := NewSocketTransport("tcp", addr, 2*time.Second, WithPool(3))
+	defer tr.Close()
+
+	var wg sync.WaitGroup
+	for i := 0; i < 30; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			var out []CommandResponse
+			if err := tr.Call(CommandRequest{Command: "status-get"}, &out); err != nil {
+				t.Errorf("Call() error = %v", err)
+			}
+		}()
+	}
+	wg.Wait()
+
+	stats := tr.PoolStats()
+	if stats.Dials > 3 || stats.Dials+stats.Reuses != 30 || stats.InUse != 0 {
+		t.Errorf("PoolStats() = %+v, want at most 3 dials covering 30 commands", stats)
+	}
+	if p := peak.Load(); p > 3 {
+		t.Errorf("server saw %d connections at once, want at most 3", p)
+	}
+}
+
+// TestSocketPool_ServerHangsUp redials when the server closes the connection after each reply.
+func TestSocketPool_ServerHangsUp(t *testing.T) {
+	addr, _ := startPersistentServer(t, true)
+	tr := NewSocketTransport("tcp", addr, 2*time.Second, WithPool(1))
+	defer tr.Close()
+
+	for i := 0; i < 5; i++ {
+		var out []CommandResponse
+		if err := tr.Call(CommandRequest{Command: "status-get"}, &out); err != nil {
+			t.Fatalf("Call() %d error = %v", i, err)
+		}
+		time.Sleep(5 * time.Millisecond) // let the hang-up arrive
+	}
+
+	stats := tr.PoolStats()
+	if stats.Dials != 5 || stats.Stale != 4 || stats.Failures != 0 {
+		t.Errorf("PoolStats() = %+v, want 5 dials and 4 stale connections", stats)
+	}
+}
+
+// TestSocketPool_Failures counts failed dials.
+func TestSocketPool_Failures(t *testing.T) {
+	l, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	addr := l.Addr().String()
+	l.Close()
+
+	tr := NewSocketTransport("tcp", addr, time.Second, WithPool(1))
+	var out []CommandResponse
+	for i := 0; i < 2; i++ {
+		if err := tr.Call(CommandRequest{Command: "status-get"}, &out); err == nil || !contains(err.Error(), "connect") {
+			t.Fatalf("expected connect error, got %v", err)
+		}
+	}
+	if got := tr.PoolStats(); got.Failures != 2 || got.Dials != 0 || got.InUse != 0 {
+		t.Errorf("PoolStats() = %+v, want 2 failures", got)
+	}
+}
+
+// TestSocketPool_Close drops the idle connections and stops pooling.
+func TestSocketPool_Close(t *testing.T) {
+	addr, _ := startPersistentServer(t, false)
+	tr := NewSocketTransport("tcp", addr, 2*time.Second, WithPool(1))
+
+	var out []CommandResponse
+	if err := tr.Call(CommandRequest{Command: "status-get"}, &out); err != nil {
+		t.Fatal(err)
+	}
+	if err := tr.Close(); err != nil {
+		t.Fatalf("Close() error = %v", err)
+	}
+	if err := tr.Call(CommandRequest{Command: "status-get"}, &out); err != nil {
+		t.Fatalf("Call() after Close error = %v", err)
+	}
+	if got := tr.PoolStats(); got.Idle != 0 || got.Dials != 2 {
+		t.Errorf("PoolStats() = %+v, want no idle connections after 2 dials", got)
+	}
+}
+
+// startDroppingServer answers the first command on each connection and closes the
+// connection after reading the second one without answering, as a daemon restarting
+// mid-command would. It counts the commands it read by name.
+func startDroppingServer(t *testing.T) (string, func(cmd string) int) {
+	t.Helper()
+
+	l, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatalf("failed to start listener: %v", err)
+	}
+	t.Cleanup(func() { l.Close() })
+
+	var mu sync.Mutex
+	seen := map[string]int{}
+	go func() {
+		for {
+			conn, err := l.Accept()
+			if err != nil {
+				return
+			}
+			go func() {
+				defer conn.Close()
+				dec, enc := json.NewDecoder(conn), json.NewEncoder(conn)
+				for i := 0; ; i++ {
+					var req CommandRequest
+					if err := dec.Decode(&req); err != nil {
+						return
+					}
+					mu.Lock()
+					seen[req.Command]++
+					mu.Unlock()
+					if i > 0 {
+						return
+					}
+					if err := enc.Encode(CommandResponse{Result: ResultSuccess, Text: req.Command}); err != nil {
+						return
+					}
+				}
+			}()
+		}
+	}()
+	return l.Addr().String(), func(cmd string) int {
+		mu.Lock()
+		defer mu.Unlock()
+		return seen[cmd]
+	}
+}
+
+// TestSocketPool_DroppedAfterRead sends a command the server read before hanging up
+// only once, unless it is read-only.
+func TestSocketPool_DroppedAfterRead(t *testing.T) {
+	addr, seen := startDroppingServer(t)
+	tr := NewSocketTransport("tcp", addr, 2*time.Second, WithPool(1))
+	defer tr.Close()
+
+	var out []CommandResponse
+	if err := tr.Call(CommandRequest{Command: "status-get"}, &out); err != nil {
+		t.Fatal(err)
+	}
+	if err := tr.Call(CommandRequest{Command: "lease4-add"}, &out); err == nil {
+		t.Fatal("expected an error for the dropped lease4-add")
+	}
+	if n := seen("lease4-add"); n != 1 {
+		t.Errorf("server read lease4-add %d times, want exactly once", n)
+	}
+
+	if err := tr.Call(CommandRequest{Command: "status-get"}, &out); err != nil {
+		t.Fatal(err)
+	}
+	if err := tr.Call(CommandRequest{Command: "lease4-get"}, &out); err != nil {
+		t.Fatalf("Call() lease4-get error = %v, want it resent on a new connection", err)
+	}
+	if n := seen("lease4-get"); n != 2 {
+		t.Errorf("server read lease4-get %d times, want 2", n)
+	}
+}
@@ -0,0 +1,139 @@
+package client
+
+import (
+	"context"
+	"errors"
+	"io"
+	"math/rand/v2"
+	"net"
+	"net/http"
+	"strings"
+	"syscall"
+	"time"
+)
+
+// RetryPolicy configures the Retry middleware.
+type RetryPolicy struct {
+	MaxAttempts int           // attempts per call, the first included; 0 means 3
+	BaseDelay   time.Duration // upper bound of the first backoff, doubling after each retry; 0 means 100ms
+	MaxDelay    time.Duration // cap on the backoff; 0 means 5s
+
+	// Idempotent reports whether cmd may be sent again after Kea may already have
+	// received it. Nil means IsReadOnlyCommand.
+	Idempotent func(cmd string) bool
+
+	// RetryUndelivered opts the other commands in to retries, but only when the
+	// connection could not be opened, so the request never left the client.
+	RetryUndelivered bool
+
+	// OnRetry, if set, is called before each retry with the failed attempt number,
+	// its error and the delay before the next attempt.
+	OnRetry func(req CommandRequest, attempt int, err error, delay time.Duration)
+}
+
+// Retry retries calls that fail transiently: the connection is refused or reset,
+// e.g. while Kea restarts, a read times out, or the Control Agent answers 502, 503
+// or 504. Failures reported by Kea itself, such as a result code, and cancellation
+// of the context are not retried. Between attempts it waits a random time up to an
+// exponentially growing bound ("full jitter").
+//
+// Only idempotent commands, by default the read-only ones, are retried after the
+// request may have been delivered; other commands are retried only with
+// RetryUndelivered and only when the connection was never established.
+func Retry(policy RetryPolicy) Middleware {
+	if policy.MaxAttempts <= 0 {
+		policy.MaxAttempts = 3
+	}
+	if policy.BaseDelay <= 0 {
+		policy.BaseDelay = 100 * time.Millisecond
+	}
+	if policy.MaxDelay <= 0 {
+		policy.MaxDelay = 5 * time.Second
+	}
+	if policy.Idempotent == nil {
+		policy.Idempotent = IsReadOnlyCommand
+	}
+
+	return func(next Transport) Transport {
+		return TransportFunc(func(ctx context.Context, req CommandRequest, out interface{}) error {
+			idempotent := policy.Idempotent(req.Command)
+			for attempt := 1; ; attempt++ {
+				err := callContext(ctx, next, req, out)
+				if err == nil || attempt >= policy.MaxAttempts || ctx.Err() != nil || !isTransient(err) {
+					return err
+				}
+				if !idempotent && !(policy.RetryUndelivered && IsConnectError(err)) {
+					return err
+				}
+
+				delay := policy.backoff(attempt)
+				if policy.OnRetry != nil {
+					policy.OnRetry(req, attempt, err, delay)
+				}
+				timer := time.NewTimer(delay)
+				select {
+				case <-timer.C:
+				case <-ctx.Done():
+					timer.Stop()
+					return err
+				}
+			}
+		})
+	}
+}
+
+// backoff returns a random delay up to BaseDelay*2^(attempt-1), capped at MaxDelay.
+func (p RetryPolicy) backoff(attempt int) time.Duration {
+	limit := p.MaxDelay
+	if shift := attempt - 1; shift < 32 {
+		if d := p.BaseDelay << shift; d > 0 && d < limit {
+			limit = d
+		}
+	}
+	return rand.N(limit + 1)
+}
+
+// readOnlyCommands are read-only commands whose names do not follow the get/list pattern.
+var readOnlyCommands = map[string]bool{
+	"build-report":  true,
+	"list-commands": true,
+	"config-test":   true,
+	"ha-heartbeat":  true,
+}
+
+// IsReadOnlyCommand reports whether cmd only reads state and is safe to send twice:
+// the *-get and *-list commands and their variants such as lease4-get-page,
+// statistic-get-all or reservation-get-by-hostname, plus a few others such as
+// list-commands and build-report.
+func IsReadOnlyCommand(cmd string) bool {
+	return strings.HasSuffix(cmd, "-get") || strings.HasSuffix(cmd, "-list") ||
+		strings.Contains(cmd, "-get-") || readOnlyCommands[cmd]
+}
+
+// isTransient reports whether err is a failure that may go away on its own.
+func isTransient(err error) bool {
+	var statusErr *HTTPStatusError
+	if errors.As(err, &statusErr) {
+		switch statusErr.StatusCode {
+		case http.StatusBadGateway, http.StatusServiceUnavailable, http.StatusGatewayTimeout:
+			return true
+		}
+		return false
+	}
+	if IsConnectError(err) {
+		return true
+	}
+	var netErr net.Error
+	if errors.As(err, &netErr) && netErr.Timeout() {
+		return true
+	}
+	return errors.Is(err, io.EOF) || errors.Is(err, io.ErrUnexpectedEOF) ||
+		errors.Is(err, syscall.ECONNRESET) || errors.Is(err, syscall.EPIPE)
+}
+
+// IsConnectError reports whether err shows the connection to Kea could not be
+// established, so the command cannot have been delivered.
+func IsConnectError(err error) bool {
+	var opErr *net.OpError
+	return errors.As(err, &opErr) && opErr.Op == "dial"
+}
@@ -0,0 +1,196 @@
+package client
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"io"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+// fastRetry is a policy with delays short enough for tests.
+var fastRetry = RetryPolicy{MaxAttempts: 4, BaseDelay: time.Millisecond, MaxDelay: 2 * time.Millisecond}
+
+// failingTransport fails the first n calls with err, then succeeds.
+func failingTransport(n int, err error, calls *int) Transport {
+	return TransportFunc(func(ctx context.Context, req CommandRequest, out interface{}) error {
+		*calls++
+		if *calls <= n {
+			return err
+		}
+		return okTransport.CallContext(ctx, req, out)
+	})
+}
+
+// refusedError is what dialling a stopped Kea looks like.
+var refusedError = &net.OpError{Op: "dial", Net: "tcp", Err: errors.New("connect: connection refused")}
+
+// TestIsReadOnlyCommand classifies Kea command names.
+func TestIsReadOnlyCommand(t *testing.T) {
+	for cmd, want := range map[string]bool{
+		"status-get":                 true,
+		"statistic-get":              true,
+		"statistic-get-all":          true,
+		"config-get":                 true,
+		"subnet4-list":               true,
+		"lease4-get-page":            true,
+		"reservation-get-by-address": true,
+		"list-commands":              true,
+		"config-set":                 false,
+		"lease4-add":                 false,
+		"statistic-reset":            false,
+		"ha-maintenance-start":       false,
+		"shutdown":                   false,
+	} {
+		if got := IsReadOnlyCommand(cmd); got != want {
+			t.Errorf("IsReadOnlyCommand(%q) = %v, want %v", cmd, got, want)
+		}
+	}
+}
+
+// TestRetry_ReadOnly retries a read-only command until it succeeds.
+func TestRetry_ReadOnly(t *testing.T) {
+	var calls, retries int
+	policy := fastRetry
+	policy.OnRetry = func(req CommandRequest, attempt int, err error, delay time.Duration) {
+		retries++
+		if attempt != retries || delay > policy.MaxDelay {
+			t.Errorf("OnRetry(attempt=%d, delay=%v) on retry %d", attempt, delay, retries)
+		}
+	}
+
+	c := NewClient(failingTransport(2, refusedError, &calls), WithMiddleware(Retry(policy)))
+	if _, err := CallCommand(c, "status-get", Services.DHCP4); err != nil {
+		t.Fatalf("CallCommand() error = %v", err)
+	}
+	if calls != 3 || retries != 2 {
+		t.Errorf("calls = %d, retries = %d, want 3 and 2", calls, retries)
+	}
+}
+
+// TestRetry_GivesUp returns the last error after MaxAttempts.
+func TestRetry_GivesUp(t *testing.T) {
+	var calls int
+	c := NewClient(failingTransport(10, io.EOF, &calls), WithMiddleware(Retry(fastRetry)))
+	if _, err := CallCommand(c, "config-get"); !errors.Is(err, io.EOF) {
+		t.Errorf("CallCommand() error = %v, want EOF", err)
+	}
+	if calls != fastRetry.MaxAttempts {
+		t.Errorf("calls = %d, want %d", calls, fastRetry.MaxAttempts)
+	}
+}
+
+// TestRetry_Mutating never retries a mutating command that may have reached Kea, and
+// retries an undelivered one only when opted in.
+func TestRetry_Mutating(t *testing.T) {
+	tests := []struct {
+		name   string
+		policy RetryPolicy
+		err    error
+		want   int
+	}{
+		{"refused, not opted in", fastRetry, refusedError, 1},
+		{"refused, opted in", RetryPolicy{MaxAttempts: 3, BaseDelay: time.Millisecond, RetryUndelivered: true}, refusedError, 2},
+		{"reset, opted in", RetryPolicy{MaxAttempts: 3, BaseDelay: time.Millisecond, RetryUndelivered: true}, io.ErrUnexpectedEOF, 1},
+		{"503, opted in", RetryPolicy{MaxAttempts: 3, BaseDelay: time.Millisecond, RetryUndelivered: true}, &HTTPStatusError{StatusCode: 503}, 1},
+		{"declared idempotent", RetryPolicy{MaxAttempts: 3, BaseDelay: time.Millisecond, Idempotent: func(string) bool { return true }}, io.EOF, 2},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var calls int
+			c := NewClient(failingTransport(1, tt.err, &calls), WithMiddleware(Retry(tt.policy)))
+			CallCommand(c, "lease4-add", Services.DHCP4)
+			if calls != tt.want {
+				t.Errorf("calls = %d, want %d", calls, tt.want)
+			}
+		})
+	}
+}
+
+// TestRetry_PermanentErrors does not retry errors that will not go away.
+func TestRetry_PermanentErrors(t *testing.T) {
+	for _, err := range []error{
+		ResultNotFound.ResultError("no such subnet"),
+		&HTTPStatusError{StatusCode: http.StatusUnauthorized},
+		errors.New("unmarshal response: bad"),
+	} {
+		var calls int
+		c := NewClient(failingTransport(1, err, &calls), WithMiddleware(Retry(fastRetry)))
+		CallCommand(c, "status-get")
+		if calls != 1 {
+			t.Errorf("%v: calls = %d, want 1", err, calls)
+		}
+	}
+}
+
+// TestRetry_Context stops waiting between attempts when ctx is cancelled.
+func TestRetry_Context(t *testing.T) {
+	var calls int
+	policy := RetryPolicy{MaxAttempts: 5, BaseDelay: time.Hour, MaxDelay: time.Hour}
+	ctx, cancel := context.WithTimeout(context.Background(), 20*time.Millisecond)
+	defer cancel()
+
+	c := NewClient(failingTransport(10, refusedError, &calls), WithMiddleware(Retry(policy)))
+	start := time.Now()
+	if _, err := CallCommandContext(ctx, c, "status-get"); !errors.Is(err, refusedError) {
+		t.Errorf("CallCommandContext() error = %v, want the last attempt's error", err)
+	}
+	if time.Since(start) > time.Second || calls > 2 {
+		t.Errorf("retry ignored cancellation: %d calls in %v", calls, time.Since(start))
+	}
+}
+
+// TestRetry_HTTP retries a read-only command through a 503 from the Control Agent
+// and a restart of the daemon behind a real connection.
+func TestRetry_HTTP(t *testing.T) {
+	var hits atomic.Int32
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if hits.Add(1) == 1 {
+			http.Error(w, "server is overloaded", http.StatusServiceUnavailable)
+			return
+		}
+		json.NewEncoder(w).Encode([]CommandResponse{{Result: ResultSuccess}})
+	}))
+	defer srv.Close()
+
+	c := NewClient(NewHTTPTransport(srv.URL), WithMiddleware(Retry(fastRetry)))
+	if _, err := CallCommand(c, "version-get"); err != nil {
+		t.Fatalf("CallCommand() error = %v", err)
+	}
+	if hits.Load() != 2 {
+		t.Errorf("server hits = %d, want 2", hits.Load())
+	}
+
+	hits.Store(0)
+	if _, err := CallCommand(c, "config-set"); err == nil || !contains(err.Error(), "status=503") {
+		t.Errorf("config-set error = %v, want the 503", err)
+	}
+	if hits.Load() != 1 {
+		t.Errorf("config-set server hits = %d, want 1", hits.Load())
+	}
+
+	l, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	addr := l.Addr().String()
+	l.Close()
+
+	var calls int
+	refused := NewHTTPTransport("http://" + addr)
+	c = NewClient(TransportFunc(func(ctx context.Context, req CommandRequest, out interface{}) error {
+		calls++
+		return refused.CallContext(ctx, req, out)
+	}), WithMiddleware(Retry(RetryPolicy{MaxAttempts: 2, BaseDelay: time.Millisecond, RetryUndelivered: true})))
+	if _, err := CallCommand(c, "config-set"); err == nil || !IsConnectError(err) {
+		t.Errorf("CallCommand() error = %v, want a dial error", err)
+	}
+	if calls != 2 {
+		t.Errorf("calls = %d, want 2 for an undelivered config-set", calls)
+	}
+}
@@ -1,59 +1,177 @@
 package client
 
 import (
+	"context"
 	"encoding/json"
 	"fmt"
 	"net"
 	"time"
 )
 
-// SocketTransport connects to Kea directly via UNIX or TCP sockets.
+// SocketTransport connects to Kea directly via UNIX or TCP sockets, speaking the
+// daemons' native control socket protocol: each connection carries one command to
+// the daemon that owns the socket, so the service field is not sent, and the reply
+// is a single response object, which is normalised to the one-element list the
+// Control Agent would return.
+//
+// By default every command dials a new connection; WithPool keeps connections open
+// between commands instead.
 type SocketTransport struct {
 	network string // "unix" or "tcp"
 	address string
 	timeout time.Duration
+	pool    *connPool
+}
+
+// SocketOption configures a SocketTransport.
+type SocketOption func(*SocketTransport)
+
+// WithPool keeps up to size connections open and reuses them across commands, which
+// saves a dial per command when paging through many results. At most size commands
+// run at once; further callers wait for a free connection. The transport is safe for
+// concurrent use either way. Connections the server has closed are detected and
+// redialled, so daemons that hang up after every reply still work, just without the
+// saving. Close releases the idle connections.
+func WithPool(size int) SocketOption {
+	return func(s *SocketTransport) {
+		if size > 0 {
+			s.pool = newConnPool(size)
+		}
+	}
 }
 
 // NewSocketTransport creates a socket-based Transport.
-func NewSocketTransport(network, address string, timeout time.Duration) *SocketTransport {
-	return &SocketTransport{
+func NewSocketTransport(network, address string, timeout time.Duration, opts ...SocketOption) *SocketTransport {
+	s := &SocketTransport{
 		network: network,
 		address: address,
 		timeout: timeout,
 	}
+	for _, opt := range opts {
+		opt(s)
+	}
+	return s
 }
 
 // Call implements the Transport interface for sockets.
 func (s *SocketTransport) Call(req CommandRequest, out interface{}) error {
-	conn, err := net.DialTimeout(s.network, s.address, s.timeout)
+	return s.CallContext(context.Background(), req, out)
+}
+
+// CallContext implements the ContextTransport interface for sockets.
+// The connection deadline is the earlier of the transport timeout and the
+// deadline of ctx, and cancelling ctx interrupts any pending write or read.
+func (s *SocketTransport) CallContext(ctx context.Context, req CommandRequest, out interface{}) error {
+	if len(req.Service) > 1 {
+		return fmt.Errorf("control socket: %w, cannot send %s to %d services", ErrSingleDaemon, req.Command, len(req.Service))
+	}
+	orig := req
+	req.Service = nil
+
+	var raw json.RawMessage
+	var err error
+	if s.pool != nil {
+		raw, err = s.pool.call(ctx, s, req)
+	} else {
+		raw, err = s.callOnce(ctx, req)
+	}
 	if err != nil {
-		return fmt.Errorf("connect: %w", err)
+		return err
 	}
-	defer conn.Close()
 
-	// Optional: set read/write deadlines for additional protection
-	_ = conn.SetDeadline(time.Now().Add(s.timeout))
+	if err := decodeResponse(raw, out); err != nil {
+		return &TransportError{Op: "decode response", Err: err}
+	}
+	return checkResponses(orig, out)
+}
+
+// callOnce sends req on a connection of its own.
+func (s *SocketTransport) callOnce(ctx context.Context, req CommandRequest) (json.RawMessage, error) {
+	conn, err := s.dial(ctx)
+	if err != nil {
+		return nil, err
+	}
+	defer conn.Close()
+	raw, _, err := s.roundTrip(ctx, conn, req)
+	return raw, err
+}
 
-	enc := json.NewEncoder(conn)
-	if err := enc.Encode(&req); err != nil {
-		return fmt.Errorf("encode request: %w", err)
+func (s *SocketTransport) dial(ctx context.Context) (net.Conn, error) {
+	dialer := net.Dialer{Timeout: s.timeout}
+	conn, err := dialer.DialContext(ctx, s.network, s.address)
+	if err != nil {
+		return nil, &TransportError{Op: "connect", Err: err}
 	}
+	return conn, nil
+}
 
-	dec := json.NewDecoder(conn)
-	if err := dec.Decode(out); err != nil {
-		return fmt.Errorf("decode response: %w", err)
+// roundTrip writes req to conn and reads back one JSON document. reusable reports
+// whether conn is still in a state where it can carry another command.
+func (s *SocketTransport) roundTrip(ctx context.Context, conn net.Conn, req CommandRequest) (raw json.RawMessage, reusable bool, err error) {
+	deadline := time.Now().Add(s.timeout)
+	if d, ok := ctx.Deadline(); ok && d.Before(deadline) {
+		deadline = d
 	}
+	_ = conn.SetDeadline(deadline)
 
-	if responses, ok := out.(*[]CommandResponse); ok {
-		if len(*responses) == 0 {
-			return fmt.Errorf("empty response from Kea")
-		}
-		for _, r := range *responses {
-			if err := r.Result.ResultError(r.Text); err != nil {
-				return err
-			}
+	// Unblock pending I/O as soon as the context is cancelled.
+	stop := context.AfterFunc(ctx, func() {
+		_ = conn.SetDeadline(time.Unix(1, 0))
+	})
+
+	b, err := json.Marshal(&req)
+	if err != nil {
+		stop()
+		return nil, false, &TransportError{Op: "encode request", Err: err}
+	}
+	if n, err := conn.Write(append(b, '\n')); err != nil {
+		stop()
+		if n == 0 {
+			err = &unsentError{err}
 		}
+		return nil, false, &TransportError{Op: "encode request", Err: ctxErrOr(ctx, err)}
+	}
+
+	// Large replies arrive over many reads; the decoder keeps reading until the
+	// document is complete or the connection fails.
+	if err := json.NewDecoder(conn).Decode(&raw); err != nil {
+		stop()
+		return nil, false, &TransportError{Op: "decode response", Err: ctxErrOr(ctx, err)}
 	}
 
-	return nil
+	if !stop() {
+		return raw, false, nil // cancelled after the reply: the deadline is poisoned
+	}
+	return raw, conn.SetDeadline(time.Time{}) == nil, nil
+}
+
+// PoolStats reports the counters of a pooled transport; it is zero without WithPool.
+func (s *SocketTransport) PoolStats() PoolStats {
+	if s.pool == nil {
+		return PoolStats{}
+	}
+	return s.pool.stats()
+}
+
+// Close closes the idle pooled connections. Connections in use are closed when their
+// command completes. The transport stays usable but no longer keeps connections open.
+func (s *SocketTransport) Close() error {
+	if s.pool == nil {
+		return nil
+	}
+	return s.pool.close()
+}
+
+// ctxErrOr returns the context error if ctx is done, otherwise err.
+// I/O failures caused by cancellation then surface as context.Canceled or
+// context.DeadlineExceeded instead of an opaque timeout.
+func ctxErrOr(ctx context.Context, err error) error {
+	if ctxErr := ctx.Err(); ctxErr != nil {
+		return ctxErr
+	}
+	// The socket deadline may fire a moment before the context timer does.
+	if d, ok := ctx.Deadline(); ok && !time.Now().Before(d) {
+		return context.DeadlineExceeded
+	}
+	return err
 }
@@ -1,7 +1,9 @@
 package client
 
 import (
+	"context"
 	"encoding/json"
+	"errors"
 	"net"
 	"os"
 	"path/filepath"
@@ -80,6 +82,35 @@ func startGarbageServer(t *testing.T, network, address string) net.Listener {
 	return l
 }
 
+// startSilentServer starts a socket server that accepts connections but never replies,
+// holding each connection open until the listener is closed.
+func startSilentServer(t *testing.T, network, address string) net.Listener {
+	t.Helper()
+
+	l, err := net.Listen(network, address)
+	if err != nil {
+		t.Fatalf("failed to start %s listener: %v", network, err)
+	}
+
+	done := make(chan struct{})
+	t.Cleanup(func() { close(done) })
+
+	go func() {
+		for {
+			conn, err := l.Accept()
+			if err != nil {
+				return
+			}
+			go func() {
+				defer conn.Close()
+				<-done
+			}()
+		}
+	}()
+
+	return l
+}
+
 // TempSocketPath returns a temp file path for use as a UNIX socket.
 func TempSocketPath(t *testing.T, name string) string {
 	t.Helper()
@@ -205,7 +236,7 @@ func TestSocketTransport_EmptyResponse(t *testing.T) {
 
 	var out []CommandResponse
 	err := c.Call(CommandRequest{Command: "status-get"}, &out)
-	if err == nil || err.Error() != "empty response from Kea" {
+	if err == nil || err.Error() != "empty response from Kea" || !errors.Is(err, ErrEmptyResponse) {
 		t.Errorf("expected empty response error, got: %v", err)
 	}
 }
@@ -233,3 +264,173 @@ func TestSocketTransport_CommandResponseHandling(t *testing.T) {
 		t.Errorf("expected custom failure error, got: %v", err)
 	}
 }
+
+// TestSocketTransport_ContextDeadline verifies that a context deadline shorter than
+// the transport timeout interrupts a read from an unresponsive server.
+func TestSocketTransport_ContextDeadline(t *testing.T) {
+	l := startSilentServer(t, "tcp", "127.0.0.1:12352")
+	defer l.Close()
+
+	tr := NewSocketTransport("tcp", "127.0.0.1:12352", 5*time.Second)
+
+	ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+	defer cancel()
+
+	start := time.Now()
+	var out []CommandResponse
+	err := tr.CallContext(ctx, CommandRequest{Command: "status-get"}, &out)
+	if !errors.Is(err, context.DeadlineExceeded) {
+		t.Fatalf("expected deadline exceeded, got: %v", err)
+	}
+	if elapsed := time.Since(start); elapsed > 2*time.Second {
+		t.Errorf("call took %v, expected it to stop at the context deadline", elapsed)
+	}
+}
+
+// TestSocketTransport_ContextCanceled verifies that cancelling the context unblocks a pending read.
+func TestSocketTransport_ContextCanceled(t *testing.T) {
+	l := startSilentServer(t, "tcp", "127.0.0.1:12353")
+	defer l.Close()
+
+	tr := NewSocketTransport("tcp", "127.0.0.1:12353", 5*time.Second)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	time.AfterFunc(50*time.Millisecond, cancel)
+
+	var out []CommandResponse
+	err := tr.CallContext(ctx, CommandRequest{Command: "status-get"}, &out)
+	if !errors.Is(err, context.Canceled) {
+		t.Errorf("expected context canceled, got: %v", err)
+	}
+}
+
+// startNativeSocketServer starts a UNIX socket server that behaves like a Kea daemon:
+// it checks the request carries no service and writes reply in chunks of chunkSize
+// bytes, pausing between them, before closing the connection.
+func startNativeSocketServer(t *testing.T, address, reply string, chunkSize int) net.Listener {
+	t.Helper()
+	if runtime.GOOS == "windows" {
+		t.Skip("UNIX socket test skipped on Windows")
+	}
+
+	l, err := net.Listen("unix", address)
+	if err != nil {
+		t.Fatalf("failed to start unix listener: %v", err)
+	}
+	t.Cleanup(func() { l.Close() })
+
+	go func() {
+		conn, err := l.Accept()
+		if err != nil {
+			return
+		}
+		defer conn.Close()
+
+		var req map[string]interface{}
+		if err := json.NewDecoder(conn).Decode(&req); err != nil {
+			t.Errorf("server decode error: %v", err)
+			return
+		}
+		if _, ok := req["service"]; ok {
+			t.Errorf("request carries service: %v", req)
+		}
+
+		for data := []byte(reply); len(data) > 0; {
+			n := min(chunkSize, len(data))
+			if _, err := conn.Write(data[:n]); err != nil {
+				return
+			}
+			data = data[n:]
+			time.Sleep(time.Millisecond)
+		}
+	}()
+
+	return l
+}
+
+// TestSocketTransport_NativeResponse normalises a single response object and strips the service.
+func TestSocketTransport_NativeResponse(t *testing.T) {
+	path := TempSocketPath(t, "native")
+	startNativeSocketServer(t, path, `{"result": 0, "text": "ok", "arguments": {"pid": 42}}`, 1<<10)
+
+	c, err := NewSocket("unix", path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	resp, err := CallCommand(c, "status-get", Services.DHCP4)
+	if err != nil {
+		t.Fatalf("CallCommand() error = %v", err)
+	}
+	if len(resp) != 1 || resp[0].Text != "ok" || string(resp[0].Arguments) != `{"pid": 42}` {
+		t.Errorf("CallCommand() = %+v", resp)
+	}
+}
+
+// TestSocketTransport_NativeFailure reports the result of a single response object.
+func TestSocketTransport_NativeFailure(t *testing.T) {
+	path := TempSocketPath(t, "failure")
+	startNativeSocketServer(t, path, `{"result": 3, "text": "no lease found"}`, 1<<10)
+
+	c, err := NewSocket("unix", path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	_, err = CallCommand(c, "lease4-get", Services.DHCP4)
+	if !IsNotFound(err) {
+		t.Errorf("expected not found error, got %v", err)
+	}
+	var cmdErr *CommandError
+	if !errors.As(err, &cmdErr) || cmdErr.Command != "lease4-get" || cmdErr.Service != Services.DHCP4 || cmdErr.Text != "no lease found" {
+		t.Errorf("CommandError = %+v", cmdErr)
+	}
+}
+
+// TestSocketTransport_ChunkedResponse reads a large reply that arrives over many writes.
+func TestSocketTransport_ChunkedResponse(t *testing.T) {
+	leases := make([]map[string]interface{}, 2000)
+	for i := range leases {
+		leases[i] = map[string]interface{}{"ip-address": "192.0.2.1", "hw-address": "aa:bb:cc:dd:ee:ff", "subnet-id": i}
+	}
+	reply, err := json.Marshal(map[string]interface{}{"result": 0, "arguments": map[string]interface{}{"leases": leases}})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	path := TempSocketPath(t, "chunked")
+	startNativeSocketServer(t, path, string(reply), 4096)
+
+	var out []CommandResponse
+	tr := NewSocketTransport("unix", path, 5*time.Second)
+	if err := tr.Call(CommandRequest{Command: "lease4-get-all"}, &out); err != nil {
+		t.Fatalf("Call() error = %v", err)
+	}
+	var args struct {
+		Leases []json.RawMessage `json:"leases"`
+	}
+	if len(out) != 1 || json.Unmarshal(out[0].Arguments, &args) != nil || len(args.Leases) != len(leases) {
+		t.Errorf("Call() decoded %d leases from %d bytes", len(args.Leases), len(reply))
+	}
+}
+
+// TestSocketTransport_TruncatedResponse fails when the connection closes mid-document.
+func TestSocketTransport_TruncatedResponse(t *testing.T) {
+	path := TempSocketPath(t, "truncated")
+	startNativeSocketServer(t, path, `{"result": 0, "arguments": {"leases": [`, 8)
+
+	var out []CommandResponse
+	tr := NewSocketTransport("unix", path, 2*time.Second)
+	err := tr.Call(CommandRequest{Command: "lease4-get-all"}, &out)
+	if err == nil || !contains(err.Error(), "decode response") {
+		t.Errorf("expected decode error, got %v", err)
+	}
+}
+
+// TestSocketTransport_MultipleServices refuses to send one request to several daemons.
+func TestSocketTransport_MultipleServices(t *testing.T) {
+	tr := NewSocketTransport("unix", "/nonexistent.sock", time.Second)
+	var out []CommandResponse
+	err := tr.Call(CommandRequest{Command: "config-get", Service: []Service{Services.DHCP4, Services.DHCP6}}, &out)
+	if err == nil || !contains(err.Error(), "single daemon") || !errors.Is(err, ErrSingleDaemon) {
+		t.Errorf("expected single daemon error, got %v", err)
+	}
+}
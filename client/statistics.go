@@ -0,0 +1,135 @@
+package client
+
+import (
+	"context"
+	"time"
+
+	"github.com/rannday/kea-api/types"
+)
+
+/*
+ * Statistics commands shared by kea-dhcp4, kea-dhcp6 and kea-dhcp-ddns.
+ * The DDNS daemon only implements statistic-get, statistic-get-all,
+ * statistic-reset and statistic-reset-all.
+ */
+
+// StatisticGet fetches the samples of a single statistic. A statistic that does not
+// exist yet is returned without samples.
+func StatisticGet(c *Client, service Service, name string) (types.Statistic, error) {
+	return StatisticGetContext(context.Background(), c, service, name)
+}
+
+// StatisticGetContext is like StatisticGet but honours ctx.
+func StatisticGetContext(ctx context.Context, c *Client, service Service, name string) (types.Statistic, error) {
+	set, err := DecodeFirstWithArgsContext[types.StatisticSet](ctx, c, "statistic-get", map[string]interface{}{"name": name}, service)
+	if err != nil {
+		return types.Statistic{}, err
+	}
+	return types.Statistic{Name: name, Samples: set[name]}, nil
+}
+
+// StatisticGetAll fetches every statistic of a service, sorted by name.
+func StatisticGetAll(c *Client, service Service) ([]types.Statistic, error) {
+	return StatisticGetAllContext(context.Background(), c, service)
+}
+
+// StatisticGetAllContext is like StatisticGetAll but honours ctx.
+func StatisticGetAllContext(ctx context.Context, c *Client, service Service) ([]types.Statistic, error) {
+	set, err := DecodeFirstContext[types.StatisticSet](ctx, c, "statistic-get-all", service)
+	if err != nil {
+		return nil, err
+	}
+	return set.Statistics(), nil
+}
+
+// StatisticReset sets a statistic back to its neutral value (0 or an empty duration).
+func StatisticReset(c *Client, service Service, name string) error {
+	return StatisticResetContext(context.Background(), c, service, name)
+}
+
+// StatisticResetContext is like StatisticReset but honours ctx.
+func StatisticResetContext(ctx context.Context, c *Client, service Service, name string) error {
+	_, err := CallWithArgsContext(ctx, c, "statistic-reset", map[string]interface{}{"name": name}, service)
+	return err
+}
+
+// StatisticResetAll resets every statistic of a service.
+func StatisticResetAll(c *Client, service Service) error {
+	return StatisticResetAllContext(context.Background(), c, service)
+}
+
+// StatisticResetAllContext is like StatisticResetAll but honours ctx.
+func StatisticResetAllContext(ctx context.Context, c *Client, service Service) error {
+	_, err := CallCommandContext(ctx, c, "statistic-reset-all", service)
+	return err
+}
+
+// StatisticRemove deletes a statistic and all of its samples.
+func StatisticRemove(c *Client, service Service, name string) error {
+	return StatisticRemoveContext(context.Background(), c, service, name)
+}
+
+// StatisticRemoveContext is like StatisticRemove but honours ctx.
+func StatisticRemoveContext(ctx context.Context, c *Client, service Service, name string) error {
+	_, err := CallWithArgsContext(ctx, c, "statistic-remove", map[string]interface{}{"name": name}, service)
+	return err
+}
+
+// StatisticRemoveAll deletes every statistic of a service.
+func StatisticRemoveAll(c *Client, service Service) error {
+	return StatisticRemoveAllContext(context.Background(), c, service)
+}
+
+// StatisticRemoveAllContext is like StatisticRemoveAll but honours ctx.
+func StatisticRemoveAllContext(ctx context.Context, c *Client, service Service) error {
+	_, err := CallCommandContext(ctx, c, "statistic-remove-all", service)
+	return err
+}
+
+// StatisticSampleAgeSet limits a statistic to samples younger than age (whole seconds).
+func StatisticSampleAgeSet(c *Client, service Service, name string, age time.Duration) error {
+	return StatisticSampleAgeSetContext(context.Background(), c, service, name, age)
+}
+
+// StatisticSampleAgeSetContext is like StatisticSampleAgeSet but honours ctx.
+func StatisticSampleAgeSetContext(ctx context.Context, c *Client, service Service, name string, age time.Duration) error {
+	args := map[string]interface{}{"name": name, "duration": int64(age / time.Second)}
+	_, err := CallWithArgsContext(ctx, c, "statistic-sample-age-set", args, service)
+	return err
+}
+
+// StatisticSampleAgeSetAll applies StatisticSampleAgeSet to every statistic of a service.
+func StatisticSampleAgeSetAll(c *Client, service Service, age time.Duration) error {
+	return StatisticSampleAgeSetAllContext(context.Background(), c, service, age)
+}
+
+// StatisticSampleAgeSetAllContext is like StatisticSampleAgeSetAll but honours ctx.
+func StatisticSampleAgeSetAllContext(ctx context.Context, c *Client, service Service, age time.Duration) error {
+	args := map[string]interface{}{"duration": int64(age / time.Second)}
+	_, err := CallWithArgsContext(ctx, c, "statistic-sample-age-set-all", args, service)
+	return err
+}
+
+// StatisticSampleCountSet limits a statistic to its most recent maxSamples samples.
+func StatisticSampleCountSet(c *Client, service Service, name string, maxSamples int) error {
+	return StatisticSampleCountSetContext(context.Background(), c, service, name, maxSamples)
+}
+
+// StatisticSampleCountSetContext is like StatisticSampleCountSet but honours ctx.
+func StatisticSampleCountSetContext(ctx context.Context, c *Client, service Service, name string, maxSamples int) error {
+	args := map[string]interface{}{"name": name, "max-samples": maxSamples}
+	_, err := CallWithArgsContext(ctx, c, "statistic-sample-count-set", args, service)
+	return err
+}
+
+// StatisticSampleCountSetAll applies StatisticSampleCountSet to every statistic of a service.
+func StatisticSampleCountSetAll(c *Client, service Service, maxSamples int) error {
+	return StatisticSampleCountSetAllContext(context.Background(), c, service, maxSamples)
+}
+
+// StatisticSampleCountSetAllContext is like StatisticSampleCountSetAll but honours ctx.
+func StatisticSampleCountSetAllContext(ctx context.Context, c *Client, service Service, maxSamples int) error {
+	args := map[string]interface{}{"max-samples": maxSamples}
+	_, err := CallWithArgsContext(ctx, c, "statistic-sample-count-set-all", args, service)
+	return err
+}
@@ -2,7 +2,6 @@ package client
 
 import (
 	"encoding/json"
-	"fmt"
 )
 
 // ResultCode represents the result code returned by Kea commands.
@@ -17,22 +16,13 @@ const (
 	ResultConflict       ResultCode = 4
 )
 
-// ResultError converts a ResultCode to an error.
+// ResultError converts a ResultCode to an error: nil for ResultSuccess, otherwise a
+// *CommandError carrying r and text.
 func (r ResultCode) ResultError(text string) error {
-	switch r {
-	case ResultSuccess:
+	if r == ResultSuccess {
 		return nil
-	case ResultGeneralFailure:
-		return fmt.Errorf("general error: %s", text)
-	case ResultUnsupported:
-		return fmt.Errorf("unsupported command: %s", text)
-	case ResultNotFound:
-		return fmt.Errorf("resource not found: %s", text)
-	case ResultConflict:
-		return fmt.Errorf("conflict: %s", text)
-	default:
-		return fmt.Errorf("unknown result code %d: %s", r, text)
 	}
+	return &CommandError{Code: r, Text: text}
 }
 
 // Service represents a Kea service name.
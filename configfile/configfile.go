@@ -0,0 +1,506 @@
+// Package configfile reads Kea configuration files as found on disk. Unlike plain
+// JSON they may contain #, // and /* */ comments and <?include "file"?> directives,
+// which are expanded relative to the file containing them.
+package configfile
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+	"slices"
+	"strings"
+)
+
+// maxIncludeDepth bounds nested includes.
+const maxIncludeDepth = 32
+
+// Position locates a value in a configuration file. Line and Col are 1-based; Col counts bytes.
+type Position struct {
+	File string
+	Line int
+	Col  int
+}
+
+// String formats the position as file:line:col.
+func (p Position) String() string {
+	return fmt.Sprintf("%s:%d:%d", p.File, p.Line, p.Col)
+}
+
+// Error is a problem found at a position in a configuration file.
+type Error struct {
+	Pos Position
+	Msg string
+	Err error // underlying error, if any
+}
+
+func (e *Error) Error() string {
+	return e.Pos.String() + ": " + e.Msg
+}
+
+func (e *Error) Unwrap() error {
+	return e.Err
+}
+
+// Document is a parsed configuration file with its includes expanded.
+type Document struct {
+	JSON   []byte // plain JSON equivalent of the file
+	values []valueInfo
+	index  map[string]int
+}
+
+// valueInfo records where a value starts, keyed by its RFC 6901 JSON Pointer.
+type valueInfo struct {
+	pointer string
+	pos     Position
+	kind    string // object, array, string, number or bool; null values are "null"
+}
+
+// Parse reads and parses the configuration file at path.
+func Parse(path string) (*Document, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	return ParseBytes(path, data)
+}
+
+// ParseBytes parses data as if it had been read from the file name; includes are
+// resolved relative to the directory of name.
+func ParseBytes(name string, data []byte) (*Document, error) {
+	p := &parser{doc: &Document{index: make(map[string]int)}}
+	p.lex = []*lexer{newLexer(name, data)}
+	if abs, err := filepath.Abs(name); err == nil {
+		p.lex[0].abs = abs
+	}
+
+	if err := p.value(""); err != nil {
+		return nil, err
+	}
+	tok, err := p.next()
+	if err != nil {
+		return nil, err
+	}
+	if tok.kind != tokEOF {
+		return nil, p.errorf(tok.pos, "unexpected %s after the top-level value", tok)
+	}
+	p.doc.JSON = p.out.Bytes()
+	return p.doc, nil
+}
+
+// Load parses the configuration file at path and decodes it into v, such as a
+// *dhcp4.Dhcp4Config.
+func Load(path string, v interface{}) (*Document, error) {
+	doc, err := Parse(path)
+	if err != nil {
+		return nil, err
+	}
+	return doc, doc.Decode(v)
+}
+
+// Decode unmarshals the document into v. Type mismatches are reported at the
+// position of the offending value.
+func (d *Document) Decode(v interface{}) error {
+	err := json.Unmarshal(d.JSON, v)
+	var typeErr *json.UnmarshalTypeError
+	if errors.As(err, &typeErr) {
+		if pos, ok := d.findField(typeErr.Field, typeErr.Value); ok {
+			return &Error{Pos: pos, Msg: fmt.Sprintf("cannot use %s as %s for %s", typeErr.Value, typeErr.Type, typeErr.Field), Err: err}
+		}
+	}
+	return err
+}
+
+// Position returns where the value at the given RFC 6901 JSON Pointer starts,
+// e.g. "/Dhcp4/subnet4/0/pools/1".
+func (d *Document) Position(pointer string) (Position, bool) {
+	i, ok := d.index[pointer]
+	if !ok {
+		return Position{}, false
+	}
+	return d.values[i].pos, true
+}
+
+// findField locates the value encoding/json blamed for a type error. Field is a
+// dotted path such as "Dhcp4.subnet4.0.id". Older Go releases omit the list
+// indices, and types with their own UnmarshalJSON report only the innermost
+// member names, so failing an exact match the first value of the given JSON
+// kind whose path ends in field wins.
+func (d *Document) findField(field, kind string) (Position, bool) {
+	if field == "" {
+		return Position{}, false
+	}
+	want := strings.Split(field, ".")
+	var pointer strings.Builder
+	for _, part := range want {
+		pointer.WriteString("/" + escapePointer(part))
+	}
+	if i, ok := d.index[pointer.String()]; ok {
+		return d.values[i].pos, true
+	}
+
+	for _, v := range d.values {
+		if v.kind != kind {
+			continue
+		}
+		var parts []string
+		for _, tok := range strings.Split(v.pointer, "/")[1:] {
+			if !isIndex(tok) {
+				parts = append(parts, strings.NewReplacer("~1", "/", "~0", "~").Replace(tok))
+			}
+		}
+		if len(parts) >= len(want) && slices.Equal(parts[len(parts)-len(want):], want) {
+			return v.pos, true
+		}
+	}
+	return Position{}, false
+}
+
+func isIndex(tok string) bool {
+	if tok == "" {
+		return false
+	}
+	for _, r := range tok {
+		if r < '0' || r > '9' {
+			return false
+		}
+	}
+	return true
+}
+
+type tokenKind int
+
+const (
+	tokEOF tokenKind = iota
+	tokDelim
+	tokString
+	tokNumber
+	tokLiteral
+	tokInclude
+)
+
+type token struct {
+	kind tokenKind
+	text string // raw text; for tokInclude the decoded file name
+	pos  Position
+}
+
+func (t token) String() string {
+	switch t.kind {
+	case tokEOF:
+		return "end of file"
+	case tokInclude:
+		return "include directive"
+	}
+	return fmt.Sprintf("%q", t.text)
+}
+
+// lexer splits one file into tokens, skipping whitespace and comments.
+type lexer struct {
+	name string
+	abs  string
+	data []byte
+	off  int
+	line int
+	col  int
+}
+
+func newLexer(name string, data []byte) *lexer {
+	return &lexer{name: name, data: data, line: 1, col: 1}
+}
+
+func (l *lexer) pos() Position {
+	return Position{File: l.name, Line: l.line, Col: l.col}
+}
+
+func (l *lexer) advance(n int) {
+	for _, b := range l.data[l.off : l.off+n] {
+		if b == '\n' {
+			l.line++
+			l.col = 1
+		} else {
+			l.col++
+		}
+	}
+	l.off += n
+}
+
+var (
+	numberPattern  = regexp.MustCompile(`^-?(0|[1-9][0-9]*)(\.[0-9]+)?([eE][+-]?[0-9]+)?`)
+	stringPattern  = regexp.MustCompile(`^"(\\.|[^"\\\x00-\x1f])*"`)
+	literalPattern = regexp.MustCompile(`^(true|false|null)\b`)
+	includePattern = regexp.MustCompile(`^<\?include\s*("(?:\\.|[^"\\])*")\s*\?>`)
+)
+
+func (l *lexer) next() (token, error) {
+	for l.off < len(l.data) {
+		rest := l.data[l.off:]
+		switch {
+		case rest[0] == ' ' || rest[0] == '\t' || rest[0] == '\r' || rest[0] == '\n':
+			l.advance(1)
+		case rest[0] == '#' || bytes.HasPrefix(rest, []byte("//")):
+			n := bytes.IndexByte(rest, '\n')
+			if n < 0 {
+				n = len(rest)
+			}
+			l.advance(n)
+		case bytes.HasPrefix(rest, []byte("/*")):
+			start := l.pos()
+			n := bytes.Index(rest[2:], []byte("*/"))
+			if n < 0 {
+				return token{}, &Error{Pos: start, Msg: "unterminated comment"}
+			}
+			l.advance(n + 4)
+		default:
+			return l.scan()
+		}
+	}
+	return token{kind: tokEOF, pos: l.pos()}, nil
+}
+
+func (l *lexer) scan() (token, error) {
+	pos, rest := l.pos(), l.data[l.off:]
+	emit := func(kind tokenKind, n int) (token, error) {
+		t := token{kind: kind, text: string(rest[:n]), pos: pos}
+		l.advance(n)
+		return t, nil
+	}
+
+	switch c := rest[0]; {
+	case strings.IndexByte("{}[]:,", c) >= 0:
+		return emit(tokDelim, 1)
+	case c == '"':
+		if m := stringPattern.Find(rest); m != nil {
+			return emit(tokString, len(m))
+		}
+		return token{}, &Error{Pos: pos, Msg: "invalid or unterminated string"}
+	case c == '-' || (c >= '0' && c <= '9'):
+		if m := numberPattern.Find(rest); m != nil {
+			return emit(tokNumber, len(m))
+		}
+	case c == '<':
+		if m := includePattern.FindSubmatch(rest); m != nil {
+			var file string
+			if err := json.Unmarshal(m[1], &file); err != nil {
+				return token{}, &Error{Pos: pos, Msg: "invalid include file name", Err: err}
+			}
+			l.advance(len(m[0]))
+			return token{kind: tokInclude, text: file, pos: pos}, nil
+		}
+		return token{}, &Error{Pos: pos, Msg: `malformed include directive, want <?include "file"?>`}
+	default:
+		if m := literalPattern.Find(rest); m != nil {
+			return emit(tokLiteral, len(m))
+		}
+	}
+	return token{}, &Error{Pos: pos, Msg: fmt.Sprintf("unexpected character %q", rest[0])}
+}
+
+// parser reads tokens from a stack of lexers, one per open include, and writes plain JSON.
+type parser struct {
+	lex    []*lexer
+	peeked *token
+	out    bytes.Buffer
+	doc    *Document
+}
+
+func (p *parser) errorf(pos Position, format string, args ...interface{}) error {
+	return &Error{Pos: pos, Msg: fmt.Sprintf(format, args...)}
+}
+
+// next returns the next token, entering and leaving included files as needed.
+func (p *parser) next() (token, error) {
+	if p.peeked != nil {
+		t := *p.peeked
+		p.peeked = nil
+		return t, nil
+	}
+	for {
+		cur := p.lex[len(p.lex)-1]
+		tok, err := cur.next()
+		if err != nil {
+			return token{}, err
+		}
+		switch {
+		case tok.kind == tokEOF && len(p.lex) > 1:
+			p.lex = p.lex[:len(p.lex)-1]
+		case tok.kind == tokInclude:
+			if err := p.include(cur, tok); err != nil {
+				return token{}, err
+			}
+		default:
+			return tok, nil
+		}
+	}
+}
+
+func (p *parser) peek() (token, error) {
+	if p.peeked == nil {
+		t, err := p.next()
+		if err != nil {
+			return token{}, err
+		}
+		p.peeked = &t
+	}
+	return *p.peeked, nil
+}
+
+func (p *parser) include(from *lexer, tok token) error {
+	if len(p.lex) >= maxIncludeDepth {
+		return p.errorf(tok.pos, "includes nested too deeply")
+	}
+	name := tok.text
+	if !filepath.IsAbs(name) {
+		name = filepath.Join(filepath.Dir(from.name), name)
+	}
+	abs, err := filepath.Abs(name)
+	if err != nil {
+		return &Error{Pos: tok.pos, Msg: err.Error(), Err: err}
+	}
+	for _, l := range p.lex {
+		if l.abs == abs {
+			return p.errorf(tok.pos, "include cycle: %s includes itself", name)
+		}
+	}
+	data, err := os.ReadFile(name)
+	if err != nil {
+		return &Error{Pos: tok.pos, Msg: fmt.Sprintf("include: %v", err), Err: err}
+	}
+	l := newLexer(name, data)
+	l.abs = abs
+	p.lex = append(p.lex, l)
+	return nil
+}
+
+func (p *parser) record(pointer string, pos Position, kind string) {
+	p.doc.index[pointer] = len(p.doc.values)
+	p.doc.values = append(p.doc.values, valueInfo{pointer: pointer, pos: pos, kind: kind})
+}
+
+// value parses one JSON value located at pointer.
+func (p *parser) value(pointer string) error {
+	tok, err := p.next()
+	if err != nil {
+		return err
+	}
+	switch {
+	case tok.kind == tokDelim && tok.text == "{":
+		p.record(pointer, tok.pos, "object")
+		return p.object(pointer)
+	case tok.kind == tokDelim && tok.text == "[":
+		p.record(pointer, tok.pos, "array")
+		return p.array(pointer)
+	case tok.kind == tokString:
+		p.record(pointer, tok.pos, "string")
+	case tok.kind == tokNumber:
+		p.record(pointer, tok.pos, "number")
+	case tok.kind == tokLiteral:
+		kind := "bool"
+		if tok.text == "null" {
+			kind = "null"
+		}
+		p.record(pointer, tok.pos, kind)
+	default:
+		return p.errorf(tok.pos, "expected a value, got %s", tok)
+	}
+	p.out.WriteString(tok.text)
+	return nil
+}
+
+func (p *parser) object(pointer string) error {
+	p.out.WriteByte('{')
+	seen := make(map[string]bool)
+	for first := true; ; first = false {
+		tok, err := p.next()
+		if err != nil {
+			return err
+		}
+		if tok.kind == tokDelim && tok.text == "}" {
+			break
+		}
+		if !first {
+			if tok.kind != tokDelim || tok.text != "," {
+				return p.errorf(tok.pos, "expected , or } in object, got %s", tok)
+			}
+			// A trailing comma before the closing brace is tolerated.
+			if next, err := p.peek(); err != nil {
+				return err
+			} else if next.kind == tokDelim && next.text == "}" {
+				p.next()
+				break
+			}
+			p.out.WriteByte(',')
+			if tok, err = p.next(); err != nil {
+				return err
+			}
+		}
+
+		if tok.kind != tokString {
+			return p.errorf(tok.pos, "expected object key, got %s", tok)
+		}
+		var key string
+		if err := json.Unmarshal([]byte(tok.text), &key); err != nil {
+			return &Error{Pos: tok.pos, Msg: "invalid object key", Err: err}
+		}
+		if seen[key] {
+			return p.errorf(tok.pos, "duplicate key %q", key)
+		}
+		seen[key] = true
+
+		colon, err := p.next()
+		if err != nil {
+			return err
+		}
+		if colon.kind != tokDelim || colon.text != ":" {
+			return p.errorf(colon.pos, "expected : after key %q, got %s", key, colon)
+		}
+		p.out.WriteString(tok.text)
+		p.out.WriteByte(':')
+		if err := p.value(pointer + "/" + escapePointer(key)); err != nil {
+			return err
+		}
+	}
+	p.out.WriteByte('}')
+	return nil
+}
+
+func (p *parser) array(pointer string) error {
+	p.out.WriteByte('[')
+	for i := 0; ; i++ {
+		tok, err := p.peek()
+		if err != nil {
+			return err
+		}
+		if tok.kind == tokDelim && tok.text == "]" {
+			p.next()
+			break
+		}
+		if i > 0 {
+			if tok.kind != tokDelim || tok.text != "," {
+				return p.errorf(tok.pos, "expected , or ] in list, got %s", tok)
+			}
+			p.next()
+			// A trailing comma before the closing bracket is tolerated.
+			if next, err := p.peek(); err != nil {
+				return err
+			} else if next.kind == tokDelim && next.text == "]" {
+				p.next()
+				break
+			}
+			p.out.WriteByte(',')
+		}
+		if err := p.value(fmt.Sprintf("%s/%d", pointer, i)); err != nil {
+			return err
+		}
+	}
+	p.out.WriteByte(']')
+	return nil
+}
+
+// escapePointer escapes a member name for use in an RFC 6901 JSON Pointer.
+func escapePointer(s string) string {
+	return strings.NewReplacer("~", "~0", "/", "~1").Replace(s)
+}
@@ -0,0 +1,142 @@
+package configfile
+
+import (
+	"encoding/json"
+	"errors"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+// TestParse strips comments, expands includes and tolerates trailing commas.
+func TestParse(t *testing.T) {
+	t.Parallel()
+
+	doc, err := Parse("testdata/kea-dhcp4.conf")
+	if err != nil {
+		t.Fatalf("Parse() error = %v", err)
+	}
+
+	var got, want interface{}
+	if err := json.Unmarshal(doc.JSON, &got); err != nil {
+		t.Fatalf("Parse() produced invalid JSON %s: %v", doc.JSON, err)
+	}
+	wantJSON := `{"Dhcp4": {
+		"interfaces-config": {"interfaces": ["eth0"]},
+		"valid-lifetime": 4000,
+		"subnet4": [
+			{"id": 1, "subnet": "192.0.2.0/24", "pools": [{"pool": "192.0.2.10 - 192.0.2.100"}]},
+			{"id": 2, "subnet": "198.51.100.0/24"}
+		],
+		"server-tag": "http://example.com/#not-a-comment"
+	}}`
+	if err := json.Unmarshal([]byte(wantJSON), &want); err != nil {
+		t.Fatal(err)
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("Parse() JSON = %s", doc.JSON)
+	}
+}
+
+// TestDocumentPosition reports positions in the file that holds each value, including included files.
+func TestDocumentPosition(t *testing.T) {
+	t.Parallel()
+
+	doc, err := Parse("testdata/kea-dhcp4.conf")
+	if err != nil {
+		t.Fatalf("Parse() error = %v", err)
+	}
+
+	tests := []struct {
+		pointer string
+		want    Position
+	}{
+		{"", Position{"testdata/kea-dhcp4.conf", 2, 1}},
+		{"/Dhcp4/valid-lifetime", Position{"testdata/kea-dhcp4.conf", 10, 27}},
+		{"/Dhcp4/subnet4/0", Position{filepath.Join("testdata", "subnets", "office.json"), 2, 1}},
+		{"/Dhcp4/subnet4/0/pools/0/pool", Position{filepath.Join("testdata", "subnets", "office.json"), 5, 26}},
+		{"/Dhcp4/subnet4/1/subnet", Position{"testdata/kea-dhcp4.conf", 15, 27}},
+	}
+	for _, tt := range tests {
+		got, ok := doc.Position(tt.pointer)
+		if !ok || got != tt.want {
+			t.Errorf("Position(%q) = %v, %v; want %v", tt.pointer, got, ok, tt.want)
+		}
+	}
+	if _, ok := doc.Position("/Dhcp4/subnet4/2"); ok {
+		t.Error("Position() found a value that does not exist")
+	}
+}
+
+// TestParseErrors reports problems as file:line:col, in the included file where relevant.
+func TestParseErrors(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name string
+		file string
+		data string
+		want string
+	}{
+		{"missing comma", "bad-include.conf", "", "testdata/subnets/broken.json:3:5: expected , or } in object"},
+		{"include cycle", "cycle-a.conf", "", "include cycle"},
+		{"missing include", "inline.conf", `{"a": <?include "nope.json"?>}`, "testdata/inline.conf:1:7: include:"},
+		{"unterminated comment", "inline.conf", "{\n  /* never closed\n}", "testdata/inline.conf:2:3: unterminated comment"},
+		{"duplicate key", "inline.conf", `{"a": 1, "a": 2}`, `testdata/inline.conf:1:10: duplicate key "a"`},
+		{"bad literal", "inline.conf", `{"a": True}`, "testdata/inline.conf:1:7: unexpected character 'T'"},
+		{"trailing data", "inline.conf", `{} {}`, "testdata/inline.conf:1:4: unexpected"},
+		{"malformed include", "inline.conf", `<?include nope?>`, "testdata/inline.conf:1:1: malformed include"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			var err error
+			if tt.data == "" {
+				_, err = Parse(filepath.Join("testdata", tt.file))
+			} else {
+				_, err = ParseBytes(filepath.Join("testdata", tt.file), []byte(tt.data))
+			}
+			var perr *Error
+			if !errors.As(err, &perr) {
+				t.Fatalf("error = %v, want *Error", err)
+			}
+			if !strings.HasPrefix(filepath.ToSlash(err.Error()), tt.want) && !strings.Contains(err.Error(), tt.want) {
+				t.Errorf("error = %q, want %q", err, tt.want)
+			}
+		})
+	}
+}
+
+// TestLoadTypeError reports decode errors at the position of the offending value.
+func TestLoadTypeError(t *testing.T) {
+	t.Parallel()
+
+	path := filepath.Join(t.TempDir(), "kea.conf")
+	data := "{\n  \"Dhcp4\": {\n    \"subnet4\": [ { \"id\": \"one\" } ]\n  }\n}\n"
+	if err := os.WriteFile(path, []byte(data), 0o644); err != nil {
+		t.Fatal(err)
+	}
+
+	var cfg struct {
+		Dhcp4 struct {
+			Subnet4 []struct {
+				ID int `json:"id"`
+			} `json:"subnet4"`
+		}
+	}
+	_, err := Load(path, &cfg)
+	var perr *Error
+	if !errors.As(err, &perr) {
+		t.Fatalf("Load() error = %v, want *Error", err)
+	}
+	if want := (Position{path, 3, 26}); perr.Pos != want {
+		t.Errorf("Load() error position = %v, want %v", perr.Pos, want)
+	}
+	var typeErr *json.UnmarshalTypeError
+	if !errors.As(err, &typeErr) {
+		t.Errorf("Load() error does not wrap *json.UnmarshalTypeError")
+	}
+}
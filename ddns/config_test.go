@@ -0,0 +1,60 @@
+package ddns
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/rannday/kea-api/client"
+	"github.com/rannday/kea-api/internal/testenv"
+)
+
+// TestConfigApply checks the hash, then tests and sets the configuration without its hash.
+func TestConfigApply(t *testing.T) {
+	t.Parallel()
+
+	var cfg DdnsConfig
+	if err := json.Unmarshal([]byte(`{"DhcpDdns": {"ip-address": "127.0.0.1", "port": 53001}, "hash": "ABC123"}`), &cfg); err != nil {
+		t.Fatalf("failed to decode test config: %v", err)
+	}
+	sent := map[string]any{"DhcpDdns": json.RawMessage(`{"ip-address": "127.0.0.1", "port": 53001}`)}
+
+	mockClient := testenv.NewSequenceMockClient(t,
+		testenv.MockStep{
+			Validate:  testenv.ExpectCommandArgs(t, "config-hash-get", nil, client.Services.DDNS),
+			Responses: []client.CommandResponse{{Result: client.ResultSuccess, Arguments: json.RawMessage(`{"hash": "ABC123"}`)}},
+		},
+		testenv.MockStep{
+			Validate:  testenv.ExpectCommandArgs(t, "config-test", sent, client.Services.DDNS),
+			Responses: []client.CommandResponse{{Result: client.ResultSuccess}},
+		},
+		testenv.MockStep{
+			Validate:  testenv.ExpectCommandArgs(t, "config-set", sent, client.Services.DDNS),
+			Responses: []client.CommandResponse{{Result: client.ResultSuccess, Text: "Configuration applied successfully."}},
+		},
+	)
+
+	report, err := ConfigApply(mockClient, cfg, client.ConfigApplyOptions{})
+	if err != nil {
+		t.Fatalf("ConfigApply() error = %v", err)
+	}
+	if !report.Applied || report.Written != nil || len(report.Steps) != 3 {
+		t.Errorf("ConfigApply() report = %+v", report)
+	}
+}
+
+// TestLoadConfigFile reads a commented kea-dhcp-ddns.conf into the typed configuration.
+func TestLoadConfigFile(t *testing.T) {
+	t.Parallel()
+
+	cfg, err := LoadConfigFile("testdata/kea-dhcp-ddns.conf")
+	if err != nil {
+		t.Fatalf("LoadConfigFile() error = %v", err)
+	}
+	d := cfg.DhcpDdns
+	if d.IPAddress != "127.0.0.1" || d.Port != 53001 {
+		t.Errorf("LoadConfigFile() listener = %s:%d", d.IPAddress, d.Port)
+	}
+	if len(d.TSIGKeys) != 1 || d.TSIGKeys[0].KeyName != "key.example.org" {
+		t.Errorf("LoadConfigFile() tsig-keys = %+v", d.TSIGKeys)
+	}
+}
@@ -1,6 +1,10 @@
 package ddns
 
-import "github.com/rannday/kea-api/client"
+import (
+	"context"
+
+	"github.com/rannday/kea-api/client"
+)
 
 /*
  * Commands supported by kea-dhcp-ddns daemon: build-report, config-get, config-hash-get, config-reload, config-set, config-test,
@@ -11,5 +15,10 @@ import "github.com/rannday/kea-api/client"
 
 // ConfigGet retrieves the configuration from the Kea DDNS (d2) service.
 func ConfigGet(c *client.Client) (DdnsConfig, error) {
-	return client.ConfigGet[DdnsConfig](c, "d2")
+	return ConfigGetContext(context.Background(), c)
+}
+
+// ConfigGetContext is like ConfigGet but honours ctx.
+func ConfigGetContext(ctx context.Context, c *client.Client) (DdnsConfig, error) {
+	return client.ConfigGetContext[DdnsConfig](ctx, c, client.Services.DDNS)
 }
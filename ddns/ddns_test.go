@@ -1 +1,41 @@
 package ddns
+
+import (
+	"encoding/json"
+	"os"
+	"testing"
+
+	"github.com/rannday/kea-api/client"
+	"github.com/rannday/kea-api/internal/testenv"
+)
+
+// TestConfigGetRoundTrip re-encodes a config-get reply and checks that nothing was added or lost.
+func TestConfigGetRoundTrip(t *testing.T) {
+	t.Parallel()
+
+	raw, err := os.ReadFile("testdata/config-get.json")
+	if err != nil {
+		t.Fatalf("failed to read fixture: %v", err)
+	}
+	mockClient := testenv.NewMockClient(t,
+		testenv.ExpectCommand(t, "config-get", client.Services.DDNS),
+		[]client.CommandResponse{{Result: client.ResultSuccess, Arguments: raw}},
+	)
+
+	cfg, err := ConfigGet(mockClient)
+	if err != nil {
+		t.Fatalf("ConfigGet() error = %v", err)
+	}
+	out, err := json.Marshal(cfg)
+	if err != nil {
+		t.Fatalf("Marshal() error = %v", err)
+	}
+	testenv.ExpectJSONEquivalent(t, out, raw)
+
+	if got := cfg.DhcpDdns.TSIGKeys[0].KeyName; got != "d2.md5.key" {
+		t.Errorf("TSIGKeys[0].KeyName = %q, want d2.md5.key", got)
+	}
+	if _, ok := cfg.DhcpDdns.Extra["user-context"]; !ok {
+		t.Errorf("DhcpDdns.Extra = %v, want user-context", cfg.DhcpDdns.Extra)
+	}
+}
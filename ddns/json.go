@@ -0,0 +1,45 @@
+package ddns
+
+import "github.com/rannday/kea-api/internal/utils"
+
+// JSON codecs keeping the config types of this package lossless, Extra included; see utils.DecodeObject.
+
+func (d *DdnsConfig) UnmarshalJSON(data []byte) error {
+	type plain DdnsConfig
+	return utils.DecodeObject(data, (*plain)(d), &d.Extra, &d.absent)
+}
+
+func (d DdnsConfig) MarshalJSON() ([]byte, error) {
+	type plain DdnsConfig
+	return utils.EncodeObject(plain(d), d.Extra, d.absent)
+}
+
+func (d *DhcpDdnsBlock) UnmarshalJSON(data []byte) error {
+	type plain DhcpDdnsBlock
+	return utils.DecodeObject(data, (*plain)(d), &d.Extra, &d.absent)
+}
+
+func (d DhcpDdnsBlock) MarshalJSON() ([]byte, error) {
+	type plain DhcpDdnsBlock
+	return utils.EncodeObject(plain(d), d.Extra, d.absent)
+}
+
+func (d *DdnsDirectionConfig) UnmarshalJSON(data []byte) error {
+	type plain DdnsDirectionConfig
+	return utils.DecodeObject(data, (*plain)(d), &d.Extra, &d.absent)
+}
+
+func (d DdnsDirectionConfig) MarshalJSON() ([]byte, error) {
+	type plain DdnsDirectionConfig
+	return utils.EncodeObject(plain(d), d.Extra, d.absent)
+}
+
+func (t *TsigKey) UnmarshalJSON(data []byte) error {
+	type plain TsigKey
+	return utils.DecodeObject(data, (*plain)(t), &t.Extra, &t.absent)
+}
+
+func (t TsigKey) MarshalJSON() ([]byte, error) {
+	type plain TsigKey
+	return utils.EncodeObject(plain(t), t.Extra, t.absent)
+}
@@ -0,0 +1,53 @@
+package ddns
+
+import (
+	"context"
+
+	"github.com/rannday/kea-api/client"
+	"github.com/rannday/kea-api/types"
+)
+
+/*
+ * Statistics of the running DDNS (d2) server. Unlike the DHCP servers, d2 does not
+ * support removing statistics or changing sample limits.
+ */
+
+// StatisticGet fetches the samples of a single statistic.
+func StatisticGet(c *client.Client, name string) (types.Statistic, error) {
+	return StatisticGetContext(context.Background(), c, name)
+}
+
+// StatisticGetContext is like StatisticGet but honours ctx.
+func StatisticGetContext(ctx context.Context, c *client.Client, name string) (types.Statistic, error) {
+	return client.StatisticGetContext(ctx, c, client.Services.DDNS, name)
+}
+
+// StatisticGetAll fetches every statistic, sorted by name.
+func StatisticGetAll(c *client.Client) ([]types.Statistic, error) {
+	return StatisticGetAllContext(context.Background(), c)
+}
+
+// StatisticGetAllContext is like StatisticGetAll but honours ctx.
+func StatisticGetAllContext(ctx context.Context, c *client.Client) ([]types.Statistic, error) {
+	return client.StatisticGetAllContext(ctx, c, client.Services.DDNS)
+}
+
+// StatisticReset sets a statistic back to its neutral value.
+func StatisticReset(c *client.Client, name string) error {
+	return StatisticResetContext(context.Background(), c, name)
+}
+
+// StatisticResetContext is like StatisticReset but honours ctx.
+func StatisticResetContext(ctx context.Context, c *client.Client, name string) error {
+	return client.StatisticResetContext(ctx, c, client.Services.DDNS, name)
+}
+
+// StatisticResetAll resets every statistic.
+func StatisticResetAll(c *client.Client) error {
+	return StatisticResetAllContext(context.Background(), c)
+}
+
+// StatisticResetAllContext is like StatisticResetAll but honours ctx.
+func StatisticResetAllContext(ctx context.Context, c *client.Client) error {
+	return client.StatisticResetAllContext(ctx, c, client.Services.DDNS)
+}
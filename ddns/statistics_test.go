@@ -0,0 +1,47 @@
+package ddns
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/rannday/kea-api/client"
+	"github.com/rannday/kea-api/internal/testenv"
+)
+
+// TestStatisticGetAll decodes d2 statistics, including per-key names.
+func TestStatisticGetAll(t *testing.T) {
+	t.Parallel()
+
+	mockClient := testenv.NewMockClient(t,
+		testenv.ExpectCommandArgs(t, "statistic-get-all", nil, client.Services.DDNS),
+		[]client.CommandResponse{{
+			Result: client.ResultSuccess,
+			Arguments: json.RawMessage(`{
+				"ncr-received": [[1, "2019-07-30 10:11:19.498739"]],
+				"key[foo.example.org.].update-sent": [[1, "2019-07-30 10:11:19.498739"]]
+			}`),
+		}},
+	)
+
+	got, err := StatisticGetAll(mockClient)
+	if err != nil {
+		t.Fatalf("StatisticGetAll() error = %v", err)
+	}
+	if len(got) != 2 || got[0].Name != "key[foo.example.org.].update-sent" || got[1].Name != "ncr-received" {
+		t.Errorf("StatisticGetAll() = %+v", got)
+	}
+}
+
+// TestStatisticReset sends the statistic name to d2.
+func TestStatisticReset(t *testing.T) {
+	t.Parallel()
+
+	mockClient := testenv.NewMockClient(t,
+		testenv.ExpectCommandArgs(t, "statistic-reset", map[string]any{"name": "ncr-error"}, client.Services.DDNS),
+		[]client.CommandResponse{{Result: client.ResultSuccess}},
+	)
+
+	if err := StatisticReset(mockClient, "ncr-error"); err != nil {
+		t.Errorf("StatisticReset() error = %v", err)
+	}
+}
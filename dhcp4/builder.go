@@ -0,0 +1,371 @@
+package dhcp4
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/netip"
+
+	"github.com/rannday/kea-api/types"
+)
+
+/*
+ * Configuration builder. A Dhcp4Block written as a struct literal emits every modelled
+ * member, zero or not; ConfigBuilder starts from an empty decoded configuration so
+ * that only the parameters it is given end up in the output.
+ */
+
+// ConfigBuilder assembles a Dhcp4Config step by step, e.g.
+//
+//	cfg, err := dhcp4.NewConfig().
+//		Interfaces("eth0").
+//		Subnet("192.0.2.0/24", 1).
+//		Pool("192.0.2.10 - 192.0.2.100").
+//		Option("routers", "192.0.2.1").
+//		Reservation("aa:bb:cc:dd:ee:ff", "192.0.2.5").
+//		Build()
+//
+// Each step checks what it adds; mistakes are collected and reported by Err and Build.
+type ConfigBuilder struct {
+	cfg     Dhcp4Config
+	autoIDs bool
+	errs    []error
+}
+
+// NewConfig returns a builder for an empty DHCPv4 configuration.
+func NewConfig() *ConfigBuilder {
+	b := &ConfigBuilder{}
+	_ = json.Unmarshal([]byte(`{"Dhcp4": {}}`), &b.cfg)
+	return b
+}
+
+func (b *ConfigBuilder) errorf(format string, args ...interface{}) {
+	b.errs = append(b.errs, fmt.Errorf(format, args...))
+}
+
+// decodeInto sets v, a pointer to a config type, from its JSON members so that
+// members not given are left out when v is encoded.
+func (b *ConfigBuilder) decodeInto(v interface{}, members map[string]interface{}) {
+	data, err := json.Marshal(members)
+	if err == nil {
+		err = json.Unmarshal(data, v)
+	}
+	if err != nil {
+		b.errs = append(b.errs, err)
+	}
+}
+
+// AutoSubnetIDs makes Build assign the next free id to subnets added with id 0.
+// Without it every subnet needs an explicit id.
+func (b *ConfigBuilder) AutoSubnetIDs() *ConfigBuilder {
+	b.autoIDs = true
+	return b
+}
+
+// Interfaces sets the interfaces the server listens on, e.g. "eth0" or "eth1/192.0.2.1".
+func (b *ConfigBuilder) Interfaces(names ...string) *ConfigBuilder {
+	b.decodeInto(&b.cfg.Dhcp4.InterfacesConfig, map[string]interface{}{"interfaces": names})
+	return b
+}
+
+// ControlSocket sets the server's UNIX control socket.
+func (b *ConfigBuilder) ControlSocket(socketName string) *ConfigBuilder {
+	b.decodeInto(&b.cfg.Dhcp4.ControlSocket, map[string]interface{}{"socket-type": "unix", "socket-name": socketName})
+	return b
+}
+
+// ValidLifetime sets the global lease lifetime in seconds.
+func (b *ConfigBuilder) ValidLifetime(seconds int) *ConfigBuilder {
+	b.cfg.Dhcp4.ValidLifetime = seconds
+	return b
+}
+
+// Timers sets the global renew (T1) and rebind (T2) timers in seconds.
+func (b *ConfigBuilder) Timers(renew, rebind int) *ConfigBuilder {
+	if renew > rebind {
+		b.errorf("renew-timer %d exceeds rebind-timer %d", renew, rebind)
+	}
+	b.cfg.Dhcp4.RenewTimer, b.cfg.Dhcp4.RebindTimer = renew, rebind
+	return b
+}
+
+// OptionDef defines a custom option. Define options before using them by name.
+func (b *ConfigBuilder) OptionDef(def types.OptionDef) *ConfigBuilder {
+	for _, d := range b.cfg.Dhcp4.OptionDef {
+		if d.Name == def.Name && d.Space == def.Space {
+			b.errorf("option-def %q is already defined", def.Name)
+			return b
+		}
+	}
+	b.cfg.Dhcp4.OptionDef = append(b.cfg.Dhcp4.OptionDef, def)
+	return b
+}
+
+// Option adds a global option in the dhcp4 space.
+func (b *ConfigBuilder) Option(name, data string) *ConfigBuilder {
+	b.cfg.Dhcp4.OptionData = b.option(b.cfg.Dhcp4.OptionData, "global", name, data)
+	return b
+}
+
+// option checks name and appends it to list; scope names the list in errors.
+func (b *ConfigBuilder) option(list []types.OptionData, scope, name, data string) []types.OptionData {
+	if _, ok := standardOptions[name]; !ok && !b.definedOption(name) {
+		b.errorf("%s: option %q is not a standard dhcp4 option and has no option-def", scope, name)
+	}
+	return append(list, types.OptionData{Name: name, Data: data})
+}
+
+func (b *ConfigBuilder) definedOption(name string) bool {
+	for _, d := range b.cfg.Dhcp4.OptionDef {
+		if d.Name == name && (d.Space == "" || d.Space == "dhcp4") {
+			return true
+		}
+	}
+	return false
+}
+
+// Set applies fn to the configuration block, for parameters the builder has no step for.
+func (b *ConfigBuilder) Set(fn func(*Dhcp4Block)) *ConfigBuilder {
+	fn(&b.cfg.Dhcp4)
+	return b
+}
+
+// Subnet adds a subnet with the given id, or 0 to have Build assign one after AutoSubnetIDs.
+// The returned builder adds pools, options and reservations to it.
+func (b *ConfigBuilder) Subnet(prefix string, id int) *SubnetBuilder {
+	return b.addSubnet(-1, prefix, id)
+}
+
+// SharedNetwork adds a shared network. The returned builder adds subnets to it.
+func (b *ConfigBuilder) SharedNetwork(name string) *SharedNetworkBuilder {
+	for _, sn := range b.cfg.Dhcp4.SharedNetworks {
+		if sn.Name == name {
+			b.errorf("shared network %q is already defined", name)
+		}
+	}
+	b.cfg.Dhcp4.SharedNetworks = append(b.cfg.Dhcp4.SharedNetworks, SharedNetwork4{Name: name})
+	return &SharedNetworkBuilder{b: b, index: len(b.cfg.Dhcp4.SharedNetworks) - 1}
+}
+
+func (b *ConfigBuilder) addSubnet(network int, prefix string, id int) *SubnetBuilder {
+	p, err := netip.ParsePrefix(prefix)
+	if err != nil || !p.Addr().Is4() {
+		b.errorf("subnet %q: not an IPv4 prefix", prefix)
+	}
+	switch {
+	case id < 0:
+		b.errorf("subnet %s: invalid id %d", prefix, id)
+	case id == 0 && !b.autoIDs:
+		b.errorf("subnet %s: id is required unless AutoSubnetIDs is set", prefix)
+	case id > 0:
+		b.eachSubnet(func(s *Subnet4) {
+			if s.ID == id {
+				b.errorf("subnet %s: id %d is already used by subnet %s", prefix, id, s.Subnet)
+			}
+		})
+	}
+
+	list := &b.cfg.Dhcp4.Subnet4
+	if network >= 0 {
+		list = &b.cfg.Dhcp4.SharedNetworks[network].Subnet4
+	}
+	*list = append(*list, Subnet4{ID: id, Subnet: prefix})
+	return &SubnetBuilder{b: b, network: network, index: len(*list) - 1, prefix: p.Masked()}
+}
+
+// eachSubnet calls fn for every subnet, in shared networks first.
+func (b *ConfigBuilder) eachSubnet(fn func(*Subnet4)) {
+	for i := range b.cfg.Dhcp4.SharedNetworks {
+		for j := range b.cfg.Dhcp4.SharedNetworks[i].Subnet4 {
+			fn(&b.cfg.Dhcp4.SharedNetworks[i].Subnet4[j])
+		}
+	}
+	for i := range b.cfg.Dhcp4.Subnet4 {
+		fn(&b.cfg.Dhcp4.Subnet4[i])
+	}
+}
+
+// Err returns the mistakes found so far, joined, or nil.
+func (b *ConfigBuilder) Err() error {
+	return errors.Join(b.errs...)
+}
+
+// Build assigns any automatic subnet ids, validates the whole configuration with
+// Validate and returns it. Errors found along the way are returned joined; a
+// configuration that fails validation yields a *types.ValidationError.
+func (b *ConfigBuilder) Build() (Dhcp4Config, error) {
+	if err := b.Err(); err != nil {
+		return Dhcp4Config{}, err
+	}
+
+	used := make(map[int]bool)
+	b.eachSubnet(func(s *Subnet4) { used[s.ID] = true })
+	next := 1
+	b.eachSubnet(func(s *Subnet4) {
+		if s.ID != 0 {
+			return
+		}
+		for used[next] {
+			next++
+		}
+		s.ID, used[next] = next, true
+	})
+
+	if err := Validate(b.cfg.Dhcp4, types.ValidateOptions{}).Err(); err != nil {
+		return Dhcp4Config{}, err
+	}
+
+	// Round trip so the result shares nothing with the builder.
+	data, err := json.Marshal(b.cfg)
+	if err != nil {
+		return Dhcp4Config{}, err
+	}
+	var cfg Dhcp4Config
+	if err := json.Unmarshal(data, &cfg); err != nil {
+		return Dhcp4Config{}, err
+	}
+	return cfg, nil
+}
+
+// SharedNetworkBuilder adds to a shared network created by ConfigBuilder.SharedNetwork.
+type SharedNetworkBuilder struct {
+	b     *ConfigBuilder
+	index int
+}
+
+func (n *SharedNetworkBuilder) network() *SharedNetwork4 {
+	return &n.b.cfg.Dhcp4.SharedNetworks[n.index]
+}
+
+// Interface sets the interface the shared network is reached on.
+func (n *SharedNetworkBuilder) Interface(name string) *SharedNetworkBuilder {
+	n.network().Interface = name
+	return n
+}
+
+// Option adds an option for all subnets of the shared network.
+func (n *SharedNetworkBuilder) Option(name, data string) *SharedNetworkBuilder {
+	sn := n.network()
+	sn.OptionData = n.b.option(sn.OptionData, "shared network "+sn.Name, name, data)
+	return n
+}
+
+// Set applies fn to the shared network, for parameters the builder has no step for.
+func (n *SharedNetworkBuilder) Set(fn func(*SharedNetwork4)) *SharedNetworkBuilder {
+	fn(n.network())
+	return n
+}
+
+// Subnet adds a subnet to the shared network; see ConfigBuilder.Subnet.
+func (n *SharedNetworkBuilder) Subnet(prefix string, id int) *SubnetBuilder {
+	return n.b.addSubnet(n.index, prefix, id)
+}
+
+// Done returns to the configuration builder.
+func (n *SharedNetworkBuilder) Done() *ConfigBuilder {
+	return n.b
+}
+
+// Build is shorthand for Done().Build().
+func (n *SharedNetworkBuilder) Build() (Dhcp4Config, error) {
+	return n.b.Build()
+}
+
+// SubnetBuilder adds to a subnet created by ConfigBuilder.Subnet or SharedNetworkBuilder.Subnet.
+type SubnetBuilder struct {
+	b       *ConfigBuilder
+	network int // index into shared-networks, or -1 for the top-level subnet4 list
+	index   int
+	prefix  netip.Prefix
+}
+
+func (s *SubnetBuilder) subnet() *Subnet4 {
+	if s.network >= 0 {
+		return &s.b.cfg.Dhcp4.SharedNetworks[s.network].Subnet4[s.index]
+	}
+	return &s.b.cfg.Dhcp4.Subnet4[s.index]
+}
+
+// Pool adds an address pool given as "first - last" or in CIDR notation. It must lie
+// within the subnet and not overlap the subnet's other pools.
+func (s *SubnetBuilder) Pool(pool string) *SubnetBuilder {
+	sub := s.subnet()
+	first, last, err := types.ParsePool(pool)
+	switch {
+	case err != nil:
+		s.b.errorf("subnet %s: %v", sub.Subnet, err)
+	case s.prefix.IsValid() && (!s.prefix.Contains(first) || !s.prefix.Contains(last)):
+		s.b.errorf("subnet %s: pool %s is outside the subnet", sub.Subnet, pool)
+	default:
+		for _, p := range sub.Pools {
+			if f, l, err := p.Range(); err == nil && !l.Less(first) && !last.Less(f) {
+				s.b.errorf("subnet %s: pool %s overlaps pool %s", sub.Subnet, pool, p.Pool)
+			}
+		}
+	}
+	sub.Pools = append(sub.Pools, Pool4{Pool: pool})
+	return s
+}
+
+// Option adds an option for clients in the subnet.
+func (s *SubnetBuilder) Option(name, data string) *SubnetBuilder {
+	sub := s.subnet()
+	sub.OptionData = s.b.option(sub.OptionData, "subnet "+sub.Subnet, name, data)
+	return s
+}
+
+// Reservation reserves ipAddress, which must lie within the subnet, for the client with hwAddress.
+func (s *SubnetBuilder) Reservation(hwAddress, ipAddress string) *SubnetBuilder {
+	sub := s.subnet()
+	addr, err := netip.ParseAddr(ipAddress)
+	switch {
+	case err != nil || !addr.Is4():
+		s.b.errorf("subnet %s: invalid reserved address %q", sub.Subnet, ipAddress)
+	case s.prefix.IsValid() && !s.prefix.Contains(addr):
+		s.b.errorf("subnet %s: reserved address %s is outside the subnet", sub.Subnet, ipAddress)
+	}
+	for _, r := range sub.Reservations {
+		if r.HWAddress == hwAddress {
+			s.b.errorf("subnet %s: hw-address %s already has a reservation", sub.Subnet, hwAddress)
+		}
+	}
+	sub.Reservations = append(sub.Reservations, Reservation4{HWAddress: hwAddress, IPAddress: ipAddress})
+	return s
+}
+
+// ValidLifetime sets the subnet's lease lifetime in seconds.
+func (s *SubnetBuilder) ValidLifetime(seconds int) *SubnetBuilder {
+	s.subnet().ValidLifetime = seconds
+	return s
+}
+
+// Timers sets the subnet's renew (T1) and rebind (T2) timers in seconds.
+func (s *SubnetBuilder) Timers(renew, rebind int) *SubnetBuilder {
+	sub := s.subnet()
+	if renew > rebind {
+		s.b.errorf("subnet %s: renew-timer %d exceeds rebind-timer %d", sub.Subnet, renew, rebind)
+	}
+	sub.RenewTimer, sub.RebindTimer = renew, rebind
+	return s
+}
+
+// Set applies fn to the subnet, for parameters the builder has no step for.
+func (s *SubnetBuilder) Set(fn func(*Subnet4)) *SubnetBuilder {
+	fn(s.subnet())
+	return s
+}
+
+// Subnet adds another subnet next to this one, in the same shared network if any.
+func (s *SubnetBuilder) Subnet(prefix string, id int) *SubnetBuilder {
+	return s.b.addSubnet(s.network, prefix, id)
+}
+
+// Done returns to the configuration builder.
+func (s *SubnetBuilder) Done() *ConfigBuilder {
+	return s.b
+}
+
+// Build is shorthand for Done().Build().
+func (s *SubnetBuilder) Build() (Dhcp4Config, error) {
+	return s.b.Build()
+}
@@ -0,0 +1,141 @@
+package dhcp4
+
+import (
+	"encoding/json"
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/rannday/kea-api/internal/testenv"
+	"github.com/rannday/kea-api/types"
+)
+
+// TestConfigBuilder emits only the parameters it was given.
+func TestConfigBuilder(t *testing.T) {
+	t.Parallel()
+
+	cfg, err := NewConfig().
+		Interfaces("eth0").
+		ControlSocket("/run/kea/kea4-ctrl-socket").
+		ValidLifetime(3600).
+		Timers(900, 1800).
+		OptionDef(types.OptionDef{Name: "my-option", Code: 222, Type: "string"}).
+		Option("domain-name-servers", "192.0.2.53").
+		Subnet("192.0.2.0/24", 1).
+		Pool("192.0.2.10 - 192.0.2.100").
+		Option("routers", "192.0.2.1").
+		Reservation("aa:bb:cc:dd:ee:ff", "192.0.2.5").
+		Done().
+		SharedNetwork("office").
+		Interface("eth1").
+		Option("my-option", "hello").
+		Subnet("198.51.100.0/24", 2).
+		Pool("198.51.100.0/25").
+		Build()
+	if err != nil {
+		t.Fatalf("Build() error = %v", err)
+	}
+
+	got, err := json.Marshal(cfg)
+	if err != nil {
+		t.Fatal(err)
+	}
+	testenv.ExpectJSONEquivalent(t, got, []byte(`{"Dhcp4": {
+		"interfaces-config": {"interfaces": ["eth0"]},
+		"control-socket": {"socket-type": "unix", "socket-name": "/run/kea/kea4-ctrl-socket"},
+		"valid-lifetime": 3600,
+		"renew-timer": 900,
+		"rebind-timer": 1800,
+		"option-def": [{"name": "my-option", "code": 222, "type": "string"}],
+		"option-data": [{"name": "domain-name-servers", "data": "192.0.2.53"}],
+		"subnet4": [{
+			"id": 1,
+			"subnet": "192.0.2.0/24",
+			"pools": [{"pool": "192.0.2.10 - 192.0.2.100"}],
+			"option-data": [{"name": "routers", "data": "192.0.2.1"}],
+			"reservations": [{"hw-address": "aa:bb:cc:dd:ee:ff", "ip-address": "192.0.2.5"}]
+		}],
+		"shared-networks": [{
+			"name": "office",
+			"interface": "eth1",
+			"option-data": [{"name": "my-option", "data": "hello"}],
+			"subnet4": [{"id": 2, "subnet": "198.51.100.0/24", "pools": [{"pool": "198.51.100.0/25"}]}]
+		}]
+	}}`))
+}
+
+// TestConfigBuilderAutoSubnetIDs assigns free ids to subnets added with id 0.
+func TestConfigBuilderAutoSubnetIDs(t *testing.T) {
+	t.Parallel()
+
+	cfg, err := NewConfig().AutoSubnetIDs().
+		Subnet("192.0.2.0/24", 0).
+		Subnet("198.51.100.0/24", 1).
+		Subnet("203.0.113.0/24", 0).
+		Build()
+	if err != nil {
+		t.Fatalf("Build() error = %v", err)
+	}
+	var ids []int
+	for _, s := range cfg.Dhcp4.Subnet4 {
+		ids = append(ids, s.ID)
+	}
+	if len(ids) != 3 || ids[0] != 2 || ids[1] != 1 || ids[2] != 3 {
+		t.Errorf("Build() subnet ids = %v, want [2 1 3]", ids)
+	}
+}
+
+// TestConfigBuilderErrors collects mistakes from each step.
+func TestConfigBuilderErrors(t *testing.T) {
+	t.Parallel()
+
+	_, err := NewConfig().
+		Option("router", "192.0.2.1").
+		Subnet("192.0.2.0/24", 1).
+		Pool("192.0.2.10 - 192.0.2.100").
+		Pool("192.0.2.64/26").
+		Pool("192.0.3.0/28").
+		Reservation("aa:bb:cc:dd:ee:ff", "10.0.0.1").
+		Timers(2000, 1000).
+		Subnet("198.51.100.0/24", 1).
+		Subnet("2001:db8::/64", 3).
+		Subnet("203.0.113.0/24", 0).
+		Build()
+	if err == nil {
+		t.Fatal("Build() error = nil")
+	}
+	for _, want := range []string{
+		`global: option "router" is not a standard dhcp4 option`,
+		"pool 192.0.2.64/26 overlaps pool 192.0.2.10 - 192.0.2.100",
+		"pool 192.0.3.0/28 is outside the subnet",
+		"reserved address 10.0.0.1 is outside the subnet",
+		"renew-timer 2000 exceeds rebind-timer 1000",
+		"id 1 is already used by subnet 192.0.2.0/24",
+		`subnet "2001:db8::/64": not an IPv4 prefix`,
+		"subnet 203.0.113.0/24: id is required",
+	} {
+		if !strings.Contains(err.Error(), want) {
+			t.Errorf("Build() error does not mention %q:\n%v", want, err)
+		}
+	}
+}
+
+// TestConfigBuilderValidation runs Validate on the finished configuration.
+func TestConfigBuilderValidation(t *testing.T) {
+	t.Parallel()
+
+	_, err := NewConfig().
+		Set(func(b *Dhcp4Block) { b.ReservationsOutOfPool = true }).
+		Subnet("192.0.2.0/24", 1).
+		Pool("192.0.2.10 - 192.0.2.100").
+		Reservation("aa:bb:cc:dd:ee:ff", "192.0.2.20").
+		Build()
+
+	var verr *types.ValidationError
+	if !errors.As(err, &verr) {
+		t.Fatalf("Build() error = %v, want *types.ValidationError", err)
+	}
+	if len(verr.Diagnostics) != 1 || verr.Diagnostics[0].Code != types.DiagReservationInPool {
+		t.Errorf("Build() diagnostics = %v", verr.Diagnostics)
+	}
+}
@@ -0,0 +1,200 @@
+package dhcp4
+
+import (
+	"encoding/json"
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/rannday/kea-api/client"
+	"github.com/rannday/kea-api/configfile"
+	"github.com/rannday/kea-api/internal/testenv"
+)
+
+// testConfig4 is a minimal configuration as returned by config-get.
+const testConfig4 = `{"Dhcp4": {"valid-lifetime": 4000, "subnet4": [{"id": 1, "subnet": "192.0.2.0/24"}]}, "hash": "ABC123"}`
+
+func decodeTestConfig4(t *testing.T) Dhcp4Config {
+	t.Helper()
+	var cfg Dhcp4Config
+	if err := json.Unmarshal([]byte(testConfig4), &cfg); err != nil {
+		t.Fatalf("failed to decode test config: %v", err)
+	}
+	return cfg
+}
+
+// TestConfigApply runs the full workflow and sends the edited configuration without its hash.
+func TestConfigApply(t *testing.T) {
+	t.Parallel()
+
+	cfg := decodeTestConfig4(t)
+	cfg.Dhcp4.ValidLifetime = 3600
+	sent := map[string]any{"Dhcp4": map[string]any{"valid-lifetime": 3600, "subnet4": []any{map[string]any{"id": 1, "subnet": "192.0.2.0/24"}}}}
+
+	mockClient := testenv.NewSequenceMockClient(t,
+		testenv.MockStep{
+			Validate:  testenv.ExpectCommandArgs(t, "config-hash-get", nil, client.Services.DHCP4),
+			Responses: []client.CommandResponse{{Result: client.ResultSuccess, Arguments: json.RawMessage(`{"hash": "abc123"}`)}},
+		},
+		testenv.MockStep{
+			Validate:  testenv.ExpectCommandArgs(t, "config-test", sent, client.Services.DHCP4),
+			Responses: []client.CommandResponse{{Result: client.ResultSuccess, Text: "Configuration seems sane."}},
+		},
+		testenv.MockStep{
+			Validate: testenv.ExpectCommandArgs(t, "config-set", sent, client.Services.DHCP4),
+			Responses: []client.CommandResponse{{
+				Result: client.ResultSuccess, Text: "Configuration successful.", Arguments: json.RawMessage(`{"hash": "DEF456"}`),
+			}},
+		},
+		testenv.MockStep{
+			Validate: testenv.ExpectCommandArgs(t, "config-write", map[string]any{"filename": "/etc/kea/kea-dhcp4.conf"}, client.Services.DHCP4),
+			Responses: []client.CommandResponse{{
+				Result: client.ResultSuccess, Arguments: json.RawMessage(`{"filename": "/etc/kea/kea-dhcp4.conf", "size": 2048}`),
+			}},
+		},
+	)
+
+	report, err := ConfigApply(mockClient, cfg, client.ConfigApplyOptions{Write: true, Filename: "/etc/kea/kea-dhcp4.conf"})
+	if err != nil {
+		t.Fatalf("ConfigApply() error = %v", err)
+	}
+	if !report.Applied || report.PreviousHash != "abc123" || report.Hash != "DEF456" {
+		t.Errorf("ConfigApply() report = %+v", report)
+	}
+	if report.Written == nil || report.Written.Size != 2048 {
+		t.Errorf("Written = %+v", report.Written)
+	}
+
+	var commands []string
+	for _, s := range report.Steps {
+		commands = append(commands, s.Command)
+		if s.Err != nil {
+			t.Errorf("step %s: %v", s.Command, s.Err)
+		}
+	}
+	if len(commands) != 4 || commands[1] != "config-test" || commands[3] != "config-write" {
+		t.Errorf("Steps = %v", commands)
+	}
+	if report.Steps[2].Text != "Configuration successful." {
+		t.Errorf("config-set text = %q", report.Steps[2].Text)
+	}
+}
+
+// TestConfigApplyHashMismatch stops before touching the server when its configuration changed.
+func TestConfigApplyHashMismatch(t *testing.T) {
+	t.Parallel()
+
+	mockClient := testenv.NewMockClient(t,
+		testenv.ExpectCommand(t, "config-hash-get", client.Services.DHCP4),
+		[]client.CommandResponse{{Result: client.ResultSuccess, Arguments: json.RawMessage(`{"hash": "OTHER"}`)}},
+	)
+
+	report, err := ConfigApply(mockClient, decodeTestConfig4(t), client.ConfigApplyOptions{Write: true})
+	if !errors.Is(err, client.ErrConfigHashMismatch) {
+		t.Fatalf("ConfigApply() error = %v, want ErrConfigHashMismatch", err)
+	}
+	if report.Applied || len(report.Steps) != 1 || report.Steps[0].Err == nil {
+		t.Errorf("ConfigApply() report = %+v", report)
+	}
+}
+
+// TestConfigApplyTestFailure does not set a configuration the server rejects.
+func TestConfigApplyTestFailure(t *testing.T) {
+	t.Parallel()
+
+	mockClient := testenv.NewSequenceMockClient(t,
+		testenv.MockStep{
+			Validate:  testenv.ExpectCommand(t, "config-test", client.Services.DHCP4),
+			Responses: []client.CommandResponse{{Result: client.ResultGeneralFailure, Text: "subnet4 is not a list"}},
+		},
+	)
+
+	report, err := ConfigApply(mockClient, decodeTestConfig4(t), client.ConfigApplyOptions{SkipHashCheck: true})
+	if err == nil {
+		t.Fatal("ConfigApply() succeeded, want error")
+	}
+	if report.Applied || len(report.Steps) != 1 || report.Steps[0].Command != "config-test" {
+		t.Errorf("ConfigApply() report = %+v", report)
+	}
+}
+
+// TestConfigApplyWithoutHash refuses to apply a configuration that was not read from the server.
+func TestConfigApplyWithoutHash(t *testing.T) {
+	t.Parallel()
+
+	mockClient := testenv.NewSequenceMockClient(t)
+	if _, err := ConfigApply(mockClient, Dhcp4Config{}, client.ConfigApplyOptions{}); err == nil {
+		t.Error("ConfigApply() succeeded, want error")
+	}
+}
+
+// TestConfigCommands covers the single-command wrappers.
+func TestConfigCommands(t *testing.T) {
+	t.Parallel()
+
+	mockClient := testenv.NewSequenceMockClient(t,
+		testenv.MockStep{
+			Validate:  testenv.ExpectCommandArgs(t, "config-hash-get", nil, client.Services.DHCP4),
+			Responses: []client.CommandResponse{{Result: client.ResultSuccess, Arguments: json.RawMessage(`{"hash": "ABC123"}`)}},
+		},
+		testenv.MockStep{
+			Validate:  testenv.ExpectCommandArgs(t, "config-write", nil, client.Services.DHCP4),
+			Responses: []client.CommandResponse{{Result: client.ResultSuccess, Arguments: json.RawMessage(`{"filename": "kea-dhcp4.conf", "size": 10}`)}},
+		},
+		testenv.MockStep{
+			Validate:  testenv.ExpectCommandArgs(t, "config-reload", nil, client.Services.DHCP4),
+			Responses: []client.CommandResponse{{Result: client.ResultSuccess, Text: "Configuration successful."}},
+		},
+	)
+
+	if hash, err := ConfigHashGet(mockClient); err != nil || hash != "ABC123" {
+		t.Errorf("ConfigHashGet() = %q, %v", hash, err)
+	}
+	if res, err := ConfigWrite(mockClient, ""); err != nil || res.Filename != "kea-dhcp4.conf" {
+		t.Errorf("ConfigWrite() = %+v, %v", res, err)
+	}
+	if text, err := ConfigReload(mockClient); err != nil || text != "Configuration successful." {
+		t.Errorf("ConfigReload() = %q, %v", text, err)
+	}
+}
+
+// TestLoadConfigFile reads a commented kea-dhcp4.conf into the typed configuration.
+func TestLoadConfigFile(t *testing.T) {
+	t.Parallel()
+
+	cfg, err := LoadConfigFile("testdata/kea-dhcp4.conf")
+	if err != nil {
+		t.Fatalf("LoadConfigFile() error = %v", err)
+	}
+	d := cfg.Dhcp4
+	if d.ValidLifetime != 4000 || d.ControlSocket.SocketName != "/run/kea/kea4-ctrl-socket" {
+		t.Errorf("LoadConfigFile() globals = %d, %q", d.ValidLifetime, d.ControlSocket.SocketName)
+	}
+	if len(d.Subnet4) != 1 || d.Subnet4[0].Subnet != "192.0.2.0/24" || len(d.Subnet4[0].Pools) != 1 {
+		t.Fatalf("LoadConfigFile() subnets = %+v", d.Subnet4)
+	}
+	if opts := d.Subnet4[0].OptionData; len(opts) != 1 || opts[0].Name != "routers" || opts[0].Data != "192.0.2.1" {
+		t.Errorf("LoadConfigFile() option-data = %+v", opts)
+	}
+}
+
+// TestLoadConfigFileTypeError reports a mistyped member at its position in the file.
+func TestLoadConfigFileTypeError(t *testing.T) {
+	t.Parallel()
+
+	path := filepath.Join(t.TempDir(), "kea-dhcp4.conf")
+	data := "{\n  \"Dhcp4\": {\n    \"subnet4\": [ { \"id\": 1 }, { \"id\": \"two\" } ]\n  }\n}\n"
+	if err := os.WriteFile(path, []byte(data), 0o644); err != nil {
+		t.Fatal(err)
+	}
+
+	_, err := LoadConfigFile(path)
+	var perr *configfile.Error
+	if !errors.As(err, &perr) {
+		t.Fatalf("LoadConfigFile() error = %v, want *configfile.Error", err)
+	}
+	if want := (configfile.Position{File: path, Line: 3, Col: 39}); perr.Pos != want {
+		t.Errorf("LoadConfigFile() error at %v, want %v", perr.Pos, want)
+	}
+}
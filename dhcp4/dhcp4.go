@@ -1,6 +1,8 @@
 package dhcp4
 
 import (
+	"context"
+
 	"github.com/rannday/kea-api/client"
 )
 
@@ -30,25 +32,50 @@ import (
 
 // BuildReport fetches the build configuration report of the DHCPv4 server.
 func BuildReport(c *client.Client) (string, error) {
-	return client.BuildReport(c, client.Services.DHCP4)
+	return BuildReportContext(context.Background(), c)
+}
+
+// BuildReportContext is like BuildReport but honours ctx.
+func BuildReportContext(ctx context.Context, c *client.Client) (string, error) {
+	return client.BuildReportContext(ctx, c, client.Services.DHCP4)
 }
 
 // ConfigGet fetches the DHCPv4 configuration using the generic helper.
 func ConfigGet(c *client.Client) (Dhcp4Config, error) {
-	return client.ConfigGet[Dhcp4Config](c, client.Services.DHCP4)
+	return ConfigGetContext(context.Background(), c)
+}
+
+// ConfigGetContext is like ConfigGet but honours ctx.
+func ConfigGetContext(ctx context.Context, c *client.Client) (Dhcp4Config, error) {
+	return client.ConfigGetContext[Dhcp4Config](ctx, c, client.Services.DHCP4)
 }
 
 // StatusGet fetches the DHCPv4 server status using the generic helper.
 func StatusGet(c *client.Client) (DHCP4Status, error) {
-	return client.StatusGet[DHCP4Status](c, client.Services.DHCP4)
+	return StatusGetContext(context.Background(), c)
+}
+
+// StatusGetContext is like StatusGet but honours ctx.
+func StatusGetContext(ctx context.Context, c *client.Client) (DHCP4Status, error) {
+	return client.StatusGetContext[DHCP4Status](ctx, c, client.Services.DHCP4)
 }
 
 // ListCommands fetches the list of commands for DHCPv4.
 func ListCommands(c *client.Client) ([]string, error) {
-	return client.ListCommands(c, client.Services.DHCP4)
+	return ListCommandsContext(context.Background(), c)
+}
+
+// ListCommandsContext is like ListCommands but honours ctx.
+func ListCommandsContext(ctx context.Context, c *client.Client) ([]string, error) {
+	return client.ListCommandsContext(ctx, c, client.Services.DHCP4)
 }
 
 // VersionGet fetches the DHCPv4 server version using the shared helper.
 func VersionGet(c *client.Client) (string, DHCP4Version, error) {
-	return client.VersionGet[DHCP4Version](c, client.Services.DHCP4)
+	return VersionGetContext(context.Background(), c)
+}
+
+// VersionGetContext is like VersionGet but honours ctx.
+func VersionGetContext(ctx context.Context, c *client.Client) (string, DHCP4Version, error) {
+	return client.VersionGetContext[DHCP4Version](ctx, c, client.Services.DHCP4)
 }
@@ -1,6 +1,10 @@
 package dhcp4
 
 import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"os"
 	"reflect"
 	"testing"
 
@@ -66,10 +70,10 @@ func TestConfigGet(t *testing.T) {
 				ReDetect:   false,
 			},
 			ServerTag:      "default",
-			Subnet4:        []interface{}{},
-			OptionData:     []interface{}{},
-			OptionDef:      []interface{}{},
-			SharedNetworks: []interface{}{},
+			Subnet4:        []Subnet4{},
+			OptionData:     []types.OptionData{},
+			OptionDef:      []types.OptionDef{},
+			SharedNetworks: []SharedNetwork4{},
 			HostsDatabases: []types.DatabaseConfig{},
 		},
 		Hash: "abcdef1234567890",
@@ -93,6 +97,154 @@ func TestConfigGet(t *testing.T) {
 	}
 }
 
+// TestConfigGetSubnets decodes subnets, pools, shared networks and option definitions from a Kea config.
+func TestConfigGetSubnets(t *testing.T) {
+	t.Parallel()
+
+	mockClient := testenv.NewMockClient(t,
+		testenv.ExpectCommand(t, "config-get", client.Services.DHCP4),
+		[]client.CommandResponse{{
+			Result: client.ResultSuccess,
+			Arguments: json.RawMessage(`{"Dhcp4": {
+				"option-def": [{"name": "tftp-servers", "code": 150, "type": "ipv4-address", "array": true, "space": "dhcp4"}],
+				"option-data": [{"name": "domain-name-servers", "data": "192.0.2.1, 192.0.2.2"}],
+				"subnet4": [{
+					"id": 1,
+					"subnet": "192.0.2.0/24",
+					"pools": [
+						{"pool": "192.0.2.100 - 192.0.2.199", "client-class": "known"},
+						{"pool": "192.0.2.64/27", "pool-id": 7}
+					],
+					"relay": {"ip-addresses": ["10.0.0.1"]},
+					"require-client-classes": ["late"],
+					"reservations-in-subnet": true,
+					"reservations": [{"hw-address": "1a:1b:1c:1d:1e:1f", "ip-address": "192.0.2.10", "hostname": "printer"}],
+					"option-data": [{"name": "routers", "data": "192.0.2.1"}]
+				}],
+				"shared-networks": [{
+					"name": "floor13",
+					"interface": "eth1",
+					"client-classes": ["staff"],
+					"subnet4": [{"id": 5, "subnet": "198.51.100.0/24"}]
+				}]
+			}, "hash": "abc"}`),
+		}},
+	)
+
+	got, err := ConfigGet(mockClient)
+	if err != nil {
+		t.Fatalf("ConfigGet() error = %v", err)
+	}
+	cfg := got.Dhcp4
+
+	wantDef := []types.OptionDef{{Name: "tftp-servers", Code: 150, Type: "ipv4-address", Array: true, Space: "dhcp4"}}
+	if !reflect.DeepEqual(cfg.OptionDef, wantDef) {
+		t.Errorf("OptionDef = %+v, want %+v", cfg.OptionDef, wantDef)
+	}
+	if len(cfg.OptionData) != 1 || cfg.OptionData[0].Name != "domain-name-servers" {
+		t.Errorf("OptionData = %+v", cfg.OptionData)
+	}
+
+	if len(cfg.Subnet4) != 1 {
+		t.Fatalf("Subnet4 = %+v", cfg.Subnet4)
+	}
+	subnet := cfg.Subnet4[0]
+	if subnet.Relay == nil || !reflect.DeepEqual(subnet.Relay.IPAddresses, []string{"10.0.0.1"}) {
+		t.Errorf("Relay = %+v", subnet.Relay)
+	}
+	if !reflect.DeepEqual(subnet.RequireClientClasses, []string{"late"}) {
+		t.Errorf("RequireClientClasses = %v", subnet.RequireClientClasses)
+	}
+	if subnet.ReservationsInSubnet == nil || !*subnet.ReservationsInSubnet {
+		t.Errorf("ReservationsInSubnet = %v", subnet.ReservationsInSubnet)
+	}
+	if len(subnet.Reservations) != 1 || subnet.Reservations[0].Hostname != "printer" {
+		t.Errorf("Reservations = %+v", subnet.Reservations)
+	}
+	if len(subnet.Pools) != 2 || subnet.Pools[0].ClientClass != "known" || subnet.Pools[1].PoolID != 7 {
+		t.Fatalf("Pools = %+v", subnet.Pools)
+	}
+	first, last, err := subnet.Pools[1].Range()
+	if err != nil || first.String() != "192.0.2.64" || last.String() != "192.0.2.95" {
+		t.Errorf("Pools[1].Range() = %v, %v, %v", first, last, err)
+	}
+
+	if len(cfg.SharedNetworks) != 1 {
+		t.Fatalf("SharedNetworks = %+v", cfg.SharedNetworks)
+	}
+	network := cfg.SharedNetworks[0]
+	if network.Name != "floor13" || !reflect.DeepEqual(network.ClientClasses, []string{"staff"}) ||
+		len(network.Subnet4) != 1 || network.Subnet4[0].ID != 5 {
+		t.Errorf("SharedNetworks[0] = %+v", network)
+	}
+}
+
+// TestConfigGetRoundTrip re-encodes a config-get reply and checks that nothing was added or lost.
+func TestConfigGetRoundTrip(t *testing.T) {
+	t.Parallel()
+
+	raw, err := os.ReadFile("testdata/config-get.json")
+	if err != nil {
+		t.Fatalf("failed to read fixture: %v", err)
+	}
+	mockClient := testenv.NewMockClient(t,
+		testenv.ExpectCommand(t, "config-get", client.Services.DHCP4),
+		[]client.CommandResponse{{Result: client.ResultSuccess, Arguments: raw}},
+	)
+
+	cfg, err := ConfigGet(mockClient)
+	if err != nil {
+		t.Fatalf("ConfigGet() error = %v", err)
+	}
+	out, err := json.Marshal(cfg)
+	if err != nil {
+		t.Fatalf("Marshal() error = %v", err)
+	}
+	testenv.ExpectJSONEquivalent(t, out, raw)
+
+	if _, ok := cfg.Dhcp4.Extra["comment"]; !ok {
+		t.Errorf("Dhcp4.Extra = %v, want comment", cfg.Dhcp4.Extra)
+	}
+	if _, ok := cfg.Dhcp4.HooksLibraries[1].Extra["parameters"]; !ok {
+		t.Errorf("HooksLibraries[1].Extra = %v, want parameters", cfg.Dhcp4.HooksLibraries[1].Extra)
+	}
+
+	cfg.Dhcp4.ValidLifetime = 3600
+	cfg.Dhcp4.StashAgentOptions = true
+	cfg.Dhcp4.Subnet4[0].Pools = append(cfg.Dhcp4.Subnet4[0].Pools, Pool4{Pool: "192.0.2.200-192.0.2.209"})
+	out, err = json.Marshal(cfg)
+	if err != nil {
+		t.Fatalf("Marshal() error = %v", err)
+	}
+
+	var edited struct {
+		Dhcp4 map[string]json.RawMessage `json:"Dhcp4"`
+	}
+	if err := json.Unmarshal(out, &edited); err != nil {
+		t.Fatalf("Unmarshal() error = %v", err)
+	}
+	for member, want := range map[string]string{
+		"valid-lifetime":      `3600`,
+		"stash-agent-options": `true`,
+		"comment":             `"managed by kea-api"`,
+	} {
+		if got := string(edited.Dhcp4[member]); got != want {
+			t.Errorf("%s = %s, want %s", member, got, want)
+		}
+	}
+	if _, ok := edited.Dhcp4["config-control"]; ok {
+		t.Error("config-control was added although the server did not send it")
+	}
+
+	var again Dhcp4Config
+	if err := json.Unmarshal(out, &again); err != nil {
+		t.Fatalf("Unmarshal() error = %v", err)
+	}
+	if pools := again.Dhcp4.Subnet4[0].Pools; len(pools) != 3 || pools[2].Pool != "192.0.2.200-192.0.2.209" {
+		t.Errorf("Pools = %+v", pools)
+	}
+}
+
 // TestStatusGet tests the StatusGet function for the CtrlDHCP4 type.
 func TestStatusGet(t *testing.T) {
 	t.Parallel()
@@ -127,6 +279,28 @@ func TestStatusGet(t *testing.T) {
 	}
 }
 
+// TestStatusGetDirect runs the wrapper unchanged against a daemon's own HTTP control socket.
+func TestStatusGetDirect(t *testing.T) {
+	t.Parallel()
+
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var req map[string]interface{}
+		if err := json.NewDecoder(r.Body).Decode(&req); err != nil || req["command"] != "status-get" || req["service"] != nil {
+			t.Errorf("unexpected request %v (%v)", req, err)
+		}
+		w.Write([]byte(`{"result": 0, "arguments": {"pid": 4242, "uptime": 10}}`))
+	}))
+	defer srv.Close()
+
+	got, err := StatusGet(client.NewHTTP(srv.URL, client.WithDirect()))
+	if err != nil {
+		t.Fatalf("StatusGet() error = %v", err)
+	}
+	if got.PID != 4242 || got.Uptime != 10 {
+		t.Errorf("StatusGet() = %+v", got)
+	}
+}
+
 // TestListCommands tests the ListCommands function for the CtrlDHCP4 type.
 func TestListCommands(t *testing.T) {
 	t.Parallel()
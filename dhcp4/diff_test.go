@@ -0,0 +1,61 @@
+package dhcp4
+
+import (
+	"encoding/json"
+	"os"
+	"strings"
+	"testing"
+
+	"github.com/rannday/kea-api/types"
+)
+
+// TestDiff reports edits to a typed configuration, including unmodelled members.
+func TestDiff(t *testing.T) {
+	t.Parallel()
+
+	raw, err := os.ReadFile("testdata/config-get.json")
+	if err != nil {
+		t.Fatalf("failed to read fixture: %v", err)
+	}
+	var from, to Dhcp4Config
+	if err := json.Unmarshal(raw, &from); err != nil {
+		t.Fatal(err)
+	}
+	if err := json.Unmarshal(raw, &to); err != nil {
+		t.Fatal(err)
+	}
+
+	to.Hash = "ignored"
+	to.Dhcp4.ValidLifetime = 3600
+	to.Dhcp4.Extra["comment"] = json.RawMessage(`"edited"`)
+	to.Dhcp4.Subnet4[0].Pools = to.Dhcp4.Subnet4[0].Pools[:1]
+	to.Dhcp4.Subnet4 = append(to.Dhcp4.Subnet4, Subnet4{ID: 9, Subnet: "203.0.113.0/24"})
+
+	diff, err := Diff(from, to)
+	if err != nil {
+		t.Fatalf("Diff() error = %v", err)
+	}
+
+	want := map[string]types.ChangeKind{
+		"~ Dhcp4.comment": types.KindParameter,
+		"- Dhcp4.subnet4[id=1].pools[192.0.2.64/26]": types.KindPool,
+		"+ Dhcp4.subnet4[id=9]":                      types.KindSubnet,
+		"~ Dhcp4.valid-lifetime":                     types.KindParameter,
+	}
+	if len(diff) != len(want) {
+		t.Fatalf("Diff() =\n%s", diff)
+	}
+	for _, c := range diff {
+		sym := map[types.ChangeOp]string{types.ChangeAdded: "+", types.ChangeRemoved: "-", types.ChangeChanged: "~"}[c.Op]
+		if kind, ok := want[sym+" "+c.Path]; !ok || kind != c.Kind {
+			t.Errorf("unexpected change %s %s (%s)", sym, c.Path, c.Kind)
+		}
+	}
+	if !strings.Contains(diff.String(), `~ Dhcp4.comment: "managed by kea-api" -> "edited"`) {
+		t.Errorf("String() =\n%s", diff)
+	}
+
+	if diff, err := Diff(from, from); err != nil || len(diff) != 0 {
+		t.Errorf("Diff(x, x) = %v, %v", diff, err)
+	}
+}
@@ -0,0 +1,194 @@
+package dhcp4
+
+import (
+	"encoding/json"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/rannday/kea-api/client"
+	"github.com/rannday/kea-api/internal/testenv"
+	"github.com/rannday/kea-api/types"
+)
+
+// haStatusJSON is a status-get reply of a load-balancing primary, as sent by Kea 2.6.
+const haStatusJSON = `{
+	"pid": 1234,
+	"uptime": 3024,
+	"reload": 1111,
+	"high-availability": [{
+		"ha-mode": "load-balancing",
+		"ha-servers": {
+			"local": {
+				"role": "primary",
+				"scopes": ["server1"],
+				"state": "load-balancing",
+				"server-name": "server1",
+				"system-time": "2024-06-11 09:12:44"
+			},
+			"remote": {
+				"age": 10,
+				"in-touch": true,
+				"role": "secondary",
+				"last-scopes": ["server2"],
+				"last-state": "load-balancing",
+				"communication-interrupted": true,
+				"connecting-clients": 2,
+				"unacked-clients": 1,
+				"unacked-clients-left": 9,
+				"analyzed-packets": 8,
+				"server-name": "server2",
+				"system-time": "2024-06-11 09:12:45",
+				"clock-skew": 1
+			}
+		}
+	}]
+}`
+
+// TestStatusGet_HighAvailability decodes the HA section of status-get.
+func TestStatusGet_HighAvailability(t *testing.T) {
+	t.Parallel()
+
+	mockClient := testenv.NewSequenceMockClient(t,
+		testenv.MockStep{
+			Validate:  testenv.ExpectCommand(t, "status-get", client.Services.DHCP4),
+			Responses: []client.CommandResponse{{Result: client.ResultSuccess, Arguments: json.RawMessage(haStatusJSON)}},
+		},
+		testenv.MockStep{
+			Validate:  testenv.ExpectCommand(t, "status-get", client.Services.DHCP4),
+			Responses: []client.CommandResponse{{Result: client.ResultSuccess, Arguments: json.RawMessage(haStatusJSON)}},
+		},
+	)
+
+	want := []types.HAStatus{{
+		Mode: "load-balancing",
+		Servers: types.HAServers{
+			Local: types.HALocalServer{
+				ServerName: "server1",
+				Role:       types.HARolePrimary,
+				State:      types.HAStateLoadBalancing,
+				Scopes:     []string{"server1"},
+				SystemTime: "2024-06-11 09:12:44",
+			},
+			Remote: types.HARemoteServer{
+				ServerName:               "server2",
+				Role:                     types.HARoleSecondary,
+				LastState:                types.HAStateLoadBalancing,
+				LastScopes:               []string{"server2"},
+				Age:                      10,
+				InTouch:                  true,
+				CommunicationInterrupted: true,
+				ConnectingClients:        2,
+				UnackedClients:           1,
+				UnackedClientsLeft:       9,
+				AnalyzedPackets:          8,
+				ClockSkew:                1,
+				SystemTime:               "2024-06-11 09:12:45",
+			},
+		},
+	}}
+
+	status, err := StatusGet(mockClient)
+	if err != nil {
+		t.Fatalf("StatusGet() error = %v", err)
+	}
+	if status.PID != 1234 || !reflect.DeepEqual(status.HighAvailability, want) {
+		t.Errorf("StatusGet() = %+v", status)
+	}
+
+	got, err := HAStatusGet(mockClient)
+	if err != nil {
+		t.Fatalf("HAStatusGet() error = %v", err)
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("HAStatusGet() = %+v, want %+v", got, want)
+	}
+}
+
+// TestHAHeartbeat decodes the ha-heartbeat reply.
+func TestHAHeartbeat(t *testing.T) {
+	t.Parallel()
+
+	mockClient := testenv.NewMockClient(t,
+		testenv.ExpectCommandArgs(t, "ha-heartbeat", nil, client.Services.DHCP4),
+		[]client.CommandResponse{{
+			Result: client.ResultSuccess,
+			Text:   "HA peer status returned.",
+			Arguments: json.RawMessage(`{
+				"state": "partner-down",
+				"date-time": "Tue, 11 Jun 2024 09:12:44 GMT",
+				"scopes": ["server1", "server2"],
+				"unsent-update-count": 123
+			}`),
+		}},
+	)
+
+	got, err := HAHeartbeat(mockClient, "")
+	if err != nil {
+		t.Fatalf("HAHeartbeat() error = %v", err)
+	}
+	want := types.HAHeartbeat{
+		State:             types.HAStatePartnerDown,
+		DateTime:          "Tue, 11 Jun 2024 09:12:44 GMT",
+		Scopes:            []string{"server1", "server2"},
+		UnsentUpdateCount: 123,
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("HAHeartbeat() = %+v, want %+v", got, want)
+	}
+}
+
+// TestHACommands checks the arguments of the HA commands that only return a status.
+func TestHACommands(t *testing.T) {
+	t.Parallel()
+
+	ok := []client.CommandResponse{{Result: client.ResultSuccess}}
+	step := func(cmd string, args any) testenv.MockStep {
+		return testenv.MockStep{Validate: testenv.ExpectCommandArgs(t, cmd, args, client.Services.DHCP4), Responses: ok}
+	}
+	mockClient := testenv.NewSequenceMockClient(t,
+		step("ha-scopes", map[string]any{"scopes": []string{"server1", "server2"}}),
+		step("ha-scopes", map[string]any{"scopes": []string{}, "server-name": "server1"}),
+		step("ha-sync", map[string]any{"server-name": "server2", "max-period": 30}),
+		step("ha-sync-complete-notify", nil),
+		step("ha-continue", map[string]any{"server-name": "server1"}),
+		step("ha-maintenance-start", nil),
+		step("ha-maintenance-notify", map[string]any{"cancel": true}),
+		step("ha-maintenance-cancel", nil),
+		step("ha-reset", map[string]any{"server-name": "server1"}),
+	)
+
+	calls := []struct {
+		name string
+		call func() error
+	}{
+		{"HAScopes", func() error { return HAScopes(mockClient, []string{"server1", "server2"}, "") }},
+		{"HAScopes(none)", func() error { return HAScopes(mockClient, nil, "server1") }},
+		{"HASync", func() error { return HASync(mockClient, "server2", 30*time.Second) }},
+		{"HASyncCompleteNotify", func() error { return HASyncCompleteNotify(mockClient, "") }},
+		{"HAContinue", func() error { return HAContinue(mockClient, "server1") }},
+		{"HAMaintenanceStart", func() error { return HAMaintenanceStart(mockClient, "") }},
+		{"HAMaintenanceNotify", func() error { return HAMaintenanceNotify(mockClient, true, "") }},
+		{"HAMaintenanceCancel", func() error { return HAMaintenanceCancel(mockClient, "") }},
+		{"HAReset", func() error { return HAReset(mockClient, "server1") }},
+	}
+	for _, c := range calls {
+		if err := c.call(); err != nil {
+			t.Errorf("%s() error = %v", c.name, err)
+		}
+	}
+}
+
+// TestHAMaintenanceStart_Error surfaces the refusal of the server.
+func TestHAMaintenanceStart_Error(t *testing.T) {
+	t.Parallel()
+
+	mockClient := testenv.NewMockClient(t,
+		testenv.ExpectCommand(t, "ha-maintenance-start", client.Services.DHCP4),
+		[]client.CommandResponse{{Result: client.ResultGeneralFailure, Text: "Unable to transition the server from the partner-down to in-maintenance state."}},
+	)
+
+	if err := HAMaintenanceStart(mockClient, ""); err == nil {
+		t.Error("HAMaintenanceStart() succeeded, want the server's refusal")
+	}
+}
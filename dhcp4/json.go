@@ -0,0 +1,105 @@
+package dhcp4
+
+import "github.com/rannday/kea-api/internal/utils"
+
+// JSON codecs keeping the config types of this package lossless, Extra included; see utils.DecodeObject.
+
+func (d *Dhcp4Config) UnmarshalJSON(data []byte) error {
+	type plain Dhcp4Config
+	return utils.DecodeObject(data, (*plain)(d), &d.Extra, &d.absent)
+}
+
+func (d Dhcp4Config) MarshalJSON() ([]byte, error) {
+	type plain Dhcp4Config
+	return utils.EncodeObject(plain(d), d.Extra, d.absent)
+}
+
+func (d *Dhcp4Block) UnmarshalJSON(data []byte) error {
+	type plain Dhcp4Block
+	return utils.DecodeObject(data, (*plain)(d), &d.Extra, &d.absent)
+}
+
+func (d Dhcp4Block) MarshalJSON() ([]byte, error) {
+	type plain Dhcp4Block
+	return utils.EncodeObject(plain(d), d.Extra, d.absent)
+}
+
+func (c *ConfigControl) UnmarshalJSON(data []byte) error {
+	type plain ConfigControl
+	return utils.DecodeObject(data, (*plain)(c), &c.Extra, &c.absent)
+}
+
+func (c ConfigControl) MarshalJSON() ([]byte, error) {
+	type plain ConfigControl
+	return utils.EncodeObject(plain(c), c.Extra, c.absent)
+}
+
+func (d *DhcpDDNSConfig) UnmarshalJSON(data []byte) error {
+	type plain DhcpDDNSConfig
+	return utils.DecodeObject(data, (*plain)(d), &d.Extra, &d.absent)
+}
+
+func (d DhcpDDNSConfig) MarshalJSON() ([]byte, error) {
+	type plain DhcpDDNSConfig
+	return utils.EncodeObject(plain(d), d.Extra, d.absent)
+}
+
+func (d *DhcpQueueControl) UnmarshalJSON(data []byte) error {
+	type plain DhcpQueueControl
+	return utils.DecodeObject(data, (*plain)(d), &d.Extra, &d.absent)
+}
+
+func (d DhcpQueueControl) MarshalJSON() ([]byte, error) {
+	type plain DhcpQueueControl
+	return utils.EncodeObject(plain(d), d.Extra, d.absent)
+}
+
+func (e *ExpiredLeasesProcessing) UnmarshalJSON(data []byte) error {
+	type plain ExpiredLeasesProcessing
+	return utils.DecodeObject(data, (*plain)(e), &e.Extra, &e.absent)
+}
+
+func (e ExpiredLeasesProcessing) MarshalJSON() ([]byte, error) {
+	type plain ExpiredLeasesProcessing
+	return utils.EncodeObject(plain(e), e.Extra, e.absent)
+}
+
+func (r *Reservation4) UnmarshalJSON(data []byte) error {
+	type plain Reservation4
+	return utils.DecodeObject(data, (*plain)(r), &r.Extra, &r.absent)
+}
+
+func (r Reservation4) MarshalJSON() ([]byte, error) {
+	type plain Reservation4
+	return utils.EncodeObject(plain(r), r.Extra, r.absent)
+}
+
+func (s *Subnet4) UnmarshalJSON(data []byte) error {
+	type plain Subnet4
+	return utils.DecodeObject(data, (*plain)(s), &s.Extra, &s.absent)
+}
+
+func (s Subnet4) MarshalJSON() ([]byte, error) {
+	type plain Subnet4
+	return utils.EncodeObject(plain(s), s.Extra, s.absent)
+}
+
+func (p *Pool4) UnmarshalJSON(data []byte) error {
+	type plain Pool4
+	return utils.DecodeObject(data, (*plain)(p), &p.Extra, &p.absent)
+}
+
+func (p Pool4) MarshalJSON() ([]byte, error) {
+	type plain Pool4
+	return utils.EncodeObject(plain(p), p.Extra, p.absent)
+}
+
+func (s *SharedNetwork4) UnmarshalJSON(data []byte) error {
+	type plain SharedNetwork4
+	return utils.DecodeObject(data, (*plain)(s), &s.Extra, &s.absent)
+}
+
+func (s SharedNetwork4) MarshalJSON() ([]byte, error) {
+	type plain SharedNetwork4
+	return utils.EncodeObject(plain(s), s.Extra, s.absent)
+}
@@ -0,0 +1,208 @@
+package dhcp4
+
+import (
+	"context"
+	"iter"
+
+	"github.com/rannday/kea-api/client"
+	"github.com/rannday/kea-api/types"
+)
+
+/*
+ * Lease management via the lease_cmds hook library (libdhcp_lease_cmds.so).
+ * Commands that look up several leases answer with result 3 when nothing
+ * matches; the wrappers below report that as an empty slice. Single-lease
+ * lookups and deletions return the error instead, see client.IsNotFound.
+ */
+
+// Lease4Add creates a new lease with lease4-add.
+func Lease4Add(c *client.Client, lease Lease4) error {
+	return Lease4AddContext(context.Background(), c, lease)
+}
+
+// Lease4AddContext is like Lease4Add but honours ctx.
+func Lease4AddContext(ctx context.Context, c *client.Client, lease Lease4) error {
+	_, err := client.CallWithArgsContext(ctx, c, "lease4-add", lease, client.Services.DHCP4)
+	return err
+}
+
+// Lease4Get fetches a single lease by IP address or by identifier and subnet.
+func Lease4Get(c *client.Client, q Lease4Query) (Lease4, error) {
+	return Lease4GetContext(context.Background(), c, q)
+}
+
+// Lease4GetContext is like Lease4Get but honours ctx.
+func Lease4GetContext(ctx context.Context, c *client.Client, q Lease4Query) (Lease4, error) {
+	q.UpdateDDNS = false
+	return client.DecodeFirstWithArgsContext[Lease4](ctx, c, "lease4-get", q, client.Services.DHCP4)
+}
+
+// Lease4GetAll fetches every lease, or only those in the given subnets.
+func Lease4GetAll(c *client.Client, subnetIDs ...int) ([]Lease4, error) {
+	return Lease4GetAllContext(context.Background(), c, subnetIDs...)
+}
+
+// Lease4GetAllContext is like Lease4GetAll but honours ctx.
+func Lease4GetAllContext(ctx context.Context, c *client.Client, subnetIDs ...int) ([]Lease4, error) {
+	var args map[string]interface{}
+	if len(subnetIDs) > 0 {
+		args = map[string]interface{}{"subnets": subnetIDs}
+	}
+	return getLeases4(ctx, c, "lease4-get-all", args)
+}
+
+// Lease4GetByHWAddress fetches all leases held by a hardware address.
+func Lease4GetByHWAddress(c *client.Client, hwAddress string) ([]Lease4, error) {
+	return Lease4GetByHWAddressContext(context.Background(), c, hwAddress)
+}
+
+// Lease4GetByHWAddressContext is like Lease4GetByHWAddress but honours ctx.
+func Lease4GetByHWAddressContext(ctx context.Context, c *client.Client, hwAddress string) ([]Lease4, error) {
+	return getLeases4(ctx, c, "lease4-get-by-hw-address", map[string]interface{}{"hw-address": hwAddress})
+}
+
+// Lease4GetByClientID fetches all leases held by a client identifier.
+func Lease4GetByClientID(c *client.Client, clientID string) ([]Lease4, error) {
+	return Lease4GetByClientIDContext(context.Background(), c, clientID)
+}
+
+// Lease4GetByClientIDContext is like Lease4GetByClientID but honours ctx.
+func Lease4GetByClientIDContext(ctx context.Context, c *client.Client, clientID string) ([]Lease4, error) {
+	return getLeases4(ctx, c, "lease4-get-by-client-id", map[string]interface{}{"client-id": clientID})
+}
+
+// Lease4GetByHostname fetches all leases with the given hostname.
+func Lease4GetByHostname(c *client.Client, hostname string) ([]Lease4, error) {
+	return Lease4GetByHostnameContext(context.Background(), c, hostname)
+}
+
+// Lease4GetByHostnameContext is like Lease4GetByHostname but honours ctx.
+func Lease4GetByHostnameContext(ctx context.Context, c *client.Client, hostname string) ([]Lease4, error) {
+	return getLeases4(ctx, c, "lease4-get-by-hostname", map[string]interface{}{"hostname": hostname})
+}
+
+// Lease4Del deletes a single lease by IP address or by identifier and subnet.
+func Lease4Del(c *client.Client, q Lease4Query) error {
+	return Lease4DelContext(context.Background(), c, q)
+}
+
+// Lease4DelContext is like Lease4Del but honours ctx.
+func Lease4DelContext(ctx context.Context, c *client.Client, q Lease4Query) error {
+	_, err := client.CallWithArgsContext(ctx, c, "lease4-del", q, client.Services.DHCP4)
+	return err
+}
+
+// Lease4Update replaces an existing lease. With forceCreate the lease is added if it does not exist.
+func Lease4Update(c *client.Client, lease Lease4, forceCreate bool) error {
+	return Lease4UpdateContext(context.Background(), c, lease, forceCreate)
+}
+
+// Lease4UpdateContext is like Lease4Update but honours ctx.
+func Lease4UpdateContext(ctx context.Context, c *client.Client, lease Lease4, forceCreate bool) error {
+	args, err := client.EncodeArguments(lease)
+	if err != nil {
+		return err
+	}
+	if forceCreate {
+		args["force-create"] = true
+	}
+	_, err = client.CallWithArgsContext(ctx, c, "lease4-update", args, client.Services.DHCP4)
+	return err
+}
+
+// Lease4Wipe removes all leases from a subnet, or from every subnet when subnetID is 0.
+// It returns Kea's summary text. Kea has deprecated this command in favour of lease4-del.
+func Lease4Wipe(c *client.Client, subnetID int) (string, error) {
+	return Lease4WipeContext(context.Background(), c, subnetID)
+}
+
+// Lease4WipeContext is like Lease4Wipe but honours ctx.
+func Lease4WipeContext(ctx context.Context, c *client.Client, subnetID int) (string, error) {
+	var args map[string]interface{}
+	if subnetID != 0 {
+		args = map[string]interface{}{"subnet-id": subnetID}
+	}
+	responses, err := client.CallWithArgsContext(ctx, c, "lease4-wipe", args, client.Services.DHCP4)
+	if err != nil {
+		return "", err
+	}
+	return responses[0].Text, nil
+}
+
+// Lease4Write writes the in-memory lease database to a file on the server.
+func Lease4Write(c *client.Client, filename string) error {
+	return Lease4WriteContext(context.Background(), c, filename)
+}
+
+// Lease4WriteContext is like Lease4Write but honours ctx.
+func Lease4WriteContext(ctx context.Context, c *client.Client, filename string) error {
+	_, err := client.CallWithArgsContext(ctx, c, "lease4-write", map[string]interface{}{"filename": filename}, client.Services.DHCP4)
+	return err
+}
+
+// Lease4ResendDDNS asks the server to resend the DNS update for a lease.
+func Lease4ResendDDNS(c *client.Client, ipAddress string) error {
+	return Lease4ResendDDNSContext(context.Background(), c, ipAddress)
+}
+
+// Lease4ResendDDNSContext is like Lease4ResendDDNS but honours ctx.
+func Lease4ResendDDNSContext(ctx context.Context, c *client.Client, ipAddress string) error {
+	_, err := client.CallWithArgsContext(ctx, c, "lease4-resend-ddns", map[string]interface{}{"ip-address": ipAddress}, client.Services.DHCP4)
+	return err
+}
+
+// Lease4GetPage fetches up to limit leases ordered by address, starting after from.
+// Use "start" as from for the first page. An exhausted cursor yields an empty page.
+func Lease4GetPage(c *client.Client, from string, limit int) (Lease4Page, error) {
+	return Lease4GetPageContext(context.Background(), c, from, limit)
+}
+
+// Lease4GetPageContext is like Lease4GetPage but honours ctx.
+func Lease4GetPageContext(ctx context.Context, c *client.Client, from string, limit int) (Lease4Page, error) {
+	args := map[string]interface{}{"from": from, "limit": limit}
+	page, err := client.DecodeFirstWithArgsContext[Lease4Page](ctx, c, "lease4-get-page", args, client.Services.DHCP4)
+	if client.IsNotFound(err) {
+		return Lease4Page{Leases: []Lease4{}}, nil
+	}
+	return page, err
+}
+
+// Lease4Iter walks every lease page by page using lease4-get-page, so large lease
+// databases never have to be held in a single response. Iteration ends when Kea reports
+// no more leases, when ctx is done, or at the first error, which is yielded once.
+func Lease4Iter(ctx context.Context, c *client.Client, opts types.LeasePageOptions) iter.Seq2[Lease4, error] {
+	return func(yield func(Lease4, error) bool) {
+		from, limit := opts.PageStart(), opts.PageLimit()
+		for {
+			page, err := Lease4GetPageContext(ctx, c, from, limit)
+			if err != nil {
+				yield(Lease4{}, err)
+				return
+			}
+			if len(page.Leases) == 0 {
+				return
+			}
+			for _, lease := range page.Leases {
+				if !opts.MatchSubnet(lease.SubnetID) {
+					continue
+				}
+				if !yield(lease, nil) {
+					return
+				}
+			}
+			from = page.Leases[len(page.Leases)-1].IPAddress
+		}
+	}
+}
+
+// getLeases4 runs a multi-lease lookup, treating "no leases found" as an empty result.
+func getLeases4(ctx context.Context, c *client.Client, cmd string, args map[string]interface{}) ([]Lease4, error) {
+	list, err := client.DecodeFirstWithArgsContext[Lease4List](ctx, c, cmd, args, client.Services.DHCP4)
+	if client.IsNotFound(err) {
+		return []Lease4{}, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	return list.Leases, nil
+}
@@ -0,0 +1,451 @@
+package dhcp4
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"reflect"
+	"testing"
+
+	"github.com/rannday/kea-api/client"
+	"github.com/rannday/kea-api/internal/testenv"
+	"github.com/rannday/kea-api/types"
+)
+
+// lease4JSON is a lease4-get reply captured from Kea 2.6.
+const lease4JSON = `{
+  "client-id": "42:42:42:42:42:42:42:42",
+  "cltt": 12345678,
+  "fqdn-fwd": false,
+  "fqdn-rev": true,
+  "hostname": "myhost.example.com.",
+  "hw-address": "08:08:08:08:08:08",
+  "ip-address": "192.0.2.1",
+  "state": 0,
+  "subnet-id": 44,
+  "valid-lft": 3600,
+  "user-context": {"comment": "reserved"}
+}`
+
+// TestLease4Add checks that lease4-add sends only the populated lease fields.
+func TestLease4Add(t *testing.T) {
+	t.Parallel()
+
+	lease := Lease4{
+		IPAddress: "192.0.2.202",
+		HWAddress: "1a:1b:1c:1d:1e:1f",
+		SubnetID:  44,
+		ValidLft:  3600,
+	}
+
+	mockClient := testenv.NewMockClient(t,
+		testenv.ExpectCommandArgs(t, "lease4-add", map[string]any{
+			"ip-address": "192.0.2.202",
+			"hw-address": "1a:1b:1c:1d:1e:1f",
+			"subnet-id":  44,
+			"valid-lft":  3600,
+			"state":      0,
+		}, client.Services.DHCP4),
+		[]client.CommandResponse{{Result: client.ResultSuccess, Text: "Lease for address 192.0.2.202, subnet-id 44 added."}},
+	)
+
+	if err := Lease4Add(mockClient, lease); err != nil {
+		t.Fatalf("Lease4Add() error = %v", err)
+	}
+}
+
+// TestLease4Get decodes a real lease4-get reply.
+func TestLease4Get(t *testing.T) {
+	t.Parallel()
+
+	want := Lease4{
+		IPAddress:   "192.0.2.1",
+		HWAddress:   "08:08:08:08:08:08",
+		ClientID:    "42:42:42:42:42:42:42:42",
+		ValidLft:    3600,
+		CLTT:        12345678,
+		SubnetID:    44,
+		FQDNRev:     true,
+		Hostname:    "myhost.example.com.",
+		State:       types.LeaseStateDefault,
+		UserContext: map[string]interface{}{"comment": "reserved"},
+	}
+
+	mockClient := testenv.NewMockClient(t,
+		testenv.ExpectCommandArgs(t, "lease4-get", map[string]any{
+			"identifier-type": "hw-address",
+			"identifier":      "08:08:08:08:08:08",
+			"subnet-id":       44,
+		}, client.Services.DHCP4),
+		[]client.CommandResponse{{
+			Result:    client.ResultSuccess,
+			Text:      "IPv4 lease found.",
+			Arguments: json.RawMessage(lease4JSON),
+		}},
+	)
+
+	got, err := Lease4Get(mockClient, Lease4Query{
+		IdentifierType: "hw-address",
+		Identifier:     "08:08:08:08:08:08",
+		SubnetID:       44,
+	})
+	if err != nil {
+		t.Fatalf("Lease4Get() error = %v", err)
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("Lease4Get() = %+v, want %+v", got, want)
+	}
+}
+
+// TestLease4Get_NotFound checks that a missing lease is reported as a not-found error.
+func TestLease4Get_NotFound(t *testing.T) {
+	t.Parallel()
+
+	mockClient := testenv.NewMockClient(t,
+		testenv.ExpectCommandArgs(t, "lease4-get", map[string]any{"ip-address": "192.0.2.9"}, client.Services.DHCP4),
+		[]client.CommandResponse{{Result: client.ResultNotFound, Text: "Lease not found."}},
+	)
+
+	_, err := Lease4Get(mockClient, Lease4Query{IPAddress: "192.0.2.9"})
+	if !client.IsNotFound(err) {
+		t.Errorf("Lease4Get() error = %v, want not found", err)
+	}
+	var cmdErr *client.CommandError
+	if !errors.Is(err, client.ErrNotFound) || !errors.As(err, &cmdErr) || cmdErr.Command != "lease4-get" || cmdErr.Service != client.Services.DHCP4 {
+		t.Errorf("Lease4Get() error = %#v, want a lease4-get *client.CommandError", err)
+	}
+}
+
+// TestLease4GetAll checks subnet filtering and decoding of the leases list.
+func TestLease4GetAll(t *testing.T) {
+	t.Parallel()
+
+	mockClient := testenv.NewMockClient(t,
+		testenv.ExpectCommandArgs(t, "lease4-get-all", map[string]any{"subnets": []int{1, 2}}, client.Services.DHCP4),
+		[]client.CommandResponse{{
+			Result:    client.ResultSuccess,
+			Text:      "1 IPv4 lease(s) found.",
+			Arguments: json.RawMessage(`{"leases": [` + lease4JSON + `]}`),
+		}},
+	)
+
+	got, err := Lease4GetAll(mockClient, 1, 2)
+	if err != nil {
+		t.Fatalf("Lease4GetAll() error = %v", err)
+	}
+	if len(got) != 1 || got[0].IPAddress != "192.0.2.1" {
+		t.Errorf("Lease4GetAll() = %+v", got)
+	}
+}
+
+// TestLease4GetAll_Empty checks that result 3 yields an empty slice rather than an error.
+func TestLease4GetAll_Empty(t *testing.T) {
+	t.Parallel()
+
+	mockClient := testenv.NewMockClient(t,
+		testenv.ExpectCommandArgs(t, "lease4-get-all", nil, client.Services.DHCP4),
+		[]client.CommandResponse{{
+			Result:    client.ResultNotFound,
+			Text:      "0 IPv4 lease(s) found.",
+			Arguments: json.RawMessage(`{"leases": []}`),
+		}},
+	)
+
+	got, err := Lease4GetAll(mockClient)
+	if err != nil {
+		t.Fatalf("Lease4GetAll() error = %v", err)
+	}
+	if got == nil || len(got) != 0 {
+		t.Errorf("Lease4GetAll() = %#v, want empty slice", got)
+	}
+}
+
+// TestLease4GetBy checks the argument name sent by each lease4-get-by-* wrapper.
+func TestLease4GetBy(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		command string
+		key     string
+		call    func(*client.Client, string) ([]Lease4, error)
+	}{
+		{"lease4-get-by-hw-address", "hw-address", Lease4GetByHWAddress},
+		{"lease4-get-by-client-id", "client-id", Lease4GetByClientID},
+		{"lease4-get-by-hostname", "hostname", Lease4GetByHostname},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.command, func(t *testing.T) {
+			t.Parallel()
+
+			mockClient := testenv.NewMockClient(t,
+				testenv.ExpectCommandArgs(t, tt.command, map[string]any{tt.key: "value"}, client.Services.DHCP4),
+				[]client.CommandResponse{{
+					Result:    client.ResultSuccess,
+					Arguments: json.RawMessage(`{"leases": [` + lease4JSON + `]}`),
+				}},
+			)
+
+			got, err := tt.call(mockClient, "value")
+			if err != nil {
+				t.Fatalf("%s error = %v", tt.command, err)
+			}
+			if len(got) != 1 {
+				t.Errorf("%s returned %d leases, want 1", tt.command, len(got))
+			}
+		})
+	}
+}
+
+// TestLease4Update checks that force-create is added to the lease fields.
+func TestLease4Update(t *testing.T) {
+	t.Parallel()
+
+	mockClient := testenv.NewMockClient(t,
+		testenv.ExpectCommandArgs(t, "lease4-update", map[string]any{
+			"ip-address":   "192.0.2.1",
+			"hostname":     "newhostname.example.org",
+			"state":        0,
+			"force-create": true,
+		}, client.Services.DHCP4),
+		[]client.CommandResponse{{Result: client.ResultSuccess, Text: "IPv4 lease updated."}},
+	)
+
+	err := Lease4Update(mockClient, Lease4{IPAddress: "192.0.2.1", Hostname: "newhostname.example.org"}, true)
+	if err != nil {
+		t.Fatalf("Lease4Update() error = %v", err)
+	}
+}
+
+// TestLease4Del checks deletion by address with DNS cleanup.
+func TestLease4Del(t *testing.T) {
+	t.Parallel()
+
+	mockClient := testenv.NewMockClient(t,
+		testenv.ExpectCommandArgs(t, "lease4-del", map[string]any{
+			"ip-address":  "192.0.2.202",
+			"update-ddns": true,
+		}, client.Services.DHCP4),
+		[]client.CommandResponse{{Result: client.ResultSuccess, Text: "IPv4 lease deleted."}},
+	)
+
+	if err := Lease4Del(mockClient, Lease4Query{IPAddress: "192.0.2.202", UpdateDDNS: true}); err != nil {
+		t.Fatalf("Lease4Del() error = %v", err)
+	}
+}
+
+// TestLease4Wipe checks that the summary text is returned.
+func TestLease4Wipe(t *testing.T) {
+	t.Parallel()
+
+	want := "Deleted 2 IPv4 lease(s) from subnet(s) 44"
+	mockClient := testenv.NewMockClient(t,
+		testenv.ExpectCommandArgs(t, "lease4-wipe", map[string]any{"subnet-id": 44}, client.Services.DHCP4),
+		[]client.CommandResponse{{Result: client.ResultSuccess, Text: want}},
+	)
+
+	got, err := Lease4Wipe(mockClient, 44)
+	if err != nil {
+		t.Fatalf("Lease4Wipe() error = %v", err)
+	}
+	if got != want {
+		t.Errorf("Lease4Wipe() = %q, want %q", got, want)
+	}
+}
+
+// TestLease4Write checks the filename argument of lease4-write.
+func TestLease4Write(t *testing.T) {
+	t.Parallel()
+
+	mockClient := testenv.NewMockClient(t,
+		testenv.ExpectCommandArgs(t, "lease4-write", map[string]any{"filename": "/tmp/leases4.csv"}, client.Services.DHCP4),
+		[]client.CommandResponse{{Result: client.ResultSuccess}},
+	)
+
+	if err := Lease4Write(mockClient, "/tmp/leases4.csv"); err != nil {
+		t.Fatalf("Lease4Write() error = %v", err)
+	}
+}
+
+// TestLease4ResendDDNS checks the address argument of lease4-resend-ddns.
+func TestLease4ResendDDNS(t *testing.T) {
+	t.Parallel()
+
+	mockClient := testenv.NewMockClient(t,
+		testenv.ExpectCommandArgs(t, "lease4-resend-ddns", map[string]any{"ip-address": "192.0.2.1"}, client.Services.DHCP4),
+		[]client.CommandResponse{{Result: client.ResultSuccess}},
+	)
+
+	if err := Lease4ResendDDNS(mockClient, "192.0.2.1"); err != nil {
+		t.Fatalf("Lease4ResendDDNS() error = %v", err)
+	}
+}
+
+// leasePage4 builds a successful lease4-get-page response holding the given addresses.
+func leasePage4(t *testing.T, subnetID int, ips ...string) []client.CommandResponse {
+	t.Helper()
+	leases := make([]Lease4, 0, len(ips))
+	for _, ip := range ips {
+		leases = append(leases, Lease4{IPAddress: ip, SubnetID: subnetID})
+	}
+	return []client.CommandResponse{{
+		Result:    client.ResultSuccess,
+		Arguments: testenv.MustEncodeRawJSON(t, Lease4Page{Leases: leases, Count: len(leases)}),
+	}}
+}
+
+// emptyPage4 is the lease4-get-page reply once the cursor is exhausted.
+var emptyPage4 = []client.CommandResponse{{Result: client.ResultNotFound, Text: "0 IPv4 lease(s) found."}}
+
+// TestLease4GetPage_Empty checks that an exhausted cursor is an empty page, not an error.
+func TestLease4GetPage_Empty(t *testing.T) {
+	t.Parallel()
+
+	mockClient := testenv.NewMockClient(t,
+		testenv.ExpectCommandArgs(t, "lease4-get-page", map[string]any{"from": "192.0.2.9", "limit": 10}, client.Services.DHCP4),
+		emptyPage4,
+	)
+
+	got, err := Lease4GetPage(mockClient, "192.0.2.9", 10)
+	if err != nil {
+		t.Fatalf("Lease4GetPage() error = %v", err)
+	}
+	if len(got.Leases) != 0 {
+		t.Errorf("Lease4GetPage() = %+v, want empty page", got)
+	}
+}
+
+// TestLease4Iter walks several pages, advancing the cursor to the last address of each page.
+func TestLease4Iter(t *testing.T) {
+	t.Parallel()
+
+	mockClient := testenv.NewSequenceMockClient(t,
+		testenv.MockStep{
+			Validate:  testenv.ExpectCommandArgs(t, "lease4-get-page", map[string]any{"from": "start", "limit": 2}, client.Services.DHCP4),
+			Responses: leasePage4(t, 1, "192.0.2.1", "192.0.2.2"),
+		},
+		testenv.MockStep{
+			Validate:  testenv.ExpectCommandArgs(t, "lease4-get-page", map[string]any{"from": "192.0.2.2", "limit": 2}, client.Services.DHCP4),
+			Responses: leasePage4(t, 2, "192.0.2.3"),
+		},
+		testenv.MockStep{
+			Validate:  testenv.ExpectCommandArgs(t, "lease4-get-page", map[string]any{"from": "192.0.2.3", "limit": 2}, client.Services.DHCP4),
+			Responses: emptyPage4,
+		},
+	)
+
+	var got []string
+	for lease, err := range Lease4Iter(context.Background(), mockClient, types.LeasePageOptions{Limit: 2}) {
+		if err != nil {
+			t.Fatalf("Lease4Iter() error = %v", err)
+		}
+		got = append(got, lease.IPAddress)
+	}
+
+	want := []string{"192.0.2.1", "192.0.2.2", "192.0.2.3"}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("Lease4Iter() = %v, want %v", got, want)
+	}
+}
+
+// TestLease4Iter_SubnetFilter checks that leases outside the requested subnets are skipped.
+func TestLease4Iter_SubnetFilter(t *testing.T) {
+	t.Parallel()
+
+	mockClient := testenv.NewSequenceMockClient(t,
+		testenv.MockStep{Responses: leasePage4(t, 1, "192.0.2.1")},
+		testenv.MockStep{Responses: leasePage4(t, 2, "198.51.100.1")},
+		testenv.MockStep{Responses: emptyPage4},
+	)
+
+	var got []string
+	opts := types.LeasePageOptions{Limit: 1, SubnetIDs: []int{2}}
+	for lease, err := range Lease4Iter(context.Background(), mockClient, opts) {
+		if err != nil {
+			t.Fatalf("Lease4Iter() error = %v", err)
+		}
+		got = append(got, lease.IPAddress)
+	}
+
+	if !reflect.DeepEqual(got, []string{"198.51.100.1"}) {
+		t.Errorf("Lease4Iter() = %v", got)
+	}
+}
+
+// TestLease4Iter_Break checks that stopping early does not fetch further pages.
+func TestLease4Iter_Break(t *testing.T) {
+	t.Parallel()
+
+	mockClient := testenv.NewSequenceMockClient(t,
+		testenv.MockStep{Responses: leasePage4(t, 1, "192.0.2.1", "192.0.2.2")},
+	)
+
+	for lease, err := range Lease4Iter(context.Background(), mockClient, types.LeasePageOptions{Limit: 2}) {
+		if err != nil {
+			t.Fatalf("Lease4Iter() error = %v", err)
+		}
+		if lease.IPAddress == "192.0.2.1" {
+			break
+		}
+	}
+}
+
+// TestLease4Iter_Error checks that a failed page is yielded once and ends iteration.
+func TestLease4Iter_Error(t *testing.T) {
+	t.Parallel()
+
+	mockClient := testenv.NewSequenceMockClient(t,
+		testenv.MockStep{Responses: []client.CommandResponse{{Result: client.ResultUnsupported, Text: "lease4-get-page not supported"}}},
+	)
+
+	var errs []error
+	for _, err := range Lease4Iter(context.Background(), mockClient, types.LeasePageOptions{}) {
+		errs = append(errs, err)
+	}
+	if len(errs) != 1 || errs[0] == nil {
+		t.Errorf("Lease4Iter() errors = %v, want exactly one error", errs)
+	}
+}
+
+// TestLease4Iter_Canceled checks that a cancelled context stops iteration with its error.
+func TestLease4Iter_Canceled(t *testing.T) {
+	t.Parallel()
+
+	mockClient := testenv.NewSequenceMockClient(t)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	for _, err := range Lease4Iter(ctx, mockClient, types.LeasePageOptions{}) {
+		if !errors.Is(err, context.Canceled) {
+			t.Errorf("Lease4Iter() error = %v, want context canceled", err)
+		}
+	}
+}
+
+// TestLease4Iter_Socket runs the iterator over SocketTransport.
+func TestLease4Iter_Socket(t *testing.T) {
+	t.Parallel()
+
+	mockClient := testenv.NewSequenceSocketMockClient(t, client.Services.DHCP4,
+		testenv.MockStep{
+			Validate:  testenv.ExpectCommandArgs(t, "lease4-get-page", map[string]any{"from": "start", "limit": 1000}, client.Services.DHCP4),
+			Responses: leasePage4(t, 1, "192.0.2.1", "192.0.2.2"),
+		},
+		testenv.MockStep{
+			Validate:  testenv.ExpectCommandArgs(t, "lease4-get-page", map[string]any{"from": "192.0.2.2", "limit": 1000}, client.Services.DHCP4),
+			Responses: emptyPage4,
+		},
+	)
+
+	count := 0
+	for _, err := range Lease4Iter(context.Background(), mockClient, types.LeasePageOptions{}) {
+		if err != nil {
+			t.Fatalf("Lease4Iter() error = %v", err)
+		}
+		count++
+	}
+	if count != 2 {
+		t.Errorf("Lease4Iter() yielded %d leases, want 2", count)
+	}
+}
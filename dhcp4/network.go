@@ -0,0 +1,104 @@
+package dhcp4
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/rannday/kea-api/client"
+	"github.com/rannday/kea-api/types"
+)
+
+/*
+ * Runtime shared network management via the subnet_cmds hook library.
+ * Changes apply to the running configuration only; use config-write to persist them.
+ */
+
+// Network4List lists the names of all shared networks.
+func Network4List(c *client.Client) ([]string, error) {
+	return Network4ListContext(context.Background(), c)
+}
+
+// Network4ListContext is like Network4List but honours ctx.
+func Network4ListContext(ctx context.Context, c *client.Client) ([]string, error) {
+	list, err := client.DecodeFirstWithArgsContext[types.SharedNetworkRefList](ctx, c, "network4-list", nil, client.Services.DHCP4)
+	if client.IsNotFound(err) {
+		return []string{}, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	names := make([]string, 0, len(list.SharedNetworks))
+	for _, n := range list.SharedNetworks {
+		names = append(names, n.Name)
+	}
+	return names, nil
+}
+
+// Network4Get fetches a shared network, including its subnets, by name.
+func Network4Get(c *client.Client, name string) (SharedNetwork4, error) {
+	return Network4GetContext(context.Background(), c, name)
+}
+
+// Network4GetContext is like Network4Get but honours ctx.
+func Network4GetContext(ctx context.Context, c *client.Client, name string) (SharedNetwork4, error) {
+	res, err := client.DecodeFirstWithArgsContext[SharedNetwork4Args](ctx, c, "network4-get", map[string]interface{}{"name": name}, client.Services.DHCP4)
+	if err != nil {
+		return SharedNetwork4{}, err
+	}
+	if len(res.SharedNetworks) == 0 {
+		return SharedNetwork4{}, fmt.Errorf("network4-get returned no shared network")
+	}
+	return res.SharedNetworks[0], nil
+}
+
+// Network4Add adds a shared network together with any subnets listed in it.
+func Network4Add(c *client.Client, network SharedNetwork4) error {
+	return Network4AddContext(context.Background(), c, network)
+}
+
+// Network4AddContext is like Network4Add but honours ctx.
+func Network4AddContext(ctx context.Context, c *client.Client, network SharedNetwork4) error {
+	args := SharedNetwork4Args{SharedNetworks: []SharedNetwork4{network}}
+	_, err := client.CallWithArgsContext(ctx, c, "network4-add", args, client.Services.DHCP4)
+	return err
+}
+
+// Network4Del deletes a shared network. action decides whether its subnets are kept as
+// plain subnets or deleted as well; Kea keeps them when action is empty.
+func Network4Del(c *client.Client, name string, action types.SubnetsAction) error {
+	return Network4DelContext(context.Background(), c, name, action)
+}
+
+// Network4DelContext is like Network4Del but honours ctx.
+func Network4DelContext(ctx context.Context, c *client.Client, name string, action types.SubnetsAction) error {
+	args := map[string]interface{}{"name": name}
+	if action != "" {
+		args["subnets-action"] = action
+	}
+	_, err := client.CallWithArgsContext(ctx, c, "network4-del", args, client.Services.DHCP4)
+	return err
+}
+
+// Network4SubnetAdd moves an existing subnet into a shared network.
+func Network4SubnetAdd(c *client.Client, name string, subnetID int) error {
+	return Network4SubnetAddContext(context.Background(), c, name, subnetID)
+}
+
+// Network4SubnetAddContext is like Network4SubnetAdd but honours ctx.
+func Network4SubnetAddContext(ctx context.Context, c *client.Client, name string, subnetID int) error {
+	args := map[string]interface{}{"name": name, "id": subnetID}
+	_, err := client.CallWithArgsContext(ctx, c, "network4-subnet-add", args, client.Services.DHCP4)
+	return err
+}
+
+// Network4SubnetDel detaches a subnet from a shared network; the subnet itself is kept.
+func Network4SubnetDel(c *client.Client, name string, subnetID int) error {
+	return Network4SubnetDelContext(context.Background(), c, name, subnetID)
+}
+
+// Network4SubnetDelContext is like Network4SubnetDel but honours ctx.
+func Network4SubnetDelContext(ctx context.Context, c *client.Client, name string, subnetID int) error {
+	args := map[string]interface{}{"name": name, "id": subnetID}
+	_, err := client.CallWithArgsContext(ctx, c, "network4-subnet-del", args, client.Services.DHCP4)
+	return err
+}
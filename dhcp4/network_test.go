@@ -0,0 +1,144 @@
+package dhcp4
+
+import (
+	"encoding/json"
+	"reflect"
+	"testing"
+
+	"github.com/rannday/kea-api/client"
+	"github.com/rannday/kea-api/internal/testenv"
+	"github.com/rannday/kea-api/types"
+)
+
+// TestNetwork4List decodes the network names and handles a server without networks.
+func TestNetwork4List(t *testing.T) {
+	t.Parallel()
+
+	mockClient := testenv.NewSequenceMockClient(t,
+		testenv.MockStep{
+			Validate: testenv.ExpectCommandArgs(t, "network4-list", nil, client.Services.DHCP4),
+			Responses: []client.CommandResponse{{
+				Result:    client.ResultSuccess,
+				Arguments: json.RawMessage(`{"shared-networks": [{"name": "network1"}, {"name": "network2"}]}`),
+			}},
+		},
+		testenv.MockStep{Responses: []client.CommandResponse{{Result: client.ResultNotFound, Text: "0 IPv4 network(s) found."}}},
+	)
+
+	got, err := Network4List(mockClient)
+	if err != nil {
+		t.Fatalf("Network4List() error = %v", err)
+	}
+	if !reflect.DeepEqual(got, []string{"network1", "network2"}) {
+		t.Errorf("Network4List() = %v", got)
+	}
+
+	got, err = Network4List(mockClient)
+	if err != nil || len(got) != 0 {
+		t.Errorf("Network4List() = %v, %v, want empty", got, err)
+	}
+}
+
+// TestNetwork4Get decodes a shared network with its subnets.
+func TestNetwork4Get(t *testing.T) {
+	t.Parallel()
+
+	mockClient := testenv.NewMockClient(t,
+		testenv.ExpectCommandArgs(t, "network4-get", map[string]any{"name": "floor13"}, client.Services.DHCP4),
+		[]client.CommandResponse{{
+			Result: client.ResultSuccess,
+			Arguments: json.RawMessage(`{"shared-networks": [{
+				"name": "floor13", "interface": "eth1", "valid-lifetime": 7200,
+				"subnet4": [{"id": 5, "subnet": "192.0.2.0/24", "pools": [{"pool": "192.0.2.100-192.0.2.199"}]}]
+			}]}`),
+		}},
+	)
+
+	got, err := Network4Get(mockClient, "floor13")
+	if err != nil {
+		t.Fatalf("Network4Get() error = %v", err)
+	}
+	want := SharedNetwork4{
+		Name:          "floor13",
+		Interface:     "eth1",
+		ValidLifetime: 7200,
+		Subnet4:       []Subnet4{{ID: 5, Subnet: "192.0.2.0/24", Pools: []Pool4{{Pool: "192.0.2.100-192.0.2.199"}}}},
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("Network4Get() = %+v, want %+v", got, want)
+	}
+}
+
+// TestNetwork4Add checks that the network is wrapped in a shared-networks list.
+func TestNetwork4Add(t *testing.T) {
+	t.Parallel()
+
+	mockClient := testenv.NewMockClient(t,
+		testenv.ExpectCommandArgs(t, "network4-add", map[string]any{"shared-networks": []any{map[string]any{
+			"name":    "floor13",
+			"subnet4": []any{map[string]any{"id": 100, "subnet": "192.0.2.0/24"}},
+		}}}, client.Services.DHCP4),
+		[]client.CommandResponse{{
+			Result:    client.ResultSuccess,
+			Arguments: json.RawMessage(`{"shared-networks": [{"name": "floor13"}]}`),
+		}},
+	)
+
+	err := Network4Add(mockClient, SharedNetwork4{Name: "floor13", Subnet4: []Subnet4{{ID: 100, Subnet: "192.0.2.0/24"}}})
+	if err != nil {
+		t.Fatalf("Network4Add() error = %v", err)
+	}
+}
+
+// TestNetwork4Del checks the subnets-action option, which is omitted when empty.
+func TestNetwork4Del(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		action types.SubnetsAction
+		args   map[string]any
+	}{
+		{"", map[string]any{"name": "floor13"}},
+		{types.SubnetsKeep, map[string]any{"name": "floor13", "subnets-action": "keep"}},
+		{types.SubnetsDelete, map[string]any{"name": "floor13", "subnets-action": "delete"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(string(tt.action), func(t *testing.T) {
+			t.Parallel()
+
+			mockClient := testenv.NewMockClient(t,
+				testenv.ExpectCommandArgs(t, "network4-del", tt.args, client.Services.DHCP4),
+				[]client.CommandResponse{{Result: client.ResultSuccess, Text: "IPv4 shared network 'floor13' deleted"}},
+			)
+
+			if err := Network4Del(mockClient, "floor13", tt.action); err != nil {
+				t.Fatalf("Network4Del() error = %v", err)
+			}
+		})
+	}
+}
+
+// TestNetwork4Subnet checks attaching and detaching a subnet.
+func TestNetwork4Subnet(t *testing.T) {
+	t.Parallel()
+
+	args := map[string]any{"name": "floor13", "id": 5}
+	mockClient := testenv.NewSequenceMockClient(t,
+		testenv.MockStep{
+			Validate:  testenv.ExpectCommandArgs(t, "network4-subnet-add", args, client.Services.DHCP4),
+			Responses: []client.CommandResponse{{Result: client.ResultSuccess, Text: "IPv4 subnet 10.0.0.0/8 (id 5) is now part of shared network 'floor13'"}},
+		},
+		testenv.MockStep{
+			Validate:  testenv.ExpectCommandArgs(t, "network4-subnet-del", args, client.Services.DHCP4),
+			Responses: []client.CommandResponse{{Result: client.ResultSuccess, Text: "IPv4 subnet 10.0.0.0/8 (id 5) is now removed from shared network 'floor13'"}},
+		},
+	)
+
+	if err := Network4SubnetAdd(mockClient, "floor13", 5); err != nil {
+		t.Fatalf("Network4SubnetAdd() error = %v", err)
+	}
+	if err := Network4SubnetDel(mockClient, "floor13", 5); err != nil {
+		t.Fatalf("Network4SubnetDel() error = %v", err)
+	}
+}
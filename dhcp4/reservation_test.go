@@ -0,0 +1,232 @@
+package dhcp4
+
+import (
+	"context"
+	"encoding/json"
+	"reflect"
+	"testing"
+
+	"github.com/rannday/kea-api/client"
+	"github.com/rannday/kea-api/internal/testenv"
+	"github.com/rannday/kea-api/types"
+)
+
+// reservation4JSON is a reservation-get reply captured from Kea 2.6.
+const reservation4JSON = `{
+  "boot-file-name": "bootfile.efi",
+  "client-classes": ["special_snowflake", "office"],
+  "hostname": "somehost.example.org",
+  "hw-address": "1a:1b:1c:1d:1e:1f",
+  "ip-address": "192.0.2.202",
+  "next-server": "192.0.2.1",
+  "option-data": [{"always-send": false, "code": 3, "csv-format": true, "data": "10.1.1.1", "name": "routers", "space": "dhcp4"}],
+  "server-hostname": "server-hostname.example.org",
+  "subnet-id": 1
+}`
+
+// TestReservationAdd checks that a global reservation still carries subnet-id 0.
+func TestReservationAdd(t *testing.T) {
+	t.Parallel()
+
+	mockClient := testenv.NewMockClient(t,
+		testenv.ExpectCommandArgs(t, "reservation-add", map[string]any{
+			"reservation": map[string]any{
+				"subnet-id":  0,
+				"hw-address": "1a:1b:1c:1d:1e:1f",
+				"hostname":   "printer",
+			},
+		}, client.Services.DHCP4),
+		[]client.CommandResponse{{Result: client.ResultSuccess, Text: "Host added."}},
+	)
+
+	err := ReservationAdd(mockClient, Reservation4{HWAddress: "1a:1b:1c:1d:1e:1f", Hostname: "printer"})
+	if err != nil {
+		t.Fatalf("ReservationAdd() error = %v", err)
+	}
+}
+
+// TestReservationGet decodes a real reservation-get reply.
+func TestReservationGet(t *testing.T) {
+	t.Parallel()
+
+	csv := true
+	want := Reservation4{
+		SubnetID:       1,
+		HWAddress:      "1a:1b:1c:1d:1e:1f",
+		IPAddress:      "192.0.2.202",
+		Hostname:       "somehost.example.org",
+		ClientClasses:  []string{"special_snowflake", "office"},
+		OptionData:     []types.OptionData{{Name: "routers", Code: 3, Space: "dhcp4", CSVFormat: &csv, Data: "10.1.1.1"}},
+		NextServer:     "192.0.2.1",
+		ServerHostname: "server-hostname.example.org",
+		BootFileName:   "bootfile.efi",
+	}
+
+	mockClient := testenv.NewMockClient(t,
+		testenv.ExpectCommandArgs(t, "reservation-get", map[string]any{
+			"subnet-id":       1,
+			"identifier-type": "hw-address",
+			"identifier":      "1a:1b:1c:1d:1e:1f",
+		}, client.Services.DHCP4),
+		[]client.CommandResponse{{
+			Result:    client.ResultSuccess,
+			Text:      "Host found.",
+			Arguments: json.RawMessage(reservation4JSON),
+		}},
+	)
+
+	got, err := ReservationGet(mockClient, types.ReservationQuery{
+		SubnetID:       1,
+		IdentifierType: "hw-address",
+		Identifier:     "1a:1b:1c:1d:1e:1f",
+	})
+	if err != nil {
+		t.Fatalf("ReservationGet() error = %v", err)
+	}
+	if !testenv.EqualExported(got, want) {
+		t.Errorf("ReservationGet() = %+v, want %+v", got, want)
+	}
+	if typ, id := got.Identifier(); typ != "hw-address" || id != "1a:1b:1c:1d:1e:1f" {
+		t.Errorf("Identifier() = %q, %q", typ, id)
+	}
+}
+
+// TestReservationGetAll_Empty checks that result 3 yields an empty slice.
+func TestReservationGetAll_Empty(t *testing.T) {
+	t.Parallel()
+
+	mockClient := testenv.NewMockClient(t,
+		testenv.ExpectCommandArgs(t, "reservation-get-all", map[string]any{"subnet-id": 0}, client.Services.DHCP4),
+		[]client.CommandResponse{{Result: client.ResultNotFound, Text: "0 IPv4 host(s) found."}},
+	)
+
+	got, err := ReservationGetAll(mockClient, 0)
+	if err != nil {
+		t.Fatalf("ReservationGetAll() error = %v", err)
+	}
+	if got == nil || len(got) != 0 {
+		t.Errorf("ReservationGetAll() = %#v, want empty slice", got)
+	}
+}
+
+// TestReservationGetBy checks the arguments sent by each reservation-get-by-* wrapper.
+func TestReservationGetBy(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		command string
+		args    map[string]any
+		call    func(*client.Client) ([]Reservation4, error)
+	}{
+		{"reservation-get-by-hostname", map[string]any{"hostname": "somehost.example.org"}, func(c *client.Client) ([]Reservation4, error) {
+			return ReservationGetByHostname(c, "somehost.example.org")
+		}},
+		{"reservation-get-by-address", map[string]any{"ip-address": "192.0.2.202"}, func(c *client.Client) ([]Reservation4, error) {
+			return ReservationGetByAddress(c, "192.0.2.202")
+		}},
+		{"reservation-get-by-id", map[string]any{"identifier-type": "hw-address", "identifier": "1a:1b:1c:1d:1e:1f"}, func(c *client.Client) ([]Reservation4, error) {
+			return ReservationGetByID(c, "hw-address", "1a:1b:1c:1d:1e:1f")
+		}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.command, func(t *testing.T) {
+			t.Parallel()
+
+			mockClient := testenv.NewMockClient(t,
+				testenv.ExpectCommandArgs(t, tt.command, tt.args, client.Services.DHCP4),
+				[]client.CommandResponse{{
+					Result:    client.ResultSuccess,
+					Arguments: json.RawMessage(`{"hosts": [` + reservation4JSON + `]}`),
+				}},
+			)
+
+			got, err := tt.call(mockClient)
+			if err != nil {
+				t.Fatalf("%s error = %v", tt.command, err)
+			}
+			if len(got) != 1 || got[0].IPAddress != "192.0.2.202" {
+				t.Errorf("%s = %+v", tt.command, got)
+			}
+		})
+	}
+}
+
+// TestReservationIter follows the source-index/from cursor across host sources.
+func TestReservationIter(t *testing.T) {
+	t.Parallel()
+
+	page := func(next *types.ReservationPageCursor, ips ...string) []client.CommandResponse {
+		hosts := make([]Reservation4, 0, len(ips))
+		for _, ip := range ips {
+			hosts = append(hosts, Reservation4{SubnetID: 1, IPAddress: ip})
+		}
+		return []client.CommandResponse{{
+			Result:    client.ResultSuccess,
+			Arguments: testenv.MustEncodeRawJSON(t, Reservation4Page{Count: len(hosts), Hosts: hosts, Next: next}),
+		}}
+	}
+
+	mockClient := testenv.NewSequenceMockClient(t,
+		testenv.MockStep{
+			Validate:  testenv.ExpectCommandArgs(t, "reservation-get-page", map[string]any{"subnet-id": 1, "limit": 2}, client.Services.DHCP4),
+			Responses: page(&types.ReservationPageCursor{From: 0, SourceIndex: 0}, "192.0.2.10", "192.0.2.11"),
+		},
+		testenv.MockStep{
+			Validate:  testenv.ExpectCommandArgs(t, "reservation-get-page", map[string]any{"subnet-id": 1, "limit": 2, "source-index": 0, "from": 0}, client.Services.DHCP4),
+			Responses: page(&types.ReservationPageCursor{From: 1234567, SourceIndex: 1}, "192.0.2.12"),
+		},
+		testenv.MockStep{
+			Validate:  testenv.ExpectCommandArgs(t, "reservation-get-page", map[string]any{"subnet-id": 1, "limit": 2, "source-index": 1, "from": 1234567}, client.Services.DHCP4),
+			Responses: []client.CommandResponse{{Result: client.ResultNotFound, Text: "0 IPv4 host(s) found."}},
+		},
+	)
+
+	var got []string
+	for r, err := range ReservationIter(context.Background(), mockClient, types.ReservationPageOptions{SubnetID: 1, Limit: 2}) {
+		if err != nil {
+			t.Fatalf("ReservationIter() error = %v", err)
+		}
+		got = append(got, r.IPAddress)
+	}
+
+	want := []string{"192.0.2.10", "192.0.2.11", "192.0.2.12"}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("ReservationIter() = %v, want %v", got, want)
+	}
+}
+
+// TestReservationDel checks deletion by address.
+func TestReservationDel(t *testing.T) {
+	t.Parallel()
+
+	mockClient := testenv.NewMockClient(t,
+		testenv.ExpectCommandArgs(t, "reservation-del", map[string]any{"subnet-id": 1, "ip-address": "192.0.2.202"}, client.Services.DHCP4),
+		[]client.CommandResponse{{Result: client.ResultSuccess, Text: "Host deleted."}},
+	)
+
+	if err := ReservationDel(mockClient, types.ReservationQuery{SubnetID: 1, IPAddress: "192.0.2.202"}); err != nil {
+		t.Fatalf("ReservationDel() error = %v", err)
+	}
+}
+
+// TestReservationUpdate checks the reservation wrapper of reservation-update.
+func TestReservationUpdate(t *testing.T) {
+	t.Parallel()
+
+	mockClient := testenv.NewMockClient(t,
+		testenv.ExpectCommandArgs(t, "reservation-update", map[string]any{
+			"reservation": map[string]any{
+				"subnet-id":  1,
+				"hw-address": "1a:1b:1c:1d:1e:1f",
+				"ip-address": "192.0.2.203",
+			},
+		}, client.Services.DHCP4),
+		[]client.CommandResponse{{Result: client.ResultSuccess, Text: "Host updated."}},
+	)
+
+	err := ReservationUpdate(mockClient, Reservation4{SubnetID: 1, HWAddress: "1a:1b:1c:1d:1e:1f", IPAddress: "192.0.2.203"})
+	if err != nil {
+		t.Fatalf("ReservationUpdate() error = %v", err)
+	}
+}
@@ -0,0 +1,114 @@
+package dhcp4
+
+import (
+	"context"
+	"time"
+
+	"github.com/rannday/kea-api/client"
+	"github.com/rannday/kea-api/types"
+)
+
+/*
+ * Statistics of the running DHCPv4 server. Names of subnet and pool statistics
+ * can be built with types.SubnetStatistic and types.PoolStatistic.
+ */
+
+// StatisticGet fetches the samples of a single statistic.
+func StatisticGet(c *client.Client, name string) (types.Statistic, error) {
+	return StatisticGetContext(context.Background(), c, name)
+}
+
+// StatisticGetContext is like StatisticGet but honours ctx.
+func StatisticGetContext(ctx context.Context, c *client.Client, name string) (types.Statistic, error) {
+	return client.StatisticGetContext(ctx, c, client.Services.DHCP4, name)
+}
+
+// StatisticGetAll fetches every statistic, sorted by name.
+func StatisticGetAll(c *client.Client) ([]types.Statistic, error) {
+	return StatisticGetAllContext(context.Background(), c)
+}
+
+// StatisticGetAllContext is like StatisticGetAll but honours ctx.
+func StatisticGetAllContext(ctx context.Context, c *client.Client) ([]types.Statistic, error) {
+	return client.StatisticGetAllContext(ctx, c, client.Services.DHCP4)
+}
+
+// StatisticReset sets a statistic back to its neutral value.
+func StatisticReset(c *client.Client, name string) error {
+	return StatisticResetContext(context.Background(), c, name)
+}
+
+// StatisticResetContext is like StatisticReset but honours ctx.
+func StatisticResetContext(ctx context.Context, c *client.Client, name string) error {
+	return client.StatisticResetContext(ctx, c, client.Services.DHCP4, name)
+}
+
+// StatisticResetAll resets every statistic.
+func StatisticResetAll(c *client.Client) error {
+	return StatisticResetAllContext(context.Background(), c)
+}
+
+// StatisticResetAllContext is like StatisticResetAll but honours ctx.
+func StatisticResetAllContext(ctx context.Context, c *client.Client) error {
+	return client.StatisticResetAllContext(ctx, c, client.Services.DHCP4)
+}
+
+// StatisticRemove deletes a statistic and its samples.
+func StatisticRemove(c *client.Client, name string) error {
+	return StatisticRemoveContext(context.Background(), c, name)
+}
+
+// StatisticRemoveContext is like StatisticRemove but honours ctx.
+func StatisticRemoveContext(ctx context.Context, c *client.Client, name string) error {
+	return client.StatisticRemoveContext(ctx, c, client.Services.DHCP4, name)
+}
+
+// StatisticRemoveAll deletes every statistic.
+func StatisticRemoveAll(c *client.Client) error {
+	return StatisticRemoveAllContext(context.Background(), c)
+}
+
+// StatisticRemoveAllContext is like StatisticRemoveAll but honours ctx.
+func StatisticRemoveAllContext(ctx context.Context, c *client.Client) error {
+	return client.StatisticRemoveAllContext(ctx, c, client.Services.DHCP4)
+}
+
+// StatisticSampleAgeSet limits a statistic to samples younger than age.
+func StatisticSampleAgeSet(c *client.Client, name string, age time.Duration) error {
+	return StatisticSampleAgeSetContext(context.Background(), c, name, age)
+}
+
+// StatisticSampleAgeSetContext is like StatisticSampleAgeSet but honours ctx.
+func StatisticSampleAgeSetContext(ctx context.Context, c *client.Client, name string, age time.Duration) error {
+	return client.StatisticSampleAgeSetContext(ctx, c, client.Services.DHCP4, name, age)
+}
+
+// StatisticSampleAgeSetAll limits every statistic to samples younger than age.
+func StatisticSampleAgeSetAll(c *client.Client, age time.Duration) error {
+	return StatisticSampleAgeSetAllContext(context.Background(), c, age)
+}
+
+// StatisticSampleAgeSetAllContext is like StatisticSampleAgeSetAll but honours ctx.
+func StatisticSampleAgeSetAllContext(ctx context.Context, c *client.Client, age time.Duration) error {
+	return client.StatisticSampleAgeSetAllContext(ctx, c, client.Services.DHCP4, age)
+}
+
+// StatisticSampleCountSet limits a statistic to its most recent maxSamples samples.
+func StatisticSampleCountSet(c *client.Client, name string, maxSamples int) error {
+	return StatisticSampleCountSetContext(context.Background(), c, name, maxSamples)
+}
+
+// StatisticSampleCountSetContext is like StatisticSampleCountSet but honours ctx.
+func StatisticSampleCountSetContext(ctx context.Context, c *client.Client, name string, maxSamples int) error {
+	return client.StatisticSampleCountSetContext(ctx, c, client.Services.DHCP4, name, maxSamples)
+}
+
+// StatisticSampleCountSetAll limits every statistic to its most recent maxSamples samples.
+func StatisticSampleCountSetAll(c *client.Client, maxSamples int) error {
+	return StatisticSampleCountSetAllContext(context.Background(), c, maxSamples)
+}
+
+// StatisticSampleCountSetAllContext is like StatisticSampleCountSetAll but honours ctx.
+func StatisticSampleCountSetAllContext(ctx context.Context, c *client.Client, maxSamples int) error {
+	return client.StatisticSampleCountSetAllContext(ctx, c, client.Services.DHCP4, maxSamples)
+}
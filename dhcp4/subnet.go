@@ -0,0 +1,127 @@
+package dhcp4
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/rannday/kea-api/client"
+	"github.com/rannday/kea-api/types"
+)
+
+/*
+ * Runtime subnet management via the subnet_cmds hook library (libdhcp_subnet_cmds.so).
+ * Changes apply to the running configuration only; use config-write to persist them.
+ */
+
+// Subnet4List lists the ID and prefix of every configured subnet.
+func Subnet4List(c *client.Client) ([]types.SubnetRef, error) {
+	return Subnet4ListContext(context.Background(), c)
+}
+
+// Subnet4ListContext is like Subnet4List but honours ctx.
+func Subnet4ListContext(ctx context.Context, c *client.Client) ([]types.SubnetRef, error) {
+	list, err := client.DecodeFirstWithArgsContext[types.SubnetRefList](ctx, c, "subnet4-list", nil, client.Services.DHCP4)
+	if client.IsNotFound(err) {
+		return []types.SubnetRef{}, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	return list.Subnets, nil
+}
+
+// Subnet4Get fetches the full definition of a subnet by ID.
+func Subnet4Get(c *client.Client, id int) (Subnet4, error) {
+	return Subnet4GetContext(context.Background(), c, id)
+}
+
+// Subnet4GetContext is like Subnet4Get but honours ctx.
+func Subnet4GetContext(ctx context.Context, c *client.Client, id int) (Subnet4, error) {
+	return getSubnet4(ctx, c, map[string]interface{}{"id": id})
+}
+
+// Subnet4GetByPrefix fetches the full definition of a subnet by prefix, e.g. "192.0.2.0/24".
+func Subnet4GetByPrefix(c *client.Client, prefix string) (Subnet4, error) {
+	return Subnet4GetByPrefixContext(context.Background(), c, prefix)
+}
+
+// Subnet4GetByPrefixContext is like Subnet4GetByPrefix but honours ctx.
+func Subnet4GetByPrefixContext(ctx context.Context, c *client.Client, prefix string) (Subnet4, error) {
+	return getSubnet4(ctx, c, map[string]interface{}{"subnet": prefix})
+}
+
+// Subnet4Add adds a new subnet and returns its ID and prefix as assigned by the server.
+func Subnet4Add(c *client.Client, s Subnet4) (types.SubnetRef, error) {
+	return Subnet4AddContext(context.Background(), c, s)
+}
+
+// Subnet4AddContext is like Subnet4Add but honours ctx.
+func Subnet4AddContext(ctx context.Context, c *client.Client, s Subnet4) (types.SubnetRef, error) {
+	return changeSubnet4(ctx, c, "subnet4-add", Subnet4Args{Subnet4: []Subnet4{s}})
+}
+
+// Subnet4Update replaces an existing subnet, matched by ID. Omitted parameters revert to their defaults.
+func Subnet4Update(c *client.Client, s Subnet4) (types.SubnetRef, error) {
+	return Subnet4UpdateContext(context.Background(), c, s)
+}
+
+// Subnet4UpdateContext is like Subnet4Update but honours ctx.
+func Subnet4UpdateContext(ctx context.Context, c *client.Client, s Subnet4) (types.SubnetRef, error) {
+	return changeSubnet4(ctx, c, "subnet4-update", Subnet4Args{Subnet4: []Subnet4{s}})
+}
+
+// Subnet4Del removes a subnet by ID. Its leases are left in the lease database.
+func Subnet4Del(c *client.Client, id int) (types.SubnetRef, error) {
+	return Subnet4DelContext(context.Background(), c, id)
+}
+
+// Subnet4DelContext is like Subnet4Del but honours ctx.
+func Subnet4DelContext(ctx context.Context, c *client.Client, id int) (types.SubnetRef, error) {
+	return changeSubnet4(ctx, c, "subnet4-del", map[string]interface{}{"id": id})
+}
+
+// Subnet4DeltaAdd adds or overwrites the given parameters, pools and options of an existing
+// subnet while leaving everything else untouched. s must carry the subnet's ID and prefix.
+func Subnet4DeltaAdd(c *client.Client, s Subnet4) (types.SubnetRef, error) {
+	return Subnet4DeltaAddContext(context.Background(), c, s)
+}
+
+// Subnet4DeltaAddContext is like Subnet4DeltaAdd but honours ctx.
+func Subnet4DeltaAddContext(ctx context.Context, c *client.Client, s Subnet4) (types.SubnetRef, error) {
+	return changeSubnet4(ctx, c, "subnet4-delta-add", Subnet4Args{Subnet4: []Subnet4{s}})
+}
+
+// Subnet4DeltaDel removes the given parameters, pools and options from an existing subnet.
+// s must carry the subnet's ID and prefix.
+func Subnet4DeltaDel(c *client.Client, s Subnet4) (types.SubnetRef, error) {
+	return Subnet4DeltaDelContext(context.Background(), c, s)
+}
+
+// Subnet4DeltaDelContext is like Subnet4DeltaDel but honours ctx.
+func Subnet4DeltaDelContext(ctx context.Context, c *client.Client, s Subnet4) (types.SubnetRef, error) {
+	return changeSubnet4(ctx, c, "subnet4-delta-del", Subnet4Args{Subnet4: []Subnet4{s}})
+}
+
+// getSubnet4 runs subnet4-get and unwraps the single-element "subnet4" list.
+func getSubnet4(ctx context.Context, c *client.Client, args map[string]interface{}) (Subnet4, error) {
+	res, err := client.DecodeFirstWithArgsContext[Subnet4Args](ctx, c, "subnet4-get", args, client.Services.DHCP4)
+	if err != nil {
+		return Subnet4{}, err
+	}
+	if len(res.Subnet4) == 0 {
+		return Subnet4{}, fmt.Errorf("subnet4-get returned no subnet")
+	}
+	return res.Subnet4[0], nil
+}
+
+// changeSubnet4 runs a subnet-modifying command and returns the affected subnet.
+func changeSubnet4(ctx context.Context, c *client.Client, cmd string, args interface{}) (types.SubnetRef, error) {
+	res, err := client.DecodeFirstWithArgsContext[types.SubnetRefList](ctx, c, cmd, args, client.Services.DHCP4)
+	if err != nil {
+		return types.SubnetRef{}, err
+	}
+	if len(res.Subnets) == 0 {
+		return types.SubnetRef{}, fmt.Errorf("%s returned no subnet", cmd)
+	}
+	return res.Subnets[0], nil
+}
@@ -0,0 +1,144 @@
+package dhcp4
+
+import (
+	"encoding/json"
+	"reflect"
+	"testing"
+
+	"github.com/rannday/kea-api/client"
+	"github.com/rannday/kea-api/internal/testenv"
+	"github.com/rannday/kea-api/types"
+)
+
+// TestSubnet4List decodes the subnet summaries and handles an empty server.
+func TestSubnet4List(t *testing.T) {
+	t.Parallel()
+
+	mockClient := testenv.NewSequenceMockClient(t,
+		testenv.MockStep{
+			Validate: testenv.ExpectCommandArgs(t, "subnet4-list", nil, client.Services.DHCP4),
+			Responses: []client.CommandResponse{{
+				Result:    client.ResultSuccess,
+				Text:      "2 IPv4 subnets found",
+				Arguments: json.RawMessage(`{"subnets": [{"id": 10, "subnet": "10.0.0.0/8"}, {"id": 100, "subnet": "192.0.2.0/24"}]}`),
+			}},
+		},
+		testenv.MockStep{Responses: []client.CommandResponse{{Result: client.ResultNotFound, Text: "0 IPv4 subnets found"}}},
+	)
+
+	got, err := Subnet4List(mockClient)
+	if err != nil {
+		t.Fatalf("Subnet4List() error = %v", err)
+	}
+	want := []types.SubnetRef{{ID: 10, Subnet: "10.0.0.0/8"}, {ID: 100, Subnet: "192.0.2.0/24"}}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("Subnet4List() = %+v, want %+v", got, want)
+	}
+
+	got, err = Subnet4List(mockClient)
+	if err != nil || len(got) != 0 {
+		t.Errorf("Subnet4List() = %+v, %v, want empty", got, err)
+	}
+}
+
+// TestSubnet4Get decodes a subnet4-get reply.
+func TestSubnet4Get(t *testing.T) {
+	t.Parallel()
+
+	subnet := `{
+		"id": 10, "subnet": "10.0.0.0/8", "valid-lifetime": 120, "shared-network-name": null,
+		"pools": [{"pool": "10.0.0.10-10.0.0.100", "option-data": []}],
+		"option-data": [{"name": "routers", "data": "10.0.0.1"}]
+	}`
+	mockClient := testenv.NewMockClient(t,
+		testenv.ExpectCommandArgs(t, "subnet4-get", map[string]any{"id": 10}, client.Services.DHCP4),
+		[]client.CommandResponse{{
+			Result:    client.ResultSuccess,
+			Text:      "Info about IPv4 subnet 10.0.0.0/8 (id 10) returned",
+			Arguments: json.RawMessage(`{"subnet4": [` + subnet + `]}`),
+		}},
+	)
+
+	got, err := Subnet4Get(mockClient, 10)
+	if err != nil {
+		t.Fatalf("Subnet4Get() error = %v", err)
+	}
+	want := Subnet4{
+		ID:            10,
+		Subnet:        "10.0.0.0/8",
+		ValidLifetime: 120,
+		Pools:         []Pool4{{Pool: "10.0.0.10-10.0.0.100", OptionData: []types.OptionData{}}},
+		OptionData:    []types.OptionData{{Name: "routers", Data: "10.0.0.1"}},
+	}
+	if !testenv.EqualExported(got, want) {
+		t.Errorf("Subnet4Get() = %+v, want %+v", got, want)
+	}
+
+	// Members Kea sent empty are reproduced although the fields omit them.
+	out, err := json.Marshal(got)
+	if err != nil {
+		t.Fatalf("Marshal() error = %v", err)
+	}
+	testenv.ExpectJSONEquivalent(t, out, []byte(subnet))
+}
+
+// TestSubnet4GetByPrefix checks lookup by prefix and the not-found case.
+func TestSubnet4GetByPrefix(t *testing.T) {
+	t.Parallel()
+
+	mockClient := testenv.NewMockClient(t,
+		testenv.ExpectCommandArgs(t, "subnet4-get", map[string]any{"subnet": "198.51.100.0/24"}, client.Services.DHCP4),
+		[]client.CommandResponse{{Result: client.ResultNotFound, Text: "No 198.51.100.0/24 subnet found"}},
+	)
+
+	_, err := Subnet4GetByPrefix(mockClient, "198.51.100.0/24")
+	if !client.IsNotFound(err) {
+		t.Errorf("Subnet4GetByPrefix() error = %v, want not found", err)
+	}
+}
+
+// TestSubnet4Changes checks the request layout and returned reference of each modifying command.
+func TestSubnet4Changes(t *testing.T) {
+	t.Parallel()
+
+	subnet := Subnet4{ID: 123, Subnet: "10.20.30.0/24", Pools: []Pool4{{Pool: "10.20.30.1-10.20.30.10"}}}
+	subnetArgs := map[string]any{"subnet4": []any{map[string]any{
+		"id":     123,
+		"subnet": "10.20.30.0/24",
+		"pools":  []any{map[string]any{"pool": "10.20.30.1-10.20.30.10"}},
+	}}}
+
+	tests := []struct {
+		command string
+		args    map[string]any
+		call    func(*client.Client) (types.SubnetRef, error)
+	}{
+		{"subnet4-add", subnetArgs, func(c *client.Client) (types.SubnetRef, error) { return Subnet4Add(c, subnet) }},
+		{"subnet4-update", subnetArgs, func(c *client.Client) (types.SubnetRef, error) { return Subnet4Update(c, subnet) }},
+		{"subnet4-delta-add", subnetArgs, func(c *client.Client) (types.SubnetRef, error) { return Subnet4DeltaAdd(c, subnet) }},
+		{"subnet4-delta-del", subnetArgs, func(c *client.Client) (types.SubnetRef, error) { return Subnet4DeltaDel(c, subnet) }},
+		{"subnet4-del", map[string]any{"id": 123}, func(c *client.Client) (types.SubnetRef, error) { return Subnet4Del(c, 123) }},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.command, func(t *testing.T) {
+			t.Parallel()
+
+			mockClient := testenv.NewMockClient(t,
+				testenv.ExpectCommandArgs(t, tt.command, tt.args, client.Services.DHCP4),
+				[]client.CommandResponse{{
+					Result:    client.ResultSuccess,
+					Arguments: json.RawMessage(`{"subnets": [{"id": 123, "subnet": "10.20.30.0/24"}]}`),
+				}},
+			)
+
+			got, err := tt.call(mockClient)
+			if err != nil {
+				t.Fatalf("%s error = %v", tt.command, err)
+			}
+			if got != (types.SubnetRef{ID: 123, Subnet: "10.20.30.0/24"}) {
+				t.Errorf("%s = %+v", tt.command, got)
+			}
+		})
+	}
+}
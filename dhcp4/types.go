@@ -1,6 +1,12 @@
 package dhcp4
 
-import "github.com/rannday/kea-api/types"
+import (
+	"encoding/json"
+	"net/netip"
+
+	"github.com/rannday/kea-api/internal/utils"
+	"github.com/rannday/kea-api/types"
+)
 
 // DHCP4Status represents the response from "status-get" on kea-dhcp4.
 type DHCP4Status struct {
@@ -13,6 +19,7 @@ type DHCP4Status struct {
 	PacketQueueStatistics []float64              `json:"packet-queue-statistics"`
 	Sockets               map[string]interface{} `json:"sockets"`
 	DHCPState             types.DHCPState        `json:"dhcp-state"`
+	HighAvailability      []types.HAStatus       `json:"high-availability,omitempty"` // Present when the ha hook library is loaded
 }
 
 // DHCP4Version is the response from "version-get" on kea-dhcp4.
@@ -22,8 +29,10 @@ type DHCP4Version struct {
 
 // Dhcp4Config is the typed response from "config-get" on the dhcp4 service.
 type Dhcp4Config struct {
-	Dhcp4 Dhcp4Block `json:"Dhcp4"`
-	Hash  string     `json:"hash"`
+	Dhcp4  Dhcp4Block                 `json:"Dhcp4"`
+	Hash   string                     `json:"hash"`
+	Extra  map[string]json.RawMessage `json:"-"`
+	absent utils.Absent
 }
 
 // Dhcp4Block holds the core DHCPv4 configuration parameters.
@@ -61,9 +70,12 @@ type Dhcp4Block struct {
 	MatchClientID              bool                       `json:"match-client-id"`
 	MultiThreading             types.MultiThreadingConfig `json:"multi-threading"`
 	NextServer                 string                     `json:"next-server"`
-	OptionData                 []interface{}              `json:"option-data"`
-	OptionDef                  []interface{}              `json:"option-def"`
+	OptionData                 []types.OptionData         `json:"option-data"`
+	OptionDef                  []types.OptionDef          `json:"option-def"`
 	ParkedPacketLimit          int                        `json:"parked-packet-limit"`
+	RebindTimer                int                        `json:"rebind-timer"`
+	RenewTimer                 int                        `json:"renew-timer"`
+	Reservations               []Reservation4             `json:"reservations,omitempty"`
 	ReservationsGlobal         bool                       `json:"reservations-global"`
 	ReservationsInSubnet       bool                       `json:"reservations-in-subnet"`
 	ReservationsLookupFirst    bool                       `json:"reservations-lookup-first"`
@@ -71,47 +83,235 @@ type Dhcp4Block struct {
 	SanityChecks               types.SanityChecks         `json:"sanity-checks"`
 	ServerHostname             string                     `json:"server-hostname"`
 	ServerTag                  string                     `json:"server-tag"`
-	SharedNetworks             []interface{}              `json:"shared-networks"`
+	SharedNetworks             []SharedNetwork4           `json:"shared-networks"`
 	StashAgentOptions          bool                       `json:"stash-agent-options"`
 	StatisticSampleAge         int                        `json:"statistic-default-sample-age"`
 	StatisticSampleCount       int                        `json:"statistic-default-sample-count"`
 	StoreExtendedInfo          bool                       `json:"store-extended-info"`
-	Subnet4                    []interface{}              `json:"subnet4"`
+	Subnet4                    []Subnet4                  `json:"subnet4"`
 	T1Percent                  float64                    `json:"t1-percent"`
 	T2Percent                  float64                    `json:"t2-percent"`
 	ValidLifetime              int                        `json:"valid-lifetime"`
+	Extra                      map[string]json.RawMessage `json:"-"`
+	absent                     utils.Absent
 }
 
 // ConfigControl defines external config database references.
 type ConfigControl struct {
-	ConfigDatabases []types.DatabaseConfig `json:"config-databases"`
+	ConfigDatabases []types.DatabaseConfig     `json:"config-databases"`
+	Extra           map[string]json.RawMessage `json:"-"`
+	absent          utils.Absent
 }
 
 // DhcpDDNSConfig specifies how to send DDNS updates.
 type DhcpDDNSConfig struct {
-	EnableUpdates bool   `json:"enable-updates"`
-	MaxQueueSize  int    `json:"max-queue-size"`
-	NCRFormat     string `json:"ncr-format"`
-	NCRProtocol   string `json:"ncr-protocol"`
-	SenderIP      string `json:"sender-ip"`
-	SenderPort    int    `json:"sender-port"`
-	ServerIP      string `json:"server-ip"`
-	ServerPort    int    `json:"server-port"`
+	EnableUpdates bool                       `json:"enable-updates"`
+	MaxQueueSize  int                        `json:"max-queue-size"`
+	NCRFormat     string                     `json:"ncr-format"`
+	NCRProtocol   string                     `json:"ncr-protocol"`
+	SenderIP      string                     `json:"sender-ip"`
+	SenderPort    int                        `json:"sender-port"`
+	ServerIP      string                     `json:"server-ip"`
+	ServerPort    int                        `json:"server-port"`
+	Extra         map[string]json.RawMessage `json:"-"`
+	absent        utils.Absent
 }
 
 // DhcpQueueControl governs packet queueing for async processing.
 type DhcpQueueControl struct {
-	Capacity    int    `json:"capacity"`
-	EnableQueue bool   `json:"enable-queue"`
-	QueueType   string `json:"queue-type"`
+	Capacity    int                        `json:"capacity"`
+	EnableQueue bool                       `json:"enable-queue"`
+	QueueType   string                     `json:"queue-type"`
+	Extra       map[string]json.RawMessage `json:"-"`
+	absent      utils.Absent
 }
 
 // ExpiredLeasesProcessing configures lease reclamation behavior.
 type ExpiredLeasesProcessing struct {
-	FlushReclaimedTimerWaitTime int `json:"flush-reclaimed-timer-wait-time"`
-	HoldReclaimedTime           int `json:"hold-reclaimed-time"`
-	MaxReclaimLeases            int `json:"max-reclaim-leases"`
-	MaxReclaimTime              int `json:"max-reclaim-time"`
-	ReclaimTimerWaitTime        int `json:"reclaim-timer-wait-time"`
-	UnwarnedReclaimCycles       int `json:"unwarned-reclaim-cycles"`
+	FlushReclaimedTimerWaitTime int                        `json:"flush-reclaimed-timer-wait-time"`
+	HoldReclaimedTime           int                        `json:"hold-reclaimed-time"`
+	MaxReclaimLeases            int                        `json:"max-reclaim-leases"`
+	MaxReclaimTime              int                        `json:"max-reclaim-time"`
+	ReclaimTimerWaitTime        int                        `json:"reclaim-timer-wait-time"`
+	UnwarnedReclaimCycles       int                        `json:"unwarned-reclaim-cycles"`
+	Extra                       map[string]json.RawMessage `json:"-"`
+	absent                      utils.Absent
+}
+
+// Lease4 is an IPv4 lease as handled by the lease4-* commands of the lease_cmds hook.
+// Expire is only sent on lease4-add and lease4-update; Kea reports CLTT instead.
+type Lease4 struct {
+	IPAddress   string                 `json:"ip-address"`
+	HWAddress   string                 `json:"hw-address,omitempty"`
+	ClientID    string                 `json:"client-id,omitempty"`
+	ValidLft    int                    `json:"valid-lft,omitempty"`
+	CLTT        int64                  `json:"cltt,omitempty"`
+	Expire      int64                  `json:"expire,omitempty"`
+	SubnetID    int                    `json:"subnet-id,omitempty"`
+	PoolID      int                    `json:"pool-id,omitempty"`
+	FQDNFwd     bool                   `json:"fqdn-fwd,omitempty"`
+	FQDNRev     bool                   `json:"fqdn-rev,omitempty"`
+	Hostname    string                 `json:"hostname,omitempty"`
+	State       types.LeaseState       `json:"state"`
+	UserContext map[string]interface{} `json:"user-context,omitempty"`
+}
+
+// Lease4Query selects a single lease for lease4-get and lease4-del.
+// Set either IPAddress, or IdentifierType ("hw-address" or "client-id"), Identifier and SubnetID.
+type Lease4Query struct {
+	IPAddress      string `json:"ip-address,omitempty"`
+	IdentifierType string `json:"identifier-type,omitempty"`
+	Identifier     string `json:"identifier,omitempty"`
+	SubnetID       int    `json:"subnet-id,omitempty"`
+	UpdateDDNS     bool   `json:"update-ddns,omitempty"` // lease4-del only: remove the DNS entries too
+}
+
+// Lease4List is the arguments block returned by the lease4-get-all and lease4-get-by-* commands.
+type Lease4List struct {
+	Leases []Lease4 `json:"leases"`
+}
+
+// Lease4Page is the arguments block returned by lease4-get-page.
+type Lease4Page struct {
+	Leases []Lease4 `json:"leases"`
+	Count  int      `json:"count"`
+}
+
+// Reservation4 is an IPv4 host reservation, as handled by the reservation-* commands of the
+// host_cmds hook. Exactly one identifier field should be set. SubnetID is 0 for global
+// reservations and is always sent by ReservationAdd and ReservationUpdate.
+type Reservation4 struct {
+	SubnetID       int                        `json:"subnet-id,omitempty"`
+	HWAddress      string                     `json:"hw-address,omitempty"`
+	DUID           string                     `json:"duid,omitempty"`
+	ClientID       string                     `json:"client-id,omitempty"`
+	CircuitID      string                     `json:"circuit-id,omitempty"`
+	FlexID         string                     `json:"flex-id,omitempty"`
+	IPAddress      string                     `json:"ip-address,omitempty"`
+	Hostname       string                     `json:"hostname,omitempty"`
+	ClientClasses  []string                   `json:"client-classes,omitempty"`
+	OptionData     []types.OptionData         `json:"option-data,omitempty"`
+	NextServer     string                     `json:"next-server,omitempty"`
+	ServerHostname string                     `json:"server-hostname,omitempty"`
+	BootFileName   string                     `json:"boot-file-name,omitempty"`
+	UserContext    map[string]interface{}     `json:"user-context,omitempty"`
+	Extra          map[string]json.RawMessage `json:"-"`
+	absent         utils.Absent
+}
+
+// Identifier returns the identifier type and value of the reservation, or empty strings if none is set.
+func (r Reservation4) Identifier() (string, string) {
+	switch {
+	case r.HWAddress != "":
+		return "hw-address", r.HWAddress
+	case r.DUID != "":
+		return "duid", r.DUID
+	case r.ClientID != "":
+		return "client-id", r.ClientID
+	case r.CircuitID != "":
+		return "circuit-id", r.CircuitID
+	case r.FlexID != "":
+		return "flex-id", r.FlexID
+	}
+	return "", ""
+}
+
+// Reservation4List is the arguments block returned by reservation-get-all and reservation-get-by-*.
+type Reservation4List struct {
+	Hosts []Reservation4 `json:"hosts"`
+}
+
+// Reservation4Page is the arguments block returned by reservation-get-page.
+type Reservation4Page struct {
+	Count int                          `json:"count"`
+	Hosts []Reservation4               `json:"hosts"`
+	Next  *types.ReservationPageCursor `json:"next,omitempty"`
+}
+
+// Subnet4 is an IPv4 subnet, as used by the subnet4-* commands of the subnet_cmds hook
+// and in the subnet4 and shared-networks lists of the configuration.
+// Zero values are omitted so the server-level defaults apply.
+type Subnet4 struct {
+	ID                    int                    `json:"id,omitempty"`
+	Subnet                string                 `json:"subnet"`
+	Pools                 []Pool4                `json:"pools,omitempty"`
+	OptionData            []types.OptionData     `json:"option-data,omitempty"`
+	Reservations          []Reservation4         `json:"reservations,omitempty"`
+	SharedNetworkName     string                 `json:"shared-network-name,omitempty"`
+	Interface             string                 `json:"interface,omitempty"`
+	Relay                 *types.Relay           `json:"relay,omitempty"`
+	ValidLifetime         int                    `json:"valid-lifetime,omitempty"`
+	MinValidLifetime      int                    `json:"min-valid-lifetime,omitempty"`
+	MaxValidLifetime      int                    `json:"max-valid-lifetime,omitempty"`
+	RenewTimer            int                    `json:"renew-timer,omitempty"`
+	RebindTimer           int                    `json:"rebind-timer,omitempty"`
+	CalculateTeeTimes     *bool                  `json:"calculate-tee-times,omitempty"`
+	T1Percent             float64                `json:"t1-percent,omitempty"`
+	T2Percent             float64                `json:"t2-percent,omitempty"`
+	Authoritative         *bool                  `json:"authoritative,omitempty"`
+	MatchClientID         *bool                  `json:"match-client-id,omitempty"`
+	ReservationsGlobal    *bool                  `json:"reservations-global,omitempty"`
+	ReservationsInSubnet  *bool                  `json:"reservations-in-subnet,omitempty"`
+	ReservationsOutOfPool *bool                  `json:"reservations-out-of-pool,omitempty"`
+	NextServer            string                 `json:"next-server,omitempty"`
+	ServerHostname        string                 `json:"server-hostname,omitempty"`
+	BootFileName          string                 `json:"boot-file-name,omitempty"`
+	UserContext           map[string]interface{} `json:"user-context,omitempty"`
+	types.ClientClassRestriction
+	Extra  map[string]json.RawMessage `json:"-"`
+	absent utils.Absent
+}
+
+// Pool4 is an address pool within a Subnet4, given as "first - last" or in CIDR notation.
+type Pool4 struct {
+	Pool        string                 `json:"pool"`
+	PoolID      int                    `json:"pool-id,omitempty"`
+	OptionData  []types.OptionData     `json:"option-data,omitempty"`
+	UserContext map[string]interface{} `json:"user-context,omitempty"`
+	types.ClientClassRestriction
+	Extra  map[string]json.RawMessage `json:"-"`
+	absent utils.Absent
+}
+
+// Range returns the first and last address of the pool.
+func (p Pool4) Range() (netip.Addr, netip.Addr, error) {
+	return types.ParsePool(p.Pool)
+}
+
+// Subnet4Args is the "subnet4" list sent by subnet4-add/update/delta-* and returned by subnet4-get.
+type Subnet4Args struct {
+	Subnet4 []Subnet4 `json:"subnet4"`
+}
+
+// SharedNetwork4 groups IPv4 subnets that share a physical link, as used by the network4-* commands.
+// Parameters set here are inherited by the member subnets unless they override them.
+type SharedNetwork4 struct {
+	Name                  string                 `json:"name"`
+	Subnet4               []Subnet4              `json:"subnet4,omitempty"`
+	Interface             string                 `json:"interface,omitempty"`
+	Relay                 *types.Relay           `json:"relay,omitempty"`
+	OptionData            []types.OptionData     `json:"option-data,omitempty"`
+	ValidLifetime         int                    `json:"valid-lifetime,omitempty"`
+	MinValidLifetime      int                    `json:"min-valid-lifetime,omitempty"`
+	MaxValidLifetime      int                    `json:"max-valid-lifetime,omitempty"`
+	RenewTimer            int                    `json:"renew-timer,omitempty"`
+	RebindTimer           int                    `json:"rebind-timer,omitempty"`
+	CalculateTeeTimes     *bool                  `json:"calculate-tee-times,omitempty"`
+	T1Percent             float64                `json:"t1-percent,omitempty"`
+	T2Percent             float64                `json:"t2-percent,omitempty"`
+	Authoritative         *bool                  `json:"authoritative,omitempty"`
+	MatchClientID         *bool                  `json:"match-client-id,omitempty"`
+	ReservationsOutOfPool *bool                  `json:"reservations-out-of-pool,omitempty"`
+	NextServer            string                 `json:"next-server,omitempty"`
+	ServerHostname        string                 `json:"server-hostname,omitempty"`
+	BootFileName          string                 `json:"boot-file-name,omitempty"`
+	UserContext           map[string]interface{} `json:"user-context,omitempty"`
+	types.ClientClassRestriction
+	Extra  map[string]json.RawMessage `json:"-"`
+	absent utils.Absent
+}
+
+// SharedNetwork4Args is the "shared-networks" list sent by network4-add and returned by network4-get.
+type SharedNetwork4Args struct {
+	SharedNetworks []SharedNetwork4 `json:"shared-networks"`
 }
@@ -0,0 +1,178 @@
+package dhcp4
+
+import (
+	"testing"
+
+	"github.com/rannday/kea-api/types"
+)
+
+func boolPtr(b bool) *bool { return &b }
+
+// diagKey is the part of a diagnostic the tests compare.
+type diagKey struct {
+	Code    types.DiagnosticCode
+	Pointer string
+}
+
+func diagKeys(diags types.Diagnostics) []diagKey {
+	var keys []diagKey
+	for _, d := range diags {
+		keys = append(keys, diagKey{d.Code, d.Pointer})
+	}
+	return keys
+}
+
+// TestValidate covers each check on a minimal configuration.
+func TestValidate(t *testing.T) {
+	t.Parallel()
+
+	base := func() Dhcp4Block {
+		return Dhcp4Block{
+			ValidLifetime: 3600,
+			Subnet4: []Subnet4{{
+				ID: 1, Subnet: "192.0.2.0/24",
+				Pools: []Pool4{{Pool: "192.0.2.10 - 192.0.2.100"}},
+			}},
+		}
+	}
+
+	tests := []struct {
+		name   string
+		modify func(b *Dhcp4Block)
+		opts   types.ValidateOptions
+		want   []diagKey
+	}{
+		{"valid", func(b *Dhcp4Block) {}, types.ValidateOptions{}, nil},
+		{"duplicate subnet id", func(b *Dhcp4Block) {
+			b.SharedNetworks = []SharedNetwork4{{Name: "net", Subnet4: []Subnet4{{ID: 1, Subnet: "198.51.100.0/24"}}}}
+		}, types.ValidateOptions{}, []diagKey{{types.DiagDuplicateSubnetID, "/Dhcp4/subnet4/0/id"}}},
+		{"invalid subnet", func(b *Dhcp4Block) {
+			b.Subnet4[0].Subnet = "2001:db8::/64"
+		}, types.ValidateOptions{}, []diagKey{{types.DiagInvalidValue, "/Dhcp4/subnet4/0/subnet"}}},
+		{"pool outside subnet", func(b *Dhcp4Block) {
+			b.Subnet4[0].Pools = append(b.Subnet4[0].Pools, Pool4{Pool: "192.0.3.0/28"})
+		}, types.ValidateOptions{}, []diagKey{{types.DiagPoolOutsideSubnet, "/Dhcp4/subnet4/0/pools/1/pool"}}},
+		{"overlapping pools", func(b *Dhcp4Block) {
+			b.Subnet4[0].Pools = append(b.Subnet4[0].Pools, Pool4{Pool: "192.0.2.96/28"})
+		}, types.ValidateOptions{}, []diagKey{{types.DiagPoolOverlap, "/Dhcp4/subnet4/0/pools/1"}}},
+		{"reservation in pool", func(b *Dhcp4Block) {
+			b.ReservationsOutOfPool = true
+			b.Subnet4[0].Reservations = []Reservation4{
+				{HWAddress: "aa:bb:cc:dd:ee:ff", IPAddress: "192.0.2.20"},
+				{HWAddress: "aa:bb:cc:dd:ee:00", IPAddress: "192.0.2.200"},
+			}
+		}, types.ValidateOptions{}, []diagKey{{types.DiagReservationInPool, "/Dhcp4/subnet4/0/reservations/0/ip-address"}}},
+		{"reservation in pool allowed", func(b *Dhcp4Block) {
+			b.ReservationsOutOfPool = true
+			b.Subnet4[0].ReservationsOutOfPool = boolPtr(false)
+			b.Subnet4[0].Reservations = []Reservation4{{HWAddress: "aa:bb:cc:dd:ee:ff", IPAddress: "192.0.2.20"}}
+		}, types.ValidateOptions{}, nil},
+		{"reservation in pool in shared network", func(b *Dhcp4Block) {
+			b.SharedNetworks = []SharedNetwork4{{Name: "net", ReservationsOutOfPool: boolPtr(true), Subnet4: []Subnet4{{
+				ID: 2, Subnet: "198.51.100.0/24",
+				Pools:        []Pool4{{Pool: "198.51.100.10 - 198.51.100.100"}},
+				Reservations: []Reservation4{{HWAddress: "aa:bb:cc:dd:ee:ff", IPAddress: "198.51.100.20"}},
+			}}}}
+		}, types.ValidateOptions{}, []diagKey{{types.DiagReservationInPool, "/Dhcp4/shared-networks/0/subnet4/0/reservations/0/ip-address"}}},
+		{"reservation in pool allowed by shared network", func(b *Dhcp4Block) {
+			b.ReservationsOutOfPool = true
+			b.SharedNetworks = []SharedNetwork4{{Name: "net", ReservationsOutOfPool: boolPtr(false), Subnet4: []Subnet4{{
+				ID: 2, Subnet: "198.51.100.0/24",
+				Pools:        []Pool4{{Pool: "198.51.100.10 - 198.51.100.100"}},
+				Reservations: []Reservation4{{HWAddress: "aa:bb:cc:dd:ee:ff", IPAddress: "198.51.100.20"}},
+			}}}}
+		}, types.ValidateOptions{}, nil},
+		{"reservation outside subnet", func(b *Dhcp4Block) {
+			b.Subnet4[0].Reservations = []Reservation4{{HWAddress: "aa:bb:cc:dd:ee:ff", IPAddress: "10.0.0.1"}}
+		}, types.ValidateOptions{}, []diagKey{{types.DiagReservationOutsideSubnet, "/Dhcp4/subnet4/0/reservations/0/ip-address"}}},
+		{"renew after rebind", func(b *Dhcp4Block) {
+			b.RenewTimer, b.RebindTimer = 900, 1800
+			b.Subnet4[0].RenewTimer = 2000
+		}, types.ValidateOptions{}, []diagKey{{types.DiagTimerOrder, "/Dhcp4/subnet4/0/renew-timer"}}},
+		{"rebind after valid lifetime", func(b *Dhcp4Block) {
+			b.RebindTimer = 4000
+		}, types.ValidateOptions{}, []diagKey{{types.DiagTimerOrder, "/Dhcp4/rebind-timer"}}},
+		{"t1 after t2", func(b *Dhcp4Block) {
+			b.SharedNetworks = []SharedNetwork4{{Name: "net", T1Percent: 0.9, T2Percent: 0.8}}
+		}, types.ValidateOptions{}, []diagKey{{types.DiagTimerOrder, "/Dhcp4/shared-networks/0/t1-percent"}}},
+		{"unknown option", func(b *Dhcp4Block) {
+			b.OptionData = []types.OptionData{{Name: "domain-name-servers", Data: "192.0.2.1"}, {Name: "router", Data: "192.0.2.1"}}
+			b.Subnet4[0].Pools[0].OptionData = []types.OptionData{{Name: "my-option", Data: "x"}, {Code: 250, Data: "y"}}
+		}, types.ValidateOptions{}, []diagKey{
+			{types.DiagUnknownOption, "/Dhcp4/option-data/1/name"},
+			{types.DiagUnknownOption, "/Dhcp4/subnet4/0/pools/0/option-data/0/name"},
+		}},
+		{"defined option", func(b *Dhcp4Block) {
+			b.OptionDef = []types.OptionDef{{Name: "my-option", Code: 250, Type: "string", Space: "dhcp4"}}
+			b.OptionData = []types.OptionData{{Name: "my-option", Data: "x"}, {Name: "anything", Space: "vendor-4491", Data: "y"}}
+		}, types.ValidateOptions{}, nil},
+		{"control socket mismatch", func(b *Dhcp4Block) {
+			b.ControlSocket = types.SocketConfig{SocketType: "unix", SocketName: "/tmp/kea4.sock"}
+		}, types.ValidateOptions{ControlSockets: map[string]types.SocketConfig{
+			"dhcp4": {SocketType: "unix", SocketName: "/run/kea/kea4-ctrl-socket"},
+		}}, []diagKey{{types.DiagControlSocketMismatch, "/Dhcp4/control-socket/socket-name"}}},
+		{"control socket match", func(b *Dhcp4Block) {
+			b.ControlSocket = types.SocketConfig{SocketName: "/run/kea/kea4-ctrl-socket"}
+		}, types.ValidateOptions{ControlSockets: map[string]types.SocketConfig{
+			"dhcp4": {SocketType: "unix", SocketName: "/run/kea/kea4-ctrl-socket"},
+		}}, nil},
+		{"control socket missing", func(b *Dhcp4Block) {}, types.ValidateOptions{ControlSockets: map[string]types.SocketConfig{
+			"dhcp4": {SocketType: "unix", SocketName: "/run/kea/kea4-ctrl-socket"},
+		}}, []diagKey{{types.DiagControlSocketMismatch, "/Dhcp4/control-socket"}}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			b := base()
+			tt.modify(&b)
+			diags := Validate(b, tt.opts)
+			got := diagKeys(diags)
+			if len(got) != len(tt.want) {
+				t.Fatalf("Validate() = %v, want %v", diags, tt.want)
+			}
+			for i := range got {
+				if got[i] != tt.want[i] {
+					t.Errorf("Validate()[%d] = %v, want %v", i, got[i], tt.want[i])
+				}
+			}
+		})
+	}
+}
+
+// TestValidateConfigFile locates each diagnostic in the file it came from.
+func TestValidateConfigFile(t *testing.T) {
+	t.Parallel()
+
+	diags, err := ValidateConfigFile("testdata/invalid.conf", types.ValidateOptions{
+		ControlSockets: map[string]types.SocketConfig{"dhcp4": {SocketType: "unix", SocketName: "/run/kea/kea4-ctrl-socket"}},
+	})
+	if err != nil {
+		t.Fatalf("ValidateConfigFile() error = %v", err)
+	}
+
+	const file = "testdata/invalid.conf"
+	want := []struct {
+		code     types.DiagnosticCode
+		severity types.Severity
+		pos      types.Position
+	}{
+		{types.DiagReservationInPool, types.SeverityError, types.Position{File: file, Line: 14, Col: 86}},
+		{types.DiagDuplicateSubnetID, types.SeverityError, types.Position{File: file, Line: 17, Col: 23}},
+		{types.DiagTimerOrder, types.SeverityWarning, types.Position{File: file, Line: 19, Col: 32}},
+		{types.DiagUnknownOption, types.SeverityError, types.Position{File: file, Line: 20, Col: 44}},
+		{types.DiagControlSocketMismatch, types.SeverityError, types.Position{File: file, Line: 4, Col: 67}},
+	}
+	if len(diags) != len(want) {
+		t.Fatalf("ValidateConfigFile() =\n%v", diags)
+	}
+	for i, w := range want {
+		d := diags[i]
+		if d.Code != w.code || d.Severity != w.severity || d.Pos != w.pos {
+			t.Errorf("diagnostic %d = %v, want %s %s at %v", i, d, w.severity, w.code, w.pos)
+		}
+	}
+	if !diags.HasErrors() || len(diags.Errors()) != 4 {
+		t.Errorf("Errors() = %v", diags.Errors())
+	}
+}
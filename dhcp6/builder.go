@@ -0,0 +1,419 @@
+package dhcp6
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/netip"
+	"strings"
+
+	"github.com/rannday/kea-api/types"
+)
+
+/*
+ * Configuration builder. A Dhcp6Block written as a struct literal emits every modelled
+ * member, zero or not; ConfigBuilder starts from an empty decoded configuration so
+ * that only the parameters it is given end up in the output.
+ */
+
+// ConfigBuilder assembles a Dhcp6Config step by step, e.g.
+//
+//	cfg, err := dhcp6.NewConfig().
+//		Interfaces("eth0").
+//		Subnet("2001:db8:1::/64", 1).
+//		Pool("2001:db8:1::10 - 2001:db8:1::ffff").
+//		PDPool("2001:db8:8::", 48, 56).
+//		Option("dns-servers", "2001:db8::53").
+//		Reservation("01:02:03:04:05:06", "2001:db8:1::5", "2001:db8:9::/56").
+//		Build()
+//
+// Each step checks what it adds; mistakes are collected and reported by Err and Build.
+type ConfigBuilder struct {
+	cfg     Dhcp6Config
+	autoIDs bool
+	errs    []error
+}
+
+// NewConfig returns a builder for an empty DHCPv6 configuration.
+func NewConfig() *ConfigBuilder {
+	b := &ConfigBuilder{}
+	_ = json.Unmarshal([]byte(`{"Dhcp6": {}}`), &b.cfg)
+	return b
+}
+
+func (b *ConfigBuilder) errorf(format string, args ...interface{}) {
+	b.errs = append(b.errs, fmt.Errorf(format, args...))
+}
+
+// decodeInto sets v, a pointer to a config type, from its JSON members so that
+// members not given are left out when v is encoded.
+func (b *ConfigBuilder) decodeInto(v interface{}, members map[string]interface{}) {
+	data, err := json.Marshal(members)
+	if err == nil {
+		err = json.Unmarshal(data, v)
+	}
+	if err != nil {
+		b.errs = append(b.errs, err)
+	}
+}
+
+// AutoSubnetIDs makes Build assign the next free id to subnets added with id 0.
+// Without it every subnet needs an explicit id.
+func (b *ConfigBuilder) AutoSubnetIDs() *ConfigBuilder {
+	b.autoIDs = true
+	return b
+}
+
+// Interfaces sets the interfaces the server listens on, e.g. "eth0" or "eth1/2001:db8::1".
+func (b *ConfigBuilder) Interfaces(names ...string) *ConfigBuilder {
+	b.decodeInto(&b.cfg.Dhcp6.InterfacesConfig, map[string]interface{}{"interfaces": names})
+	return b
+}
+
+// ControlSocket sets the server's UNIX control socket.
+func (b *ConfigBuilder) ControlSocket(socketName string) *ConfigBuilder {
+	b.decodeInto(&b.cfg.Dhcp6.ControlSocket, map[string]interface{}{"socket-type": "unix", "socket-name": socketName})
+	return b
+}
+
+// ValidLifetime sets the global lease lifetime in seconds.
+func (b *ConfigBuilder) ValidLifetime(seconds int) *ConfigBuilder {
+	b.cfg.Dhcp6.ValidLifetime = seconds
+	return b
+}
+
+// PreferredLifetime sets the global preferred lifetime in seconds.
+func (b *ConfigBuilder) PreferredLifetime(seconds int) *ConfigBuilder {
+	b.cfg.Dhcp6.PreferredLifetime = seconds
+	return b
+}
+
+// Timers sets the global renew (T1) and rebind (T2) timers in seconds.
+func (b *ConfigBuilder) Timers(renew, rebind int) *ConfigBuilder {
+	if renew > rebind {
+		b.errorf("renew-timer %d exceeds rebind-timer %d", renew, rebind)
+	}
+	b.cfg.Dhcp6.RenewTimer, b.cfg.Dhcp6.RebindTimer = renew, rebind
+	return b
+}
+
+// OptionDef defines a custom option. Define options before using them by name.
+func (b *ConfigBuilder) OptionDef(def types.OptionDef) *ConfigBuilder {
+	for _, d := range b.cfg.Dhcp6.OptionDef {
+		if d.Name == def.Name && d.Space == def.Space {
+			b.errorf("option-def %q is already defined", def.Name)
+			return b
+		}
+	}
+	b.cfg.Dhcp6.OptionDef = append(b.cfg.Dhcp6.OptionDef, def)
+	return b
+}
+
+// Option adds a global option in the dhcp6 space.
+func (b *ConfigBuilder) Option(name, data string) *ConfigBuilder {
+	b.cfg.Dhcp6.OptionData = b.option(b.cfg.Dhcp6.OptionData, "global", name, data)
+	return b
+}
+
+// option checks name and appends it to list; scope names the list in errors.
+func (b *ConfigBuilder) option(list []types.OptionData, scope, name, data string) []types.OptionData {
+	if _, ok := standardOptions[name]; !ok && !b.definedOption(name) {
+		b.errorf("%s: option %q is not a standard dhcp6 option and has no option-def", scope, name)
+	}
+	return append(list, types.OptionData{Name: name, Data: data})
+}
+
+func (b *ConfigBuilder) definedOption(name string) bool {
+	for _, d := range b.cfg.Dhcp6.OptionDef {
+		if d.Name == name && (d.Space == "" || d.Space == "dhcp6") {
+			return true
+		}
+	}
+	return false
+}
+
+// Set applies fn to the configuration block, for parameters the builder has no step for.
+func (b *ConfigBuilder) Set(fn func(*Dhcp6Block)) *ConfigBuilder {
+	fn(&b.cfg.Dhcp6)
+	return b
+}
+
+// Subnet adds a subnet with the given id, or 0 to have Build assign one after AutoSubnetIDs.
+// The returned builder adds pools, options and reservations to it.
+func (b *ConfigBuilder) Subnet(prefix string, id int) *SubnetBuilder {
+	return b.addSubnet(-1, prefix, id)
+}
+
+// SharedNetwork adds a shared network. The returned builder adds subnets to it.
+func (b *ConfigBuilder) SharedNetwork(name string) *SharedNetworkBuilder {
+	for _, sn := range b.cfg.Dhcp6.SharedNetworks {
+		if sn.Name == name {
+			b.errorf("shared network %q is already defined", name)
+		}
+	}
+	b.cfg.Dhcp6.SharedNetworks = append(b.cfg.Dhcp6.SharedNetworks, SharedNetwork6{Name: name})
+	return &SharedNetworkBuilder{b: b, index: len(b.cfg.Dhcp6.SharedNetworks) - 1}
+}
+
+func (b *ConfigBuilder) addSubnet(network int, prefix string, id int) *SubnetBuilder {
+	p, err := netip.ParsePrefix(prefix)
+	if err != nil || !p.Addr().Is6() {
+		b.errorf("subnet %q: not an IPv6 prefix", prefix)
+	}
+	switch {
+	case id < 0:
+		b.errorf("subnet %s: invalid id %d", prefix, id)
+	case id == 0 && !b.autoIDs:
+		b.errorf("subnet %s: id is required unless AutoSubnetIDs is set", prefix)
+	case id > 0:
+		b.eachSubnet(func(s *Subnet6) {
+			if s.ID == id {
+				b.errorf("subnet %s: id %d is already used by subnet %s", prefix, id, s.Subnet)
+			}
+		})
+	}
+
+	list := &b.cfg.Dhcp6.Subnet6
+	if network >= 0 {
+		list = &b.cfg.Dhcp6.SharedNetworks[network].Subnet6
+	}
+	*list = append(*list, Subnet6{ID: id, Subnet: prefix})
+	return &SubnetBuilder{b: b, network: network, index: len(*list) - 1, prefix: p.Masked()}
+}
+
+// eachSubnet calls fn for every subnet, in shared networks first.
+func (b *ConfigBuilder) eachSubnet(fn func(*Subnet6)) {
+	for i := range b.cfg.Dhcp6.SharedNetworks {
+		for j := range b.cfg.Dhcp6.SharedNetworks[i].Subnet6 {
+			fn(&b.cfg.Dhcp6.SharedNetworks[i].Subnet6[j])
+		}
+	}
+	for i := range b.cfg.Dhcp6.Subnet6 {
+		fn(&b.cfg.Dhcp6.Subnet6[i])
+	}
+}
+
+// Err returns the mistakes found so far, joined, or nil.
+func (b *ConfigBuilder) Err() error {
+	return errors.Join(b.errs...)
+}
+
+// Build assigns any automatic subnet ids, validates the whole configuration with
+// Validate and returns it. Errors found along the way are returned joined; a
+// configuration that fails validation yields a *types.ValidationError.
+func (b *ConfigBuilder) Build() (Dhcp6Config, error) {
+	if err := b.Err(); err != nil {
+		return Dhcp6Config{}, err
+	}
+
+	used := make(map[int]bool)
+	b.eachSubnet(func(s *Subnet6) { used[s.ID] = true })
+	next := 1
+	b.eachSubnet(func(s *Subnet6) {
+		if s.ID != 0 {
+			return
+		}
+		for used[next] {
+			next++
+		}
+		s.ID, used[next] = next, true
+	})
+
+	if err := Validate(b.cfg.Dhcp6, types.ValidateOptions{}).Err(); err != nil {
+		return Dhcp6Config{}, err
+	}
+
+	// Round trip so the result shares nothing with the builder.
+	data, err := json.Marshal(b.cfg)
+	if err != nil {
+		return Dhcp6Config{}, err
+	}
+	var cfg Dhcp6Config
+	if err := json.Unmarshal(data, &cfg); err != nil {
+		return Dhcp6Config{}, err
+	}
+	return cfg, nil
+}
+
+// SharedNetworkBuilder adds to a shared network created by ConfigBuilder.SharedNetwork.
+type SharedNetworkBuilder struct {
+	b     *ConfigBuilder
+	index int
+}
+
+func (n *SharedNetworkBuilder) network() *SharedNetwork6 {
+	return &n.b.cfg.Dhcp6.SharedNetworks[n.index]
+}
+
+// Interface sets the interface the shared network is reached on.
+func (n *SharedNetworkBuilder) Interface(name string) *SharedNetworkBuilder {
+	n.network().Interface = name
+	return n
+}
+
+// Option adds an option for all subnets of the shared network.
+func (n *SharedNetworkBuilder) Option(name, data string) *SharedNetworkBuilder {
+	sn := n.network()
+	sn.OptionData = n.b.option(sn.OptionData, "shared network "+sn.Name, name, data)
+	return n
+}
+
+// Set applies fn to the shared network, for parameters the builder has no step for.
+func (n *SharedNetworkBuilder) Set(fn func(*SharedNetwork6)) *SharedNetworkBuilder {
+	fn(n.network())
+	return n
+}
+
+// Subnet adds a subnet to the shared network; see ConfigBuilder.Subnet.
+func (n *SharedNetworkBuilder) Subnet(prefix string, id int) *SubnetBuilder {
+	return n.b.addSubnet(n.index, prefix, id)
+}
+
+// Done returns to the configuration builder.
+func (n *SharedNetworkBuilder) Done() *ConfigBuilder {
+	return n.b
+}
+
+// Build is shorthand for Done().Build().
+func (n *SharedNetworkBuilder) Build() (Dhcp6Config, error) {
+	return n.b.Build()
+}
+
+// SubnetBuilder adds to a subnet created by ConfigBuilder.Subnet or SharedNetworkBuilder.Subnet.
+type SubnetBuilder struct {
+	b       *ConfigBuilder
+	network int // index into shared-networks, or -1 for the top-level subnet6 list
+	index   int
+	prefix  netip.Prefix
+}
+
+func (s *SubnetBuilder) subnet() *Subnet6 {
+	if s.network >= 0 {
+		return &s.b.cfg.Dhcp6.SharedNetworks[s.network].Subnet6[s.index]
+	}
+	return &s.b.cfg.Dhcp6.Subnet6[s.index]
+}
+
+// Pool adds an address pool given as "first - last" or in CIDR notation. It must lie
+// within the subnet and not overlap the subnet's other pools.
+func (s *SubnetBuilder) Pool(pool string) *SubnetBuilder {
+	sub := s.subnet()
+	first, last, err := types.ParsePool(pool)
+	switch {
+	case err != nil:
+		s.b.errorf("subnet %s: %v", sub.Subnet, err)
+	case s.prefix.IsValid() && (!s.prefix.Contains(first) || !s.prefix.Contains(last)):
+		s.b.errorf("subnet %s: pool %s is outside the subnet", sub.Subnet, pool)
+	default:
+		for _, p := range sub.Pools {
+			if f, l, err := p.Range(); err == nil && !l.Less(first) && !last.Less(f) {
+				s.b.errorf("subnet %s: pool %s overlaps pool %s", sub.Subnet, pool, p.Pool)
+			}
+		}
+	}
+	sub.Pools = append(sub.Pools, Pool6{Pool: pool})
+	return s
+}
+
+// Option adds an option for clients in the subnet.
+func (s *SubnetBuilder) Option(name, data string) *SubnetBuilder {
+	sub := s.subnet()
+	sub.OptionData = s.b.option(sub.OptionData, "subnet "+sub.Subnet, name, data)
+	return s
+}
+
+// PDPool adds a prefix delegation pool carving prefix/prefixLen into prefixes of
+// delegatedLen bits. It must not overlap the subnet's other pd-pools.
+func (s *SubnetBuilder) PDPool(prefix string, prefixLen, delegatedLen int) *SubnetBuilder {
+	sub := s.subnet()
+	pd := PDPool{Prefix: prefix, PrefixLen: prefixLen, DelegatedLen: delegatedLen}
+	network, err := pd.Network()
+	switch {
+	case err != nil || !network.Addr().Is6():
+		s.b.errorf("subnet %s: invalid pd-pool prefix %s/%d", sub.Subnet, prefix, prefixLen)
+	case delegatedLen < prefixLen || delegatedLen > 128:
+		s.b.errorf("subnet %s: pd-pool %s/%d cannot delegate /%d prefixes", sub.Subnet, prefix, prefixLen, delegatedLen)
+	default:
+		for _, p := range sub.PDPools {
+			if other, err := p.Network(); err == nil && other.Overlaps(network) {
+				s.b.errorf("subnet %s: pd-pool %s overlaps pd-pool %s", sub.Subnet, network, other)
+			}
+		}
+	}
+	sub.PDPools = append(sub.PDPools, pd)
+	return s
+}
+
+// Reservation reserves addresses for the client with duid. Entries with a prefix
+// length are delegated prefixes; plain addresses must lie within the subnet.
+func (s *SubnetBuilder) Reservation(duid string, addresses ...string) *SubnetBuilder {
+	sub := s.subnet()
+	r := Reservation6{DUID: duid}
+	for _, a := range addresses {
+		if strings.Contains(a, "/") {
+			if p, err := netip.ParsePrefix(a); err != nil || !p.Addr().Is6() {
+				s.b.errorf("subnet %s: invalid reserved prefix %q", sub.Subnet, a)
+			}
+			r.Prefixes = append(r.Prefixes, a)
+			continue
+		}
+		addr, err := netip.ParseAddr(a)
+		switch {
+		case err != nil || !addr.Is6():
+			s.b.errorf("subnet %s: invalid reserved address %q", sub.Subnet, a)
+		case s.prefix.IsValid() && !s.prefix.Contains(addr):
+			s.b.errorf("subnet %s: reserved address %s is outside the subnet", sub.Subnet, a)
+		}
+		r.IPAddresses = append(r.IPAddresses, a)
+	}
+	for _, other := range sub.Reservations {
+		if other.DUID == duid {
+			s.b.errorf("subnet %s: duid %s already has a reservation", sub.Subnet, duid)
+		}
+	}
+	sub.Reservations = append(sub.Reservations, r)
+	return s
+}
+
+// PreferredLifetime sets the subnet's preferred lifetime in seconds.
+func (s *SubnetBuilder) PreferredLifetime(seconds int) *SubnetBuilder {
+	s.subnet().PreferredLifetime = seconds
+	return s
+}
+
+// ValidLifetime sets the subnet's lease lifetime in seconds.
+func (s *SubnetBuilder) ValidLifetime(seconds int) *SubnetBuilder {
+	s.subnet().ValidLifetime = seconds
+	return s
+}
+
+// Timers sets the subnet's renew (T1) and rebind (T2) timers in seconds.
+func (s *SubnetBuilder) Timers(renew, rebind int) *SubnetBuilder {
+	sub := s.subnet()
+	if renew > rebind {
+		s.b.errorf("subnet %s: renew-timer %d exceeds rebind-timer %d", sub.Subnet, renew, rebind)
+	}
+	sub.RenewTimer, sub.RebindTimer = renew, rebind
+	return s
+}
+
+// Set applies fn to the subnet, for parameters the builder has no step for.
+func (s *SubnetBuilder) Set(fn func(*Subnet6)) *SubnetBuilder {
+	fn(s.subnet())
+	return s
+}
+
+// Subnet adds another subnet next to this one, in the same shared network if any.
+func (s *SubnetBuilder) Subnet(prefix string, id int) *SubnetBuilder {
+	return s.b.addSubnet(s.network, prefix, id)
+}
+
+// Done returns to the configuration builder.
+func (s *SubnetBuilder) Done() *ConfigBuilder {
+	return s.b
+}
+
+// Build is shorthand for Done().Build().
+func (s *SubnetBuilder) Build() (Dhcp6Config, error) {
+	return s.b.Build()
+}
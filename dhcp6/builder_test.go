@@ -0,0 +1,74 @@
+package dhcp6
+
+import (
+	"encoding/json"
+	"strings"
+	"testing"
+
+	"github.com/rannday/kea-api/internal/testenv"
+)
+
+// TestConfigBuilder emits only the parameters it was given, including pd-pools and prefix reservations.
+func TestConfigBuilder(t *testing.T) {
+	t.Parallel()
+
+	cfg, err := NewConfig().AutoSubnetIDs().
+		Interfaces("eth0").
+		PreferredLifetime(3000).
+		ValidLifetime(4000).
+		Subnet("2001:db8:1::/64", 0).
+		Pool("2001:db8:1::10 - 2001:db8:1::ffff").
+		PDPool("2001:db8:8::", 48, 56).
+		Option("dns-servers", "2001:db8::53").
+		Reservation("01:02:03:04:05:06", "2001:db8:1::5", "2001:db8:9::/56").
+		Build()
+	if err != nil {
+		t.Fatalf("Build() error = %v", err)
+	}
+
+	got, err := json.Marshal(cfg)
+	if err != nil {
+		t.Fatal(err)
+	}
+	testenv.ExpectJSONEquivalent(t, got, []byte(`{"Dhcp6": {
+		"interfaces-config": {"interfaces": ["eth0"]},
+		"preferred-lifetime": 3000,
+		"valid-lifetime": 4000,
+		"subnet6": [{
+			"id": 1,
+			"subnet": "2001:db8:1::/64",
+			"pools": [{"pool": "2001:db8:1::10 - 2001:db8:1::ffff"}],
+			"pd-pools": [{"prefix": "2001:db8:8::", "prefix-len": 48, "delegated-len": 56}],
+			"option-data": [{"name": "dns-servers", "data": "2001:db8::53"}],
+			"reservations": [{"duid": "01:02:03:04:05:06", "ip-addresses": ["2001:db8:1::5"], "prefixes": ["2001:db8:9::/56"]}]
+		}]
+	}}`))
+}
+
+// TestConfigBuilderErrors collects mistakes from each step.
+func TestConfigBuilderErrors(t *testing.T) {
+	t.Parallel()
+
+	_, err := NewConfig().
+		Subnet("2001:db8:1::/64", 1).
+		PDPool("2001:db8:8::", 48, 56).
+		PDPool("2001:db8:8:100::", 56, 64).
+		PDPool("2001:db8:9::", 48, 40).
+		Reservation("01:02", "2001:db8:2::1", "bogus/64").
+		Subnet("192.0.2.0/24", 2).
+		Build()
+	if err == nil {
+		t.Fatal("Build() error = nil")
+	}
+	for _, want := range []string{
+		"pd-pool 2001:db8:8:100::/56 overlaps pd-pool 2001:db8:8::/48",
+		"pd-pool 2001:db8:9::/48 cannot delegate /40 prefixes",
+		"reserved address 2001:db8:2::1 is outside the subnet",
+		`invalid reserved prefix "bogus/64"`,
+		`subnet "192.0.2.0/24": not an IPv6 prefix`,
+	} {
+		if !strings.Contains(err.Error(), want) {
+			t.Errorf("Build() error does not mention %q:\n%v", want, err)
+		}
+	}
+}
@@ -0,0 +1,83 @@
+package dhcp6
+
+import (
+	"context"
+
+	"github.com/rannday/kea-api/client"
+	"github.com/rannday/kea-api/configfile"
+	"github.com/rannday/kea-api/types"
+)
+
+/*
+ * Configuration changes. A Dhcp6Config read with ConfigGet carries the hash ConfigApply
+ * checks before replacing the running configuration.
+ */
+
+// ConfigHashGet fetches the hash of the running DHCPv6 configuration.
+func ConfigHashGet(c *client.Client) (string, error) {
+	return ConfigHashGetContext(context.Background(), c)
+}
+
+// ConfigHashGetContext is like ConfigHashGet but honours ctx.
+func ConfigHashGetContext(ctx context.Context, c *client.Client) (string, error) {
+	return client.ConfigHashGetContext(ctx, c, client.Services.DHCP6)
+}
+
+// ConfigTest validates cfg on the DHCPv6 server without applying it.
+func ConfigTest(c *client.Client, cfg Dhcp6Config) (string, error) {
+	return ConfigTestContext(context.Background(), c, cfg)
+}
+
+// ConfigTestContext is like ConfigTest but honours ctx.
+func ConfigTestContext(ctx context.Context, c *client.Client, cfg Dhcp6Config) (string, error) {
+	return client.ConfigTestContext(ctx, c, client.Services.DHCP6, cfg)
+}
+
+// ConfigSet replaces the running DHCPv6 configuration with cfg, without checking its hash.
+func ConfigSet(c *client.Client, cfg Dhcp6Config) (string, error) {
+	return ConfigSetContext(context.Background(), c, cfg)
+}
+
+// ConfigSetContext is like ConfigSet but honours ctx.
+func ConfigSetContext(ctx context.Context, c *client.Client, cfg Dhcp6Config) (string, error) {
+	return client.ConfigSetContext(ctx, c, client.Services.DHCP6, cfg)
+}
+
+// ConfigWrite saves the running DHCPv6 configuration to filename, or to the file it was loaded from.
+func ConfigWrite(c *client.Client, filename string) (types.ConfigWriteResult, error) {
+	return ConfigWriteContext(context.Background(), c, filename)
+}
+
+// ConfigWriteContext is like ConfigWrite but honours ctx.
+func ConfigWriteContext(ctx context.Context, c *client.Client, filename string) (types.ConfigWriteResult, error) {
+	return client.ConfigWriteContext(ctx, c, client.Services.DHCP6, filename)
+}
+
+// ConfigReload makes the DHCPv6 server re-read its configuration file.
+func ConfigReload(c *client.Client) (string, error) {
+	return ConfigReloadContext(context.Background(), c)
+}
+
+// ConfigReloadContext is like ConfigReload but honours ctx.
+func ConfigReloadContext(ctx context.Context, c *client.Client) (string, error) {
+	return client.ConfigReloadContext(ctx, c, client.Services.DHCP6)
+}
+
+// ConfigApply checks that the server still runs the configuration cfg was read from,
+// then tests, sets and optionally writes cfg. See client.ConfigApply.
+func ConfigApply(c *client.Client, cfg Dhcp6Config, opts client.ConfigApplyOptions) (client.ConfigApplyReport, error) {
+	return ConfigApplyContext(context.Background(), c, cfg, opts)
+}
+
+// ConfigApplyContext is like ConfigApply but honours ctx.
+func ConfigApplyContext(ctx context.Context, c *client.Client, cfg Dhcp6Config, opts client.ConfigApplyOptions) (client.ConfigApplyReport, error) {
+	return client.ConfigApplyContext(ctx, c, client.Services.DHCP6, cfg, opts)
+}
+
+// LoadConfigFile reads a DHCPv6 configuration file such as kea-dhcp6.conf, expanding comments
+// and include directives. Errors are reported as file:line:col.
+func LoadConfigFile(path string) (Dhcp6Config, error) {
+	var cfg Dhcp6Config
+	_, err := configfile.Load(path, &cfg)
+	return cfg, err
+}
@@ -0,0 +1,63 @@
+package dhcp6
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/rannday/kea-api/client"
+	"github.com/rannday/kea-api/internal/testenv"
+)
+
+// TestConfigApply checks the hash, then tests and sets the configuration without its hash.
+func TestConfigApply(t *testing.T) {
+	t.Parallel()
+
+	var cfg Dhcp6Config
+	if err := json.Unmarshal([]byte(`{"Dhcp6": {"preferred-lifetime": 3000, "subnet6": [{"id": 1, "subnet": "2001:db8:1::/64"}]}, "hash": "ABC123"}`), &cfg); err != nil {
+		t.Fatalf("failed to decode test config: %v", err)
+	}
+	sent := map[string]any{"Dhcp6": json.RawMessage(`{"preferred-lifetime": 3000, "subnet6": [{"id": 1, "subnet": "2001:db8:1::/64"}]}`)}
+
+	mockClient := testenv.NewSequenceMockClient(t,
+		testenv.MockStep{
+			Validate:  testenv.ExpectCommandArgs(t, "config-hash-get", nil, client.Services.DHCP6),
+			Responses: []client.CommandResponse{{Result: client.ResultSuccess, Arguments: json.RawMessage(`{"hash": "ABC123"}`)}},
+		},
+		testenv.MockStep{
+			Validate:  testenv.ExpectCommandArgs(t, "config-test", sent, client.Services.DHCP6),
+			Responses: []client.CommandResponse{{Result: client.ResultSuccess}},
+		},
+		testenv.MockStep{
+			Validate:  testenv.ExpectCommandArgs(t, "config-set", sent, client.Services.DHCP6),
+			Responses: []client.CommandResponse{{Result: client.ResultSuccess, Text: "Configuration applied successfully."}},
+		},
+	)
+
+	report, err := ConfigApply(mockClient, cfg, client.ConfigApplyOptions{})
+	if err != nil {
+		t.Fatalf("ConfigApply() error = %v", err)
+	}
+	if !report.Applied || report.Written != nil || len(report.Steps) != 3 {
+		t.Errorf("ConfigApply() report = %+v", report)
+	}
+}
+
+// TestLoadConfigFile reads a commented kea-dhcp6.conf into the typed configuration.
+func TestLoadConfigFile(t *testing.T) {
+	t.Parallel()
+
+	cfg, err := LoadConfigFile("testdata/kea-dhcp6.conf")
+	if err != nil {
+		t.Fatalf("LoadConfigFile() error = %v", err)
+	}
+	d := cfg.Dhcp6
+	if d.PreferredLifetime != 3000 || d.ValidLifetime != 4000 {
+		t.Errorf("LoadConfigFile() lifetimes = %d, %d", d.PreferredLifetime, d.ValidLifetime)
+	}
+	if len(d.Subnet6) != 1 || len(d.Subnet6[0].Pools) != 1 || len(d.Subnet6[0].PDPools) != 1 {
+		t.Fatalf("LoadConfigFile() subnets = %+v", d.Subnet6)
+	}
+	if pd := d.Subnet6[0].PDPools[0]; pd.Prefix != "2001:db8:8::" || pd.DelegatedLen != 56 {
+		t.Errorf("LoadConfigFile() pd-pool = %+v", pd)
+	}
+}
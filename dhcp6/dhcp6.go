@@ -1,30 +1,57 @@
 package dhcp6
 
 import (
+	"context"
+
 	"github.com/rannday/kea-api/client"
 )
 
 // BuildReport fetches the build configuration report of the DHCPv6 server.
 func BuildReport(c *client.Client) (string, error) {
-	return client.BuildReport(c, client.Services.DHCP6)
+	return BuildReportContext(context.Background(), c)
+}
+
+// BuildReportContext is like BuildReport but honours ctx.
+func BuildReportContext(ctx context.Context, c *client.Client) (string, error) {
+	return client.BuildReportContext(ctx, c, client.Services.DHCP6)
 }
 
 // ConfigGet fetches the config from the DHCPv6 service.
 func ConfigGet(c *client.Client) (Dhcp6Config, error) {
-	return client.ConfigGet[Dhcp6Config](c, "dhcp6")
+	return ConfigGetContext(context.Background(), c)
+}
+
+// ConfigGetContext is like ConfigGet but honours ctx.
+func ConfigGetContext(ctx context.Context, c *client.Client) (Dhcp6Config, error) {
+	return client.ConfigGetContext[Dhcp6Config](ctx, c, client.Services.DHCP6)
 }
 
 // StatusGet fetches the DHCPv6 server status using the generic helper.
 func StatusGet(c *client.Client) (DHCP6Status, error) {
-	return client.StatusGet[DHCP6Status](c, client.Services.DHCP6)
+	return StatusGetContext(context.Background(), c)
+}
+
+// StatusGetContext is like StatusGet but honours ctx.
+func StatusGetContext(ctx context.Context, c *client.Client) (DHCP6Status, error) {
+	return client.StatusGetContext[DHCP6Status](ctx, c, client.Services.DHCP6)
 }
 
 // ListCommands fetches the list of commands for DHCPv6.
 func ListCommands(c *client.Client) ([]string, error) {
-	return client.ListCommands(c, client.Services.DHCP6)
+	return ListCommandsContext(context.Background(), c)
+}
+
+// ListCommandsContext is like ListCommands but honours ctx.
+func ListCommandsContext(ctx context.Context, c *client.Client) ([]string, error) {
+	return client.ListCommandsContext(ctx, c, client.Services.DHCP6)
 }
 
 // VersionGet fetches the DHCPv6 server version using the shared helper.
 func VersionGet(c *client.Client) (string, DHCP6Version, error) {
-	return client.VersionGet[DHCP6Version](c, client.Services.DHCP6)
+	return VersionGetContext(context.Background(), c)
+}
+
+// VersionGetContext is like VersionGet but honours ctx.
+func VersionGetContext(ctx context.Context, c *client.Client) (string, DHCP6Version, error) {
+	return client.VersionGetContext[DHCP6Version](ctx, c, client.Services.DHCP6)
 }
@@ -1,6 +1,8 @@
 package dhcp6
 
 import (
+	"encoding/json"
+	"os"
 	"reflect"
 	"testing"
 
@@ -66,10 +68,10 @@ func TestConfigGet(t *testing.T) {
 				ReDetect:   false,
 			},
 			ServerTag:      "v6-default",
-			Subnet6:        []interface{}{},
-			OptionData:     []interface{}{},
-			OptionDef:      []interface{}{},
-			SharedNetworks: []interface{}{},
+			Subnet6:        []Subnet6{},
+			OptionData:     []types.OptionData{},
+			OptionDef:      []types.OptionDef{},
+			SharedNetworks: []SharedNetwork6{},
 			HostsDatabases: []types.DatabaseConfig{},
 		},
 		Hash: "deadbeefcafefeed1234567890abcdef",
@@ -93,6 +95,106 @@ func TestConfigGet(t *testing.T) {
 	}
 }
 
+// TestConfigGetSubnets decodes subnets, address and prefix pools, and shared networks from a Kea config.
+func TestConfigGetSubnets(t *testing.T) {
+	t.Parallel()
+
+	mockClient := testenv.NewMockClient(t,
+		testenv.ExpectCommand(t, "config-get", client.Services.DHCP6),
+		[]client.CommandResponse{{
+			Result: client.ResultSuccess,
+			Arguments: json.RawMessage(`{"Dhcp6": {
+				"option-def": [{"name": "foo", "code": 222, "type": "record", "record-types": "uint16, ipv6-address", "space": "dhcp6"}],
+				"option-data": [{"name": "dns-servers", "data": "2001:db8::1"}],
+				"subnet6": [{
+					"id": 1,
+					"subnet": "2001:db8:1::/64",
+					"interface-id": "eth0-relay",
+					"pools": [{"pool": "2001:db8:1::1000 - 2001:db8:1::1fff", "evaluate-additional-classes": ["late"]}],
+					"pd-pools": [{"prefix": "2001:db8:8::", "prefix-len": 56, "delegated-len": 64, "client-class": "routers"}],
+					"relay": {"ip-addresses": ["2001:db8:ffff::1"]},
+					"rapid-commit": true,
+					"reservations": [{"duid": "01:02:03:04", "ip-addresses": ["2001:db8:1::100"], "prefixes": ["2001:db8:2:abcd::/64"]}]
+				}],
+				"shared-networks": [{"name": "lab", "subnet6": [{"id": 2, "subnet": "2001:db8:2::/64"}]}]
+			}, "hash": "abc"}`),
+		}},
+	)
+
+	got, err := ConfigGet(mockClient)
+	if err != nil {
+		t.Fatalf("ConfigGet() error = %v", err)
+	}
+	cfg := got.Dhcp6
+
+	if len(cfg.OptionDef) != 1 || cfg.OptionDef[0].RecordTypes != "uint16, ipv6-address" {
+		t.Errorf("OptionDef = %+v", cfg.OptionDef)
+	}
+	if len(cfg.OptionData) != 1 || cfg.OptionData[0].Name != "dns-servers" {
+		t.Errorf("OptionData = %+v", cfg.OptionData)
+	}
+
+	if len(cfg.Subnet6) != 1 {
+		t.Fatalf("Subnet6 = %+v", cfg.Subnet6)
+	}
+	subnet := cfg.Subnet6[0]
+	if subnet.Relay == nil || subnet.InterfaceID != "eth0-relay" || subnet.RapidCommit == nil || !*subnet.RapidCommit {
+		t.Errorf("Subnet6[0] = %+v", subnet)
+	}
+	if len(subnet.Reservations) != 1 || !reflect.DeepEqual(subnet.Reservations[0].Prefixes, []string{"2001:db8:2:abcd::/64"}) {
+		t.Errorf("Reservations = %+v", subnet.Reservations)
+	}
+	if len(subnet.Pools) != 1 || !reflect.DeepEqual(subnet.Pools[0].EvaluateAdditionalClasses, []string{"late"}) {
+		t.Fatalf("Pools = %+v", subnet.Pools)
+	}
+	first, last, err := subnet.Pools[0].Range()
+	if err != nil || first.String() != "2001:db8:1::1000" || last.String() != "2001:db8:1::1fff" {
+		t.Errorf("Pools[0].Range() = %v, %v, %v", first, last, err)
+	}
+	if len(subnet.PDPools) != 1 || subnet.PDPools[0].ClientClass != "routers" {
+		t.Fatalf("PDPools = %+v", subnet.PDPools)
+	}
+	if prefix, err := subnet.PDPools[0].Network(); err != nil || prefix.String() != "2001:db8:8::/56" {
+		t.Errorf("PDPools[0].Network() = %v, %v", prefix, err)
+	}
+
+	if len(cfg.SharedNetworks) != 1 || cfg.SharedNetworks[0].Name != "lab" || len(cfg.SharedNetworks[0].Subnet6) != 1 {
+		t.Errorf("SharedNetworks = %+v", cfg.SharedNetworks)
+	}
+}
+
+// TestConfigGetRoundTrip re-encodes a config-get reply and checks that nothing was added or lost.
+func TestConfigGetRoundTrip(t *testing.T) {
+	t.Parallel()
+
+	raw, err := os.ReadFile("testdata/config-get.json")
+	if err != nil {
+		t.Fatalf("failed to read fixture: %v", err)
+	}
+	mockClient := testenv.NewMockClient(t,
+		testenv.ExpectCommand(t, "config-get", client.Services.DHCP6),
+		[]client.CommandResponse{{Result: client.ResultSuccess, Arguments: raw}},
+	)
+
+	cfg, err := ConfigGet(mockClient)
+	if err != nil {
+		t.Fatalf("ConfigGet() error = %v", err)
+	}
+	out, err := json.Marshal(cfg)
+	if err != nil {
+		t.Fatalf("Marshal() error = %v", err)
+	}
+	testenv.ExpectJSONEquivalent(t, out, raw)
+
+	subnet := cfg.Dhcp6.Subnet6[0]
+	if _, ok := subnet.Extra["pd-allocator"]; !ok {
+		t.Errorf("Subnet6[0].Extra = %v, want pd-allocator", subnet.Extra)
+	}
+	if _, ok := subnet.PDPools[0].Extra["excluded-prefix-len"]; ok {
+		t.Errorf("PDPools[0].Extra = %v, want the modelled excluded-prefix-len left out", subnet.PDPools[0].Extra)
+	}
+}
+
 // TestStatusGet tests the StatusGet function for the CtrlDHCP6 type.
 func TestStatusGet(t *testing.T) {
 	t.Parallel()
@@ -0,0 +1,10 @@
+package dhcp6
+
+import "github.com/rannday/kea-api/types"
+
+// Diff lists the changes that turn from into to, e.g. to review an edit before ConfigApply.
+// Subnets are matched by id, pools by range or prefix, reservations by identifier and options by
+// space and code; the hash is ignored.
+func Diff(from, to Dhcp6Config) (types.ConfigDiff, error) {
+	return types.DiffConfig(from, to)
+}
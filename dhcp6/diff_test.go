@@ -0,0 +1,34 @@
+package dhcp6
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/rannday/kea-api/types"
+)
+
+// TestDiff matches prefix delegation pools by prefix.
+func TestDiff(t *testing.T) {
+	t.Parallel()
+
+	raw := []byte(`{"Dhcp6": {"subnet6": [{"id": 1, "subnet": "2001:db8:1::/64",
+		"pd-pools": [{"prefix": "2001:db8:8::", "prefix-len": 56, "delegated-len": 64}]}]}}`)
+	var from, to Dhcp6Config
+	if err := json.Unmarshal(raw, &from); err != nil {
+		t.Fatal(err)
+	}
+	if err := json.Unmarshal(raw, &to); err != nil {
+		t.Fatal(err)
+	}
+	to.Dhcp6.Subnet6[0].PDPools[0].DelegatedLen = 60
+
+	diff, err := Diff(from, to)
+	if err != nil {
+		t.Fatalf("Diff() error = %v", err)
+	}
+	if len(diff) != 1 || diff[0].Kind != types.KindParameter ||
+		diff[0].Path != "Dhcp6.subnet6[id=1].pd-pools[2001:db8:8::/56].delegated-len" ||
+		diff[0].Pointer != "/Dhcp6/subnet6/0/pd-pools/0/delegated-len" {
+		t.Errorf("Diff() = %+v", diff)
+	}
+}
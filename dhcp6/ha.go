@@ -0,0 +1,114 @@
+package dhcp6
+
+import (
+	"context"
+	"time"
+
+	"github.com/rannday/kea-api/client"
+	"github.com/rannday/kea-api/types"
+)
+
+/*
+ * High Availability commands of the ha hook library. serverName selects the
+ * relationship on servers configured in hub-and-spoke mode and may be empty otherwise.
+ */
+
+// HAStatusGet fetches the HA relationships reported by status-get.
+func HAStatusGet(c *client.Client) ([]types.HAStatus, error) {
+	return HAStatusGetContext(context.Background(), c)
+}
+
+// HAStatusGetContext is like HAStatusGet but honours ctx.
+func HAStatusGetContext(ctx context.Context, c *client.Client) ([]types.HAStatus, error) {
+	return client.HAStatusGetContext(ctx, c, client.Services.DHCP6)
+}
+
+// HAHeartbeat fetches the HA state of the server.
+func HAHeartbeat(c *client.Client, serverName string) (types.HAHeartbeat, error) {
+	return HAHeartbeatContext(context.Background(), c, serverName)
+}
+
+// HAHeartbeatContext is like HAHeartbeat but honours ctx.
+func HAHeartbeatContext(ctx context.Context, c *client.Client, serverName string) (types.HAHeartbeat, error) {
+	return client.HAHeartbeatContext(ctx, c, client.Services.DHCP6, serverName)
+}
+
+// HAScopes sets the scopes the server serves.
+func HAScopes(c *client.Client, scopes []string, serverName string) error {
+	return HAScopesContext(context.Background(), c, scopes, serverName)
+}
+
+// HAScopesContext is like HAScopes but honours ctx.
+func HAScopesContext(ctx context.Context, c *client.Client, scopes []string, serverName string) error {
+	return client.HAScopesContext(ctx, c, client.Services.DHCP6, scopes, serverName)
+}
+
+// HASync makes the server fetch the leases of its partner serverName.
+func HASync(c *client.Client, serverName string, maxPeriod time.Duration) error {
+	return HASyncContext(context.Background(), c, serverName, maxPeriod)
+}
+
+// HASyncContext is like HASync but honours ctx.
+func HASyncContext(ctx context.Context, c *client.Client, serverName string, maxPeriod time.Duration) error {
+	return client.HASyncContext(ctx, c, client.Services.DHCP6, serverName, maxPeriod)
+}
+
+// HASyncCompleteNotify tells the server that its partner finished synchronizing leases.
+func HASyncCompleteNotify(c *client.Client, serverName string) error {
+	return HASyncCompleteNotifyContext(context.Background(), c, serverName)
+}
+
+// HASyncCompleteNotifyContext is like HASyncCompleteNotify but honours ctx.
+func HASyncCompleteNotifyContext(ctx context.Context, c *client.Client, serverName string) error {
+	return client.HASyncCompleteNotifyContext(ctx, c, client.Services.DHCP6, serverName)
+}
+
+// HAContinue resumes the paused HA state machine of the server.
+func HAContinue(c *client.Client, serverName string) error {
+	return HAContinueContext(context.Background(), c, serverName)
+}
+
+// HAContinueContext is like HAContinue but honours ctx.
+func HAContinueContext(ctx context.Context, c *client.Client, serverName string) error {
+	return client.HAContinueContext(ctx, c, client.Services.DHCP6, serverName)
+}
+
+// HAMaintenanceStart puts the partner of the server in maintenance.
+func HAMaintenanceStart(c *client.Client, serverName string) error {
+	return HAMaintenanceStartContext(context.Background(), c, serverName)
+}
+
+// HAMaintenanceStartContext is like HAMaintenanceStart but honours ctx.
+func HAMaintenanceStartContext(ctx context.Context, c *client.Client, serverName string) error {
+	return client.HAMaintenanceStartContext(ctx, c, client.Services.DHCP6, serverName)
+}
+
+// HAMaintenanceNotify tells the server that its partner is entering or, with cancel, leaving maintenance.
+func HAMaintenanceNotify(c *client.Client, cancel bool, serverName string) error {
+	return HAMaintenanceNotifyContext(context.Background(), c, cancel, serverName)
+}
+
+// HAMaintenanceNotifyContext is like HAMaintenanceNotify but honours ctx.
+func HAMaintenanceNotifyContext(ctx context.Context, c *client.Client, cancel bool, serverName string) error {
+	return client.HAMaintenanceNotifyContext(ctx, c, client.Services.DHCP6, cancel, serverName)
+}
+
+// HAMaintenanceCancel takes the partner of the server out of maintenance.
+func HAMaintenanceCancel(c *client.Client, serverName string) error {
+	return HAMaintenanceCancelContext(context.Background(), c, serverName)
+}
+
+// HAMaintenanceCancelContext is like HAMaintenanceCancel but honours ctx.
+func HAMaintenanceCancelContext(ctx context.Context, c *client.Client, serverName string) error {
+	return client.HAMaintenanceCancelContext(ctx, c, client.Services.DHCP6, serverName)
+}
+
+// HAReset moves the server to the waiting state.
+func HAReset(c *client.Client, serverName string) error {
+	return HAResetContext(context.Background(), c, serverName)
+}
+
+// HAResetContext is like HAReset but honours ctx.
+func HAResetContext(ctx context.Context, c *client.Client, serverName string) error {
+	return client.HAResetContext(ctx, c, client.Services.DHCP6, serverName)
+}
@@ -0,0 +1,72 @@
+package dhcp6
+
+import (
+	"encoding/json"
+	"testing"
+	"time"
+
+	"github.com/rannday/kea-api/client"
+	"github.com/rannday/kea-api/internal/testenv"
+	"github.com/rannday/kea-api/types"
+)
+
+// TestHAStatusGet decodes the HA relationships of a hot-standby standby server.
+func TestHAStatusGet(t *testing.T) {
+	t.Parallel()
+
+	mockClient := testenv.NewMockClient(t,
+		testenv.ExpectCommand(t, "status-get", client.Services.DHCP6),
+		[]client.CommandResponse{{
+			Result: client.ResultSuccess,
+			Arguments: json.RawMessage(`{"pid": 7, "high-availability": [{
+				"ha-mode": "hot-standby",
+				"ha-servers": {
+					"local": {"role": "standby", "scopes": [], "state": "hot-standby", "server-name": "server2"},
+					"remote": {"role": "primary", "last-state": "hot-standby", "last-scopes": ["server1"], "in-touch": true, "server-name": "server1"}
+				}
+			}]}`),
+		}},
+	)
+
+	got, err := HAStatusGet(mockClient)
+	if err != nil {
+		t.Fatalf("HAStatusGet() error = %v", err)
+	}
+	if len(got) != 1 || got[0].Mode != "hot-standby" || got[0].Servers.Local.Role != types.HARoleStandby ||
+		got[0].Servers.Remote.LastState != types.HAStateHotStandby || got[0].Servers.Remote.ServerName != "server1" {
+		t.Errorf("HAStatusGet() = %+v", got)
+	}
+}
+
+// TestHACommands checks that the HA commands are sent to the DHCPv6 server.
+func TestHACommands(t *testing.T) {
+	t.Parallel()
+
+	ok := []client.CommandResponse{{Result: client.ResultSuccess}}
+	mockClient := testenv.NewSequenceMockClient(t,
+		testenv.MockStep{
+			Validate: testenv.ExpectCommandArgs(t, "ha-heartbeat", map[string]any{"server-name": "server1"}, client.Services.DHCP6),
+			Responses: []client.CommandResponse{{
+				Result:    client.ResultSuccess,
+				Arguments: json.RawMessage(`{"state": "hot-standby", "date-time": "Tue, 11 Jun 2024 09:12:44 GMT", "scopes": ["server1"], "unsent-update-count": 0}`),
+			}},
+		},
+		testenv.MockStep{Validate: testenv.ExpectCommandArgs(t, "ha-sync", map[string]any{"server-name": "server1"}, client.Services.DHCP6), Responses: ok},
+		testenv.MockStep{Validate: testenv.ExpectCommandArgs(t, "ha-maintenance-start", nil, client.Services.DHCP6), Responses: ok},
+		testenv.MockStep{Validate: testenv.ExpectCommandArgs(t, "ha-maintenance-cancel", nil, client.Services.DHCP6), Responses: ok},
+	)
+
+	hb, err := HAHeartbeat(mockClient, "server1")
+	if err != nil || hb.State != types.HAStateHotStandby {
+		t.Errorf("HAHeartbeat() = %+v, %v", hb, err)
+	}
+	if err := HASync(mockClient, "server1", time.Duration(0)); err != nil {
+		t.Errorf("HASync() error = %v", err)
+	}
+	if err := HAMaintenanceStart(mockClient, ""); err != nil {
+		t.Errorf("HAMaintenanceStart() error = %v", err)
+	}
+	if err := HAMaintenanceCancel(mockClient, ""); err != nil {
+		t.Errorf("HAMaintenanceCancel() error = %v", err)
+	}
+}
@@ -0,0 +1,115 @@
+package dhcp6
+
+import "github.com/rannday/kea-api/internal/utils"
+
+// JSON codecs keeping the config types of this package lossless, Extra included; see utils.DecodeObject.
+
+func (d *Dhcp6Config) UnmarshalJSON(data []byte) error {
+	type plain Dhcp6Config
+	return utils.DecodeObject(data, (*plain)(d), &d.Extra, &d.absent)
+}
+
+func (d Dhcp6Config) MarshalJSON() ([]byte, error) {
+	type plain Dhcp6Config
+	return utils.EncodeObject(plain(d), d.Extra, d.absent)
+}
+
+func (d *Dhcp6Block) UnmarshalJSON(data []byte) error {
+	type plain Dhcp6Block
+	return utils.DecodeObject(data, (*plain)(d), &d.Extra, &d.absent)
+}
+
+func (d Dhcp6Block) MarshalJSON() ([]byte, error) {
+	type plain Dhcp6Block
+	return utils.EncodeObject(plain(d), d.Extra, d.absent)
+}
+
+func (d *DhcpDDNSConfig) UnmarshalJSON(data []byte) error {
+	type plain DhcpDDNSConfig
+	return utils.DecodeObject(data, (*plain)(d), &d.Extra, &d.absent)
+}
+
+func (d DhcpDDNSConfig) MarshalJSON() ([]byte, error) {
+	type plain DhcpDDNSConfig
+	return utils.EncodeObject(plain(d), d.Extra, d.absent)
+}
+
+func (d *DhcpQueueControl) UnmarshalJSON(data []byte) error {
+	type plain DhcpQueueControl
+	return utils.DecodeObject(data, (*plain)(d), &d.Extra, &d.absent)
+}
+
+func (d DhcpQueueControl) MarshalJSON() ([]byte, error) {
+	type plain DhcpQueueControl
+	return utils.EncodeObject(plain(d), d.Extra, d.absent)
+}
+
+func (e *ExpiredLeasesProcessing) UnmarshalJSON(data []byte) error {
+	type plain ExpiredLeasesProcessing
+	return utils.DecodeObject(data, (*plain)(e), &e.Extra, &e.absent)
+}
+
+func (e ExpiredLeasesProcessing) MarshalJSON() ([]byte, error) {
+	type plain ExpiredLeasesProcessing
+	return utils.EncodeObject(plain(e), e.Extra, e.absent)
+}
+
+func (s *ServerID) UnmarshalJSON(data []byte) error {
+	type plain ServerID
+	return utils.DecodeObject(data, (*plain)(s), &s.Extra, &s.absent)
+}
+
+func (s ServerID) MarshalJSON() ([]byte, error) {
+	type plain ServerID
+	return utils.EncodeObject(plain(s), s.Extra, s.absent)
+}
+
+func (r *Reservation6) UnmarshalJSON(data []byte) error {
+	type plain Reservation6
+	return utils.DecodeObject(data, (*plain)(r), &r.Extra, &r.absent)
+}
+
+func (r Reservation6) MarshalJSON() ([]byte, error) {
+	type plain Reservation6
+	return utils.EncodeObject(plain(r), r.Extra, r.absent)
+}
+
+func (s *Subnet6) UnmarshalJSON(data []byte) error {
+	type plain Subnet6
+	return utils.DecodeObject(data, (*plain)(s), &s.Extra, &s.absent)
+}
+
+func (s Subnet6) MarshalJSON() ([]byte, error) {
+	type plain Subnet6
+	return utils.EncodeObject(plain(s), s.Extra, s.absent)
+}
+
+func (p *Pool6) UnmarshalJSON(data []byte) error {
+	type plain Pool6
+	return utils.DecodeObject(data, (*plain)(p), &p.Extra, &p.absent)
+}
+
+func (p Pool6) MarshalJSON() ([]byte, error) {
+	type plain Pool6
+	return utils.EncodeObject(plain(p), p.Extra, p.absent)
+}
+
+func (p *PDPool) UnmarshalJSON(data []byte) error {
+	type plain PDPool
+	return utils.DecodeObject(data, (*plain)(p), &p.Extra, &p.absent)
+}
+
+func (p PDPool) MarshalJSON() ([]byte, error) {
+	type plain PDPool
+	return utils.EncodeObject(plain(p), p.Extra, p.absent)
+}
+
+func (s *SharedNetwork6) UnmarshalJSON(data []byte) error {
+	type plain SharedNetwork6
+	return utils.DecodeObject(data, (*plain)(s), &s.Extra, &s.absent)
+}
+
+func (s SharedNetwork6) MarshalJSON() ([]byte, error) {
+	type plain SharedNetwork6
+	return utils.EncodeObject(plain(s), s.Extra, s.absent)
+}
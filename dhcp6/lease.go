@@ -0,0 +1,230 @@
+package dhcp6
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"iter"
+
+	"github.com/rannday/kea-api/client"
+	"github.com/rannday/kea-api/types"
+)
+
+/*
+ * Lease management via the lease_cmds hook library (libdhcp_lease_cmds.so).
+ * Commands that look up several leases answer with result 3 when nothing
+ * matches; the wrappers below report that as an empty slice. Single-lease
+ * lookups and deletions return the error instead, see client.IsNotFound.
+ */
+
+// Lease6Add creates a new address or prefix lease with lease6-add.
+func Lease6Add(c *client.Client, lease Lease6) error {
+	return Lease6AddContext(context.Background(), c, lease)
+}
+
+// Lease6AddContext is like Lease6Add but honours ctx.
+func Lease6AddContext(ctx context.Context, c *client.Client, lease Lease6) error {
+	_, err := client.CallWithArgsContext(ctx, c, "lease6-add", lease, client.Services.DHCP6)
+	return err
+}
+
+// Lease6Get fetches a single lease by address or prefix, or by DUID, IAID and subnet.
+func Lease6Get(c *client.Client, q Lease6Query) (Lease6, error) {
+	return Lease6GetContext(context.Background(), c, q)
+}
+
+// Lease6GetContext is like Lease6Get but honours ctx.
+func Lease6GetContext(ctx context.Context, c *client.Client, q Lease6Query) (Lease6, error) {
+	q.UpdateDDNS = false
+	args, err := q.arguments()
+	if err != nil {
+		return Lease6{}, err
+	}
+	return client.DecodeFirstWithArgsContext[Lease6](ctx, c, "lease6-get", args, client.Services.DHCP6)
+}
+
+// Lease6GetAll fetches every lease, or only those in the given subnets.
+func Lease6GetAll(c *client.Client, subnetIDs ...int) ([]Lease6, error) {
+	return Lease6GetAllContext(context.Background(), c, subnetIDs...)
+}
+
+// Lease6GetAllContext is like Lease6GetAll but honours ctx.
+func Lease6GetAllContext(ctx context.Context, c *client.Client, subnetIDs ...int) ([]Lease6, error) {
+	var args map[string]interface{}
+	if len(subnetIDs) > 0 {
+		args = map[string]interface{}{"subnets": subnetIDs}
+	}
+	return getLeases6(ctx, c, "lease6-get-all", args)
+}
+
+// Lease6GetByDUID fetches all address and prefix leases held by a DUID.
+func Lease6GetByDUID(c *client.Client, duid string) ([]Lease6, error) {
+	return Lease6GetByDUIDContext(context.Background(), c, duid)
+}
+
+// Lease6GetByDUIDContext is like Lease6GetByDUID but honours ctx.
+func Lease6GetByDUIDContext(ctx context.Context, c *client.Client, duid string) ([]Lease6, error) {
+	return getLeases6(ctx, c, "lease6-get-by-duid", map[string]interface{}{"duid": duid})
+}
+
+// Lease6GetByHostname fetches all leases with the given hostname.
+func Lease6GetByHostname(c *client.Client, hostname string) ([]Lease6, error) {
+	return Lease6GetByHostnameContext(context.Background(), c, hostname)
+}
+
+// Lease6GetByHostnameContext is like Lease6GetByHostname but honours ctx.
+func Lease6GetByHostnameContext(ctx context.Context, c *client.Client, hostname string) ([]Lease6, error) {
+	return getLeases6(ctx, c, "lease6-get-by-hostname", map[string]interface{}{"hostname": hostname})
+}
+
+// Lease6Del deletes a single lease by address or prefix, or by DUID, IAID and subnet.
+func Lease6Del(c *client.Client, q Lease6Query) error {
+	return Lease6DelContext(context.Background(), c, q)
+}
+
+// Lease6DelContext is like Lease6Del but honours ctx.
+func Lease6DelContext(ctx context.Context, c *client.Client, q Lease6Query) error {
+	args, err := q.arguments()
+	if err != nil {
+		return err
+	}
+	_, err = client.CallWithArgsContext(ctx, c, "lease6-del", args, client.Services.DHCP6)
+	return err
+}
+
+// Lease6Update replaces an existing lease. With forceCreate the lease is added if it does not exist.
+func Lease6Update(c *client.Client, lease Lease6, forceCreate bool) error {
+	return Lease6UpdateContext(context.Background(), c, lease, forceCreate)
+}
+
+// Lease6UpdateContext is like Lease6Update but honours ctx.
+func Lease6UpdateContext(ctx context.Context, c *client.Client, lease Lease6, forceCreate bool) error {
+	args, err := client.EncodeArguments(lease)
+	if err != nil {
+		return err
+	}
+	if forceCreate {
+		args["force-create"] = true
+	}
+	_, err = client.CallWithArgsContext(ctx, c, "lease6-update", args, client.Services.DHCP6)
+	return err
+}
+
+// Lease6Wipe removes all leases from a subnet, or from every subnet when subnetID is 0.
+// It returns Kea's summary text. Kea has deprecated this command in favour of lease6-del.
+func Lease6Wipe(c *client.Client, subnetID int) (string, error) {
+	return Lease6WipeContext(context.Background(), c, subnetID)
+}
+
+// Lease6WipeContext is like Lease6Wipe but honours ctx.
+func Lease6WipeContext(ctx context.Context, c *client.Client, subnetID int) (string, error) {
+	var args map[string]interface{}
+	if subnetID != 0 {
+		args = map[string]interface{}{"subnet-id": subnetID}
+	}
+	responses, err := client.CallWithArgsContext(ctx, c, "lease6-wipe", args, client.Services.DHCP6)
+	if err != nil {
+		return "", err
+	}
+	return responses[0].Text, nil
+}
+
+// Lease6Write writes the in-memory lease database to a file on the server.
+func Lease6Write(c *client.Client, filename string) error {
+	return Lease6WriteContext(context.Background(), c, filename)
+}
+
+// Lease6WriteContext is like Lease6Write but honours ctx.
+func Lease6WriteContext(ctx context.Context, c *client.Client, filename string) error {
+	_, err := client.CallWithArgsContext(ctx, c, "lease6-write", map[string]interface{}{"filename": filename}, client.Services.DHCP6)
+	return err
+}
+
+// Lease6BulkApply deletes, adds and updates several leases in one lease6-bulk-apply call.
+// Leases Kea could not process are listed in the result; the error is only set when the
+// command as a whole failed.
+func Lease6BulkApply(c *client.Client, req Lease6BulkRequest) (Lease6BulkApplyResult, error) {
+	return Lease6BulkApplyContext(context.Background(), c, req)
+}
+
+// Lease6BulkApplyContext is like Lease6BulkApply but honours ctx.
+func Lease6BulkApplyContext(ctx context.Context, c *client.Client, req Lease6BulkRequest) (Lease6BulkApplyResult, error) {
+	var result Lease6BulkApplyResult
+	responses, err := client.CallWithArgsContext(ctx, c, "lease6-bulk-apply", req, client.Services.DHCP6)
+	if err != nil || len(responses[0].Arguments) == 0 {
+		return result, err // Kea only sends arguments when some lease failed
+	}
+	if err := json.Unmarshal(responses[0].Arguments, &result); err != nil {
+		return result, fmt.Errorf("decode lease6-bulk-apply arguments: %w", err)
+	}
+	return result, nil
+}
+
+// arguments encodes the query, always sending the IAID when looking up by identifier
+// since 0 is a valid IAID.
+func (q Lease6Query) arguments() (map[string]interface{}, error) {
+	args, err := client.EncodeArguments(q)
+	if err != nil {
+		return nil, err
+	}
+	if q.Identifier != "" {
+		args["iaid"] = q.IAID
+	}
+	return args, nil
+}
+
+// Lease6GetPage fetches up to limit leases ordered by address, starting after from.
+// Use "start" as from for the first page. An exhausted cursor yields an empty page.
+func Lease6GetPage(c *client.Client, from string, limit int) (Lease6Page, error) {
+	return Lease6GetPageContext(context.Background(), c, from, limit)
+}
+
+// Lease6GetPageContext is like Lease6GetPage but honours ctx.
+func Lease6GetPageContext(ctx context.Context, c *client.Client, from string, limit int) (Lease6Page, error) {
+	args := map[string]interface{}{"from": from, "limit": limit}
+	page, err := client.DecodeFirstWithArgsContext[Lease6Page](ctx, c, "lease6-get-page", args, client.Services.DHCP6)
+	if client.IsNotFound(err) {
+		return Lease6Page{Leases: []Lease6{}}, nil
+	}
+	return page, err
+}
+
+// Lease6Iter walks every lease page by page using lease6-get-page, so large lease
+// databases never have to be held in a single response. Iteration ends when Kea reports
+// no more leases, when ctx is done, or at the first error, which is yielded once.
+func Lease6Iter(ctx context.Context, c *client.Client, opts types.LeasePageOptions) iter.Seq2[Lease6, error] {
+	return func(yield func(Lease6, error) bool) {
+		from, limit := opts.PageStart(), opts.PageLimit()
+		for {
+			page, err := Lease6GetPageContext(ctx, c, from, limit)
+			if err != nil {
+				yield(Lease6{}, err)
+				return
+			}
+			if len(page.Leases) == 0 {
+				return
+			}
+			for _, lease := range page.Leases {
+				if !opts.MatchSubnet(lease.SubnetID) {
+					continue
+				}
+				if !yield(lease, nil) {
+					return
+				}
+			}
+			from = page.Leases[len(page.Leases)-1].IPAddress
+		}
+	}
+}
+
+// getLeases6 runs a multi-lease lookup, treating "no leases found" as an empty result.
+func getLeases6(ctx context.Context, c *client.Client, cmd string, args map[string]interface{}) ([]Lease6, error) {
+	list, err := client.DecodeFirstWithArgsContext[Lease6List](ctx, c, cmd, args, client.Services.DHCP6)
+	if client.IsNotFound(err) {
+		return []Lease6{}, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	return list.Leases, nil
+}
@@ -0,0 +1,334 @@
+package dhcp6
+
+import (
+	"context"
+	"encoding/json"
+	"reflect"
+	"testing"
+
+	"github.com/rannday/kea-api/client"
+	"github.com/rannday/kea-api/internal/testenv"
+	"github.com/rannday/kea-api/types"
+)
+
+// lease6PDJSON is a lease6-get reply for a delegated prefix captured from Kea 2.6.
+const lease6PDJSON = `{
+  "cltt": 12345678,
+  "duid": "42:42:42:42:42:42:42:42",
+  "fqdn-fwd": false,
+  "fqdn-rev": false,
+  "hostname": "",
+  "iaid": 1,
+  "ip-address": "2001:db8:1234:ab00::",
+  "preferred-lft": 500,
+  "prefix-len": 56,
+  "state": 0,
+  "subnet-id": 66,
+  "type": "IA_PD",
+  "valid-lft": 3600
+}`
+
+// TestLease6Add checks that IAID 0 is still sent for a new address lease.
+func TestLease6Add(t *testing.T) {
+	t.Parallel()
+
+	lease := Lease6{
+		IPAddress: "2001:db8:1::45",
+		DUID:      "1a:1b:1c:1d:1e:1f:20:21:22:23:24",
+		IAID:      0,
+		SubnetID:  66,
+	}
+
+	mockClient := testenv.NewMockClient(t,
+		testenv.ExpectCommandArgs(t, "lease6-add", map[string]any{
+			"ip-address": "2001:db8:1::45",
+			"duid":       "1a:1b:1c:1d:1e:1f:20:21:22:23:24",
+			"iaid":       0,
+			"subnet-id":  66,
+			"state":      0,
+		}, client.Services.DHCP6),
+		[]client.CommandResponse{{Result: client.ResultSuccess, Text: "Lease for address 2001:db8:1::45, subnet-id 66 added."}},
+	)
+
+	if err := Lease6Add(mockClient, lease); err != nil {
+		t.Fatalf("Lease6Add() error = %v", err)
+	}
+}
+
+// TestLease6Get_Prefix decodes a delegated-prefix lease found by prefix.
+func TestLease6Get_Prefix(t *testing.T) {
+	t.Parallel()
+
+	want := Lease6{
+		IPAddress:    "2001:db8:1234:ab00::",
+		DUID:         "42:42:42:42:42:42:42:42",
+		IAID:         1,
+		Type:         LeaseTypePD,
+		PrefixLen:    56,
+		PreferredLft: 500,
+		ValidLft:     3600,
+		CLTT:         12345678,
+		SubnetID:     66,
+		State:        types.LeaseStateDefault,
+	}
+
+	mockClient := testenv.NewMockClient(t,
+		testenv.ExpectCommandArgs(t, "lease6-get", map[string]any{
+			"ip-address": "2001:db8:1234:ab00::",
+			"type":       "IA_PD",
+		}, client.Services.DHCP6),
+		[]client.CommandResponse{{
+			Result:    client.ResultSuccess,
+			Text:      "IPv6 lease found.",
+			Arguments: json.RawMessage(lease6PDJSON),
+		}},
+	)
+
+	got, err := Lease6Get(mockClient, Lease6Query{IPAddress: "2001:db8:1234:ab00::", Type: LeaseTypePD})
+	if err != nil {
+		t.Fatalf("Lease6Get() error = %v", err)
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("Lease6Get() = %+v, want %+v", got, want)
+	}
+}
+
+// TestLease6Get_ByIdentifier checks that a zero IAID is sent when looking up by DUID.
+func TestLease6Get_ByIdentifier(t *testing.T) {
+	t.Parallel()
+
+	mockClient := testenv.NewMockClient(t,
+		testenv.ExpectCommandArgs(t, "lease6-get", map[string]any{
+			"identifier-type": "duid",
+			"identifier":      "42:42:42:42:42:42:42:42",
+			"iaid":            0,
+			"subnet-id":       66,
+			"type":            "IA_NA",
+		}, client.Services.DHCP6),
+		[]client.CommandResponse{{Result: client.ResultNotFound, Text: "Lease not found."}},
+	)
+
+	_, err := Lease6Get(mockClient, Lease6Query{
+		IdentifierType: "duid",
+		Identifier:     "42:42:42:42:42:42:42:42",
+		SubnetID:       66,
+		Type:           LeaseTypeNA,
+	})
+	if !client.IsNotFound(err) {
+		t.Errorf("Lease6Get() error = %v, want not found", err)
+	}
+}
+
+// TestLease6GetByDUID checks decoding of the leases list and the empty case.
+func TestLease6GetByDUID(t *testing.T) {
+	t.Parallel()
+
+	mockClient := testenv.NewMockClient(t,
+		testenv.ExpectCommandArgs(t, "lease6-get-by-duid", map[string]any{"duid": "42:42:42:42:42:42:42:42"}, client.Services.DHCP6),
+		[]client.CommandResponse{{
+			Result:    client.ResultSuccess,
+			Arguments: json.RawMessage(`{"leases": [` + lease6PDJSON + `]}`),
+		}},
+	)
+
+	got, err := Lease6GetByDUID(mockClient, "42:42:42:42:42:42:42:42")
+	if err != nil {
+		t.Fatalf("Lease6GetByDUID() error = %v", err)
+	}
+	if len(got) != 1 || got[0].Type != LeaseTypePD || got[0].PrefixLen != 56 {
+		t.Errorf("Lease6GetByDUID() = %+v", got)
+	}
+}
+
+// TestLease6GetByHostname_Empty checks that result 3 yields an empty slice rather than an error.
+func TestLease6GetByHostname_Empty(t *testing.T) {
+	t.Parallel()
+
+	mockClient := testenv.NewMockClient(t,
+		testenv.ExpectCommandArgs(t, "lease6-get-by-hostname", map[string]any{"hostname": "nohost"}, client.Services.DHCP6),
+		[]client.CommandResponse{{Result: client.ResultNotFound, Text: "0 IPv6 lease(s) found."}},
+	)
+
+	got, err := Lease6GetByHostname(mockClient, "nohost")
+	if err != nil {
+		t.Fatalf("Lease6GetByHostname() error = %v", err)
+	}
+	if got == nil || len(got) != 0 {
+		t.Errorf("Lease6GetByHostname() = %#v, want empty slice", got)
+	}
+}
+
+// TestLease6Del checks deletion of a delegated prefix.
+func TestLease6Del(t *testing.T) {
+	t.Parallel()
+
+	mockClient := testenv.NewMockClient(t,
+		testenv.ExpectCommandArgs(t, "lease6-del", map[string]any{
+			"ip-address": "2001:db8:1234:ab00::",
+			"type":       "IA_PD",
+		}, client.Services.DHCP6),
+		[]client.CommandResponse{{Result: client.ResultSuccess, Text: "IPv6 lease deleted."}},
+	)
+
+	if err := Lease6Del(mockClient, Lease6Query{IPAddress: "2001:db8:1234:ab00::", Type: LeaseTypePD}); err != nil {
+		t.Fatalf("Lease6Del() error = %v", err)
+	}
+}
+
+// TestLease6Update checks that force-create is added to the lease fields.
+func TestLease6Update(t *testing.T) {
+	t.Parallel()
+
+	mockClient := testenv.NewMockClient(t,
+		testenv.ExpectCommandArgs(t, "lease6-update", map[string]any{
+			"ip-address":   "2001:db8:1::1",
+			"duid":         "88:88:88:88:88:88:88:88",
+			"iaid":         7654321,
+			"subnet-id":    66,
+			"state":        0,
+			"force-create": true,
+		}, client.Services.DHCP6),
+		[]client.CommandResponse{{Result: client.ResultSuccess, Text: "IPv6 lease updated."}},
+	)
+
+	lease := Lease6{IPAddress: "2001:db8:1::1", DUID: "88:88:88:88:88:88:88:88", IAID: 7654321, SubnetID: 66}
+	if err := Lease6Update(mockClient, lease, true); err != nil {
+		t.Fatalf("Lease6Update() error = %v", err)
+	}
+}
+
+// TestLease6Wipe checks that the summary text is returned.
+func TestLease6Wipe(t *testing.T) {
+	t.Parallel()
+
+	want := "Deleted 5 IPv6 lease(s) from all subnets"
+	mockClient := testenv.NewMockClient(t,
+		testenv.ExpectCommandArgs(t, "lease6-wipe", nil, client.Services.DHCP6),
+		[]client.CommandResponse{{Result: client.ResultSuccess, Text: want}},
+	)
+
+	got, err := Lease6Wipe(mockClient, 0)
+	if err != nil {
+		t.Fatalf("Lease6Wipe() error = %v", err)
+	}
+	if got != want {
+		t.Errorf("Lease6Wipe() = %q, want %q", got, want)
+	}
+}
+
+// TestLease6Write checks the filename argument of lease6-write.
+func TestLease6Write(t *testing.T) {
+	t.Parallel()
+
+	mockClient := testenv.NewMockClient(t,
+		testenv.ExpectCommandArgs(t, "lease6-write", map[string]any{"filename": "/tmp/leases6.csv"}, client.Services.DHCP6),
+		[]client.CommandResponse{{Result: client.ResultSuccess}},
+	)
+
+	if err := Lease6Write(mockClient, "/tmp/leases6.csv"); err != nil {
+		t.Fatalf("Lease6Write() error = %v", err)
+	}
+}
+
+// TestLease6BulkApply checks the request layout and decoding of partial failures.
+func TestLease6BulkApply(t *testing.T) {
+	t.Parallel()
+
+	mockClient := testenv.NewMockClient(t,
+		testenv.ExpectCommandArgs(t, "lease6-bulk-apply", map[string]any{
+			"deleted-leases": []any{
+				map[string]any{"ip-address": "2001:db8:abcd::", "type": "IA_PD"},
+			},
+			"leases": []any{
+				map[string]any{"ip-address": "2001:db8:abcd::1234", "duid": "1a:1b:1c:1d:1e:1f", "iaid": 1234, "subnet-id": 66, "state": 0},
+			},
+		}, client.Services.DHCP6),
+		[]client.CommandResponse{{
+			Result: client.ResultSuccess,
+			Text:   "Bulk apply of 1 IPv6 leases completed.",
+			Arguments: json.RawMessage(`{
+				"failed-deleted-leases": [
+					{"error-message": "lease not found", "ip-address": "2001:db8:abcd::", "result": 3, "type": "IA_PD"}
+				],
+				"failed-leases": []
+			}`),
+		}},
+	)
+
+	got, err := Lease6BulkApply(mockClient, Lease6BulkRequest{
+		DeletedLeases: []Lease6Ref{{IPAddress: "2001:db8:abcd::", Type: LeaseTypePD}},
+		Leases:        []Lease6{{IPAddress: "2001:db8:abcd::1234", DUID: "1a:1b:1c:1d:1e:1f", IAID: 1234, SubnetID: 66}},
+	})
+	if err != nil {
+		t.Fatalf("Lease6BulkApply() error = %v", err)
+	}
+
+	want := []Lease6BulkFailure{{
+		IPAddress:    "2001:db8:abcd::",
+		Type:         LeaseTypePD,
+		Result:       client.ResultNotFound,
+		ErrorMessage: "lease not found",
+	}}
+	if !reflect.DeepEqual(got.FailedDeletedLeases, want) || len(got.FailedLeases) != 0 {
+		t.Errorf("Lease6BulkApply() = %+v", got)
+	}
+}
+
+// TestLease6BulkApply_AllApplied checks that a reply without arguments means nothing failed.
+func TestLease6BulkApply_AllApplied(t *testing.T) {
+	t.Parallel()
+
+	mockClient := testenv.NewMockClient(t,
+		testenv.ExpectCommand(t, "lease6-bulk-apply", client.Services.DHCP6),
+		[]client.CommandResponse{{Result: client.ResultSuccess, Text: "Bulk apply of 1 IPv6 leases completed."}},
+	)
+
+	got, err := Lease6BulkApply(mockClient, Lease6BulkRequest{
+		Leases: []Lease6{{IPAddress: "2001:db8:abcd::1234", DUID: "1a:1b:1c:1d:1e:1f", IAID: 1234, SubnetID: 66}},
+	})
+	if err != nil {
+		t.Fatalf("Lease6BulkApply() error = %v", err)
+	}
+	if len(got.FailedDeletedLeases) != 0 || len(got.FailedLeases) != 0 {
+		t.Errorf("Lease6BulkApply() = %+v, want no failures", got)
+	}
+}
+
+// TestLease6Iter walks prefix and address leases page by page.
+func TestLease6Iter(t *testing.T) {
+	t.Parallel()
+
+	page := func(leases ...Lease6) []client.CommandResponse {
+		return []client.CommandResponse{{
+			Result:    client.ResultSuccess,
+			Arguments: testenv.MustEncodeRawJSON(t, Lease6Page{Leases: leases, Count: len(leases)}),
+		}}
+	}
+
+	mockClient := testenv.NewSequenceMockClient(t,
+		testenv.MockStep{
+			Validate: testenv.ExpectCommandArgs(t, "lease6-get-page", map[string]any{"from": "2001:db8:1::", "limit": 2}, client.Services.DHCP6),
+			Responses: page(
+				Lease6{IPAddress: "2001:db8:1::1", Type: LeaseTypeNA, SubnetID: 1},
+				Lease6{IPAddress: "2001:db8:1234:ab00::", Type: LeaseTypePD, PrefixLen: 56, SubnetID: 2},
+			),
+		},
+		testenv.MockStep{
+			Validate:  testenv.ExpectCommandArgs(t, "lease6-get-page", map[string]any{"from": "2001:db8:1234:ab00::", "limit": 2}, client.Services.DHCP6),
+			Responses: []client.CommandResponse{{Result: client.ResultNotFound, Text: "0 IPv6 lease(s) found."}},
+		},
+	)
+
+	var got []string
+	opts := types.LeasePageOptions{From: "2001:db8:1::", Limit: 2, SubnetIDs: []int{2}}
+	for lease, err := range Lease6Iter(context.Background(), mockClient, opts) {
+		if err != nil {
+			t.Fatalf("Lease6Iter() error = %v", err)
+		}
+		got = append(got, lease.IPAddress)
+	}
+
+	if !reflect.DeepEqual(got, []string{"2001:db8:1234:ab00::"}) {
+		t.Errorf("Lease6Iter() = %v", got)
+	}
+}
@@ -0,0 +1,104 @@
+package dhcp6
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/rannday/kea-api/client"
+	"github.com/rannday/kea-api/types"
+)
+
+/*
+ * Runtime shared network management via the subnet_cmds hook library.
+ * Changes apply to the running configuration only; use config-write to persist them.
+ */
+
+// Network6List lists the names of all shared networks.
+func Network6List(c *client.Client) ([]string, error) {
+	return Network6ListContext(context.Background(), c)
+}
+
+// Network6ListContext is like Network6List but honours ctx.
+func Network6ListContext(ctx context.Context, c *client.Client) ([]string, error) {
+	list, err := client.DecodeFirstWithArgsContext[types.SharedNetworkRefList](ctx, c, "network6-list", nil, client.Services.DHCP6)
+	if client.IsNotFound(err) {
+		return []string{}, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	names := make([]string, 0, len(list.SharedNetworks))
+	for _, n := range list.SharedNetworks {
+		names = append(names, n.Name)
+	}
+	return names, nil
+}
+
+// Network6Get fetches a shared network, including its subnets, by name.
+func Network6Get(c *client.Client, name string) (SharedNetwork6, error) {
+	return Network6GetContext(context.Background(), c, name)
+}
+
+// Network6GetContext is like Network6Get but honours ctx.
+func Network6GetContext(ctx context.Context, c *client.Client, name string) (SharedNetwork6, error) {
+	res, err := client.DecodeFirstWithArgsContext[SharedNetwork6Args](ctx, c, "network6-get", map[string]interface{}{"name": name}, client.Services.DHCP6)
+	if err != nil {
+		return SharedNetwork6{}, err
+	}
+	if len(res.SharedNetworks) == 0 {
+		return SharedNetwork6{}, fmt.Errorf("network6-get returned no shared network")
+	}
+	return res.SharedNetworks[0], nil
+}
+
+// Network6Add adds a shared network together with any subnets listed in it.
+func Network6Add(c *client.Client, network SharedNetwork6) error {
+	return Network6AddContext(context.Background(), c, network)
+}
+
+// Network6AddContext is like Network6Add but honours ctx.
+func Network6AddContext(ctx context.Context, c *client.Client, network SharedNetwork6) error {
+	args := SharedNetwork6Args{SharedNetworks: []SharedNetwork6{network}}
+	_, err := client.CallWithArgsContext(ctx, c, "network6-add", args, client.Services.DHCP6)
+	return err
+}
+
+// Network6Del deletes a shared network. action decides whether its subnets are kept as
+// plain subnets or deleted as well; Kea keeps them when action is empty.
+func Network6Del(c *client.Client, name string, action types.SubnetsAction) error {
+	return Network6DelContext(context.Background(), c, name, action)
+}
+
+// Network6DelContext is like Network6Del but honours ctx.
+func Network6DelContext(ctx context.Context, c *client.Client, name string, action types.SubnetsAction) error {
+	args := map[string]interface{}{"name": name}
+	if action != "" {
+		args["subnets-action"] = action
+	}
+	_, err := client.CallWithArgsContext(ctx, c, "network6-del", args, client.Services.DHCP6)
+	return err
+}
+
+// Network6SubnetAdd moves an existing subnet into a shared network.
+func Network6SubnetAdd(c *client.Client, name string, subnetID int) error {
+	return Network6SubnetAddContext(context.Background(), c, name, subnetID)
+}
+
+// Network6SubnetAddContext is like Network6SubnetAdd but honours ctx.
+func Network6SubnetAddContext(ctx context.Context, c *client.Client, name string, subnetID int) error {
+	args := map[string]interface{}{"name": name, "id": subnetID}
+	_, err := client.CallWithArgsContext(ctx, c, "network6-subnet-add", args, client.Services.DHCP6)
+	return err
+}
+
+// Network6SubnetDel detaches a subnet from a shared network; the subnet itself is kept.
+func Network6SubnetDel(c *client.Client, name string, subnetID int) error {
+	return Network6SubnetDelContext(context.Background(), c, name, subnetID)
+}
+
+// Network6SubnetDelContext is like Network6SubnetDel but honours ctx.
+func Network6SubnetDelContext(ctx context.Context, c *client.Client, name string, subnetID int) error {
+	args := map[string]interface{}{"name": name, "id": subnetID}
+	_, err := client.CallWithArgsContext(ctx, c, "network6-subnet-del", args, client.Services.DHCP6)
+	return err
+}
@@ -0,0 +1,56 @@
+package dhcp6
+
+import (
+	"encoding/json"
+	"reflect"
+	"testing"
+
+	"github.com/rannday/kea-api/client"
+	"github.com/rannday/kea-api/internal/testenv"
+	"github.com/rannday/kea-api/types"
+)
+
+// TestNetwork6Get decodes a shared network with its subnets.
+func TestNetwork6Get(t *testing.T) {
+	t.Parallel()
+
+	mockClient := testenv.NewMockClient(t,
+		testenv.ExpectCommandArgs(t, "network6-get", map[string]any{"name": "floor13"}, client.Services.DHCP6),
+		[]client.CommandResponse{{
+			Result: client.ResultSuccess,
+			Arguments: json.RawMessage(`{"shared-networks": [{
+				"name": "floor13", "interface-id": "relay1", "rapid-commit": true,
+				"subnet6": [{"id": 5, "subnet": "2001:db8:1::/64"}]
+			}]}`),
+		}},
+	)
+
+	got, err := Network6Get(mockClient, "floor13")
+	if err != nil {
+		t.Fatalf("Network6Get() error = %v", err)
+	}
+	rapid := true
+	want := SharedNetwork6{
+		Name:        "floor13",
+		InterfaceID: "relay1",
+		RapidCommit: &rapid,
+		Subnet6:     []Subnet6{{ID: 5, Subnet: "2001:db8:1::/64"}},
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("Network6Get() = %+v, want %+v", got, want)
+	}
+}
+
+// TestNetwork6Del checks deletion together with the member subnets.
+func TestNetwork6Del(t *testing.T) {
+	t.Parallel()
+
+	mockClient := testenv.NewMockClient(t,
+		testenv.ExpectCommandArgs(t, "network6-del", map[string]any{"name": "floor13", "subnets-action": "delete"}, client.Services.DHCP6),
+		[]client.CommandResponse{{Result: client.ResultSuccess}},
+	)
+
+	if err := Network6Del(mockClient, "floor13", types.SubnetsDelete); err != nil {
+		t.Fatalf("Network6Del() error = %v", err)
+	}
+}
@@ -0,0 +1,84 @@
+package dhcp6
+
+// standardOptions maps the names of the options Kea defines in the dhcp6 space to their codes.
+var standardOptions = map[string]int{
+	"clientid":                 1,
+	"serverid":                 2,
+	"ia-na":                    3,
+	"ia-ta":                    4,
+	"iaaddr":                   5,
+	"oro":                      6,
+	"preference":               7,
+	"elapsed-time":             8,
+	"relay-msg":                9,
+	"auth":                     11,
+	"unicast":                  12,
+	"status-code":              13,
+	"rapid-commit":             14,
+	"user-class":               15,
+	"vendor-class":             16,
+	"vendor-opts":              17,
+	"interface-id":             18,
+	"reconf-msg":               19,
+	"reconf-accept":            20,
+	"sip-server-dns":           21,
+	"sip-server-addr":          22,
+	"dns-servers":              23,
+	"domain-search":            24,
+	"ia-pd":                    25,
+	"iaprefix":                 26,
+	"nis-servers":              27,
+	"nisp-servers":             28,
+	"nis-domain-name":          29,
+	"nisp-domain-name":         30,
+	"sntp-servers":             31,
+	"information-refresh-time": 32,
+	"bcmcs-server-dns":         33,
+	"bcmcs-server-addr":        34,
+	"geoconf-civic":            36,
+	"remote-id":                37,
+	"subscriber-id":            38,
+	"client-fqdn":              39,
+	"pana-agent":               40,
+	"new-posix-timezone":       41,
+	"new-tzdb-timezone":        42,
+	"ero":                      43,
+	"lq-query":                 44,
+	"client-data":              45,
+	"clt-time":                 46,
+	"lq-relay-data":            47,
+	"lq-client-link":           48,
+	"v6-lost":                  51,
+	"capwap-ac-v6":             52,
+	"relay-id":                 53,
+	"ntp-server":               56,
+	"v6-access-domain":         57,
+	"sip-ua-cs-list":           58,
+	"bootfile-url":             59,
+	"bootfile-param":           60,
+	"client-arch-type":         61,
+	"nii":                      62,
+	"aftr-name":                64,
+	"erp-local-domain-name":    65,
+	"rsoo":                     66,
+	"pd-exclude":               67,
+	"rdnss-selection":          74,
+	"client-linklayer-addr":    79,
+	"link-address":             80,
+	"solmax-rt":                82,
+	"inf-max-rt":               83,
+	"dhcpv4-message":           87,
+	"dhcp4o6-server-addr":      88,
+	"s46-rule":                 89,
+	"s46-br":                   90,
+	"s46-dmr":                  91,
+	"s46-v4v6bind":             92,
+	"s46-portparams":           93,
+	"s46-cont-mape":            94,
+	"s46-cont-mapt":            95,
+	"s46-cont-lw":              96,
+	"v6-captive-portal":        103,
+	"v6-sztp-redirect":         136,
+	"ipv6-address-andsf":       143,
+	"v6-dnr":                   144,
+}
@@ -0,0 +1,170 @@
+package dhcp6
+
+import (
+	"context"
+	"iter"
+
+	"github.com/rannday/kea-api/client"
+	"github.com/rannday/kea-api/types"
+)
+
+/*
+ * Host reservation management via the host_cmds hook library (libdhcp_host_cmds.so).
+ * As with leases, lookups returning several hosts treat result 3 as an empty
+ * slice while single-host lookups and deletions return the error.
+ */
+
+// ReservationAdd adds a host reservation with reservation-add.
+func ReservationAdd(c *client.Client, r Reservation6) error {
+	return ReservationAddContext(context.Background(), c, r)
+}
+
+// ReservationAddContext is like ReservationAdd but honours ctx.
+func ReservationAddContext(ctx context.Context, c *client.Client, r Reservation6) error {
+	args, err := reservation6Args(r)
+	if err != nil {
+		return err
+	}
+	_, err = client.CallWithArgsContext(ctx, c, "reservation-add", args, client.Services.DHCP6)
+	return err
+}
+
+// ReservationGet fetches a single reservation by address or identifier within a subnet.
+func ReservationGet(c *client.Client, q types.ReservationQuery) (Reservation6, error) {
+	return ReservationGetContext(context.Background(), c, q)
+}
+
+// ReservationGetContext is like ReservationGet but honours ctx.
+func ReservationGetContext(ctx context.Context, c *client.Client, q types.ReservationQuery) (Reservation6, error) {
+	return client.DecodeFirstWithArgsContext[Reservation6](ctx, c, "reservation-get", q, client.Services.DHCP6)
+}
+
+// ReservationGetAll fetches every reservation in a subnet; subnet 0 holds the global reservations.
+func ReservationGetAll(c *client.Client, subnetID int) ([]Reservation6, error) {
+	return ReservationGetAllContext(context.Background(), c, subnetID)
+}
+
+// ReservationGetAllContext is like ReservationGetAll but honours ctx.
+func ReservationGetAllContext(ctx context.Context, c *client.Client, subnetID int) ([]Reservation6, error) {
+	return getReservations6(ctx, c, "reservation-get-all", map[string]interface{}{"subnet-id": subnetID})
+}
+
+// ReservationGetByHostname fetches the reservations with the given hostname in all subnets.
+func ReservationGetByHostname(c *client.Client, hostname string) ([]Reservation6, error) {
+	return ReservationGetByHostnameContext(context.Background(), c, hostname)
+}
+
+// ReservationGetByHostnameContext is like ReservationGetByHostname but honours ctx.
+func ReservationGetByHostnameContext(ctx context.Context, c *client.Client, hostname string) ([]Reservation6, error) {
+	return getReservations6(ctx, c, "reservation-get-by-hostname", map[string]interface{}{"hostname": hostname})
+}
+
+// ReservationGetByAddress fetches the reservations for an IP address in all subnets.
+func ReservationGetByAddress(c *client.Client, ipAddress string) ([]Reservation6, error) {
+	return ReservationGetByAddressContext(context.Background(), c, ipAddress)
+}
+
+// ReservationGetByAddressContext is like ReservationGetByAddress but honours ctx.
+func ReservationGetByAddressContext(ctx context.Context, c *client.Client, ipAddress string) ([]Reservation6, error) {
+	return getReservations6(ctx, c, "reservation-get-by-address", map[string]interface{}{"ip-address": ipAddress})
+}
+
+// ReservationGetByID fetches the reservations for a host identifier in all subnets.
+func ReservationGetByID(c *client.Client, identifierType, identifier string) ([]Reservation6, error) {
+	return ReservationGetByIDContext(context.Background(), c, identifierType, identifier)
+}
+
+// ReservationGetByIDContext is like ReservationGetByID but honours ctx.
+func ReservationGetByIDContext(ctx context.Context, c *client.Client, identifierType, identifier string) ([]Reservation6, error) {
+	args := map[string]interface{}{"identifier-type": identifierType, "identifier": identifier}
+	return getReservations6(ctx, c, "reservation-get-by-id", args)
+}
+
+// ReservationGetPage fetches one page of reservations. Pass a nil cursor for the first page
+// and the returned Next cursor for the following ones; an exhausted listing yields an empty page.
+func ReservationGetPage(c *client.Client, opts types.ReservationPageOptions, cursor *types.ReservationPageCursor) (Reservation6Page, error) {
+	return ReservationGetPageContext(context.Background(), c, opts, cursor)
+}
+
+// ReservationGetPageContext is like ReservationGetPage but honours ctx.
+func ReservationGetPageContext(ctx context.Context, c *client.Client, opts types.ReservationPageOptions, cursor *types.ReservationPageCursor) (Reservation6Page, error) {
+	page, err := client.DecodeFirstWithArgsContext[Reservation6Page](ctx, c, "reservation-get-page", opts.PageArguments(cursor), client.Services.DHCP6)
+	if client.IsNotFound(err) {
+		return Reservation6Page{Hosts: []Reservation6{}}, nil
+	}
+	return page, err
+}
+
+// ReservationIter walks all reservations matching opts with reservation-get-page, following
+// the source-index/from cursor across the configuration file and every host database.
+// Iteration ends when Kea returns an empty page, when ctx is done, or at the first error,
+// which is yielded once.
+func ReservationIter(ctx context.Context, c *client.Client, opts types.ReservationPageOptions) iter.Seq2[Reservation6, error] {
+	return func(yield func(Reservation6, error) bool) {
+		var cursor *types.ReservationPageCursor
+		for {
+			page, err := ReservationGetPageContext(ctx, c, opts, cursor)
+			if err != nil {
+				yield(Reservation6{}, err)
+				return
+			}
+			for _, r := range page.Hosts {
+				if !yield(r, nil) {
+					return
+				}
+			}
+			if len(page.Hosts) == 0 || page.Next == nil {
+				return
+			}
+			cursor = page.Next
+		}
+	}
+}
+
+// ReservationDel deletes a reservation by address or identifier within a subnet.
+func ReservationDel(c *client.Client, q types.ReservationQuery) error {
+	return ReservationDelContext(context.Background(), c, q)
+}
+
+// ReservationDelContext is like ReservationDel but honours ctx.
+func ReservationDelContext(ctx context.Context, c *client.Client, q types.ReservationQuery) error {
+	_, err := client.CallWithArgsContext(ctx, c, "reservation-del", q, client.Services.DHCP6)
+	return err
+}
+
+// ReservationUpdate replaces an existing reservation identified by its subnet and identifier.
+func ReservationUpdate(c *client.Client, r Reservation6) error {
+	return ReservationUpdateContext(context.Background(), c, r)
+}
+
+// ReservationUpdateContext is like ReservationUpdate but honours ctx.
+func ReservationUpdateContext(ctx context.Context, c *client.Client, r Reservation6) error {
+	args, err := reservation6Args(r)
+	if err != nil {
+		return err
+	}
+	_, err = client.CallWithArgsContext(ctx, c, "reservation-update", args, client.Services.DHCP6)
+	return err
+}
+
+// reservation6Args wraps r for reservation-add/update, always sending subnet-id since 0 means global.
+func reservation6Args(r Reservation6) (map[string]interface{}, error) {
+	host, err := client.EncodeArguments(r)
+	if err != nil {
+		return nil, err
+	}
+	host["subnet-id"] = r.SubnetID
+	return map[string]interface{}{"reservation": host}, nil
+}
+
+// getReservations6 runs a multi-host lookup, treating "no hosts found" as an empty result.
+func getReservations6(ctx context.Context, c *client.Client, cmd string, args map[string]interface{}) ([]Reservation6, error) {
+	list, err := client.DecodeFirstWithArgsContext[Reservation6List](ctx, c, cmd, args, client.Services.DHCP6)
+	if client.IsNotFound(err) {
+		return []Reservation6{}, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	return list.Hosts, nil
+}
@@ -0,0 +1,119 @@
+package dhcp6
+
+import (
+	"context"
+	"encoding/json"
+	"testing"
+
+	"github.com/rannday/kea-api/client"
+	"github.com/rannday/kea-api/internal/testenv"
+	"github.com/rannday/kea-api/types"
+)
+
+// TestReservationAdd checks that addresses and delegated prefixes are sent in the reservation.
+func TestReservationAdd(t *testing.T) {
+	t.Parallel()
+
+	mockClient := testenv.NewMockClient(t,
+		testenv.ExpectCommandArgs(t, "reservation-add", map[string]any{
+			"reservation": map[string]any{
+				"subnet-id":    66,
+				"duid":         "01:02:03:04:05:06:07:08:09:0a",
+				"ip-addresses": []string{"2001:db8:1::cafe"},
+				"prefixes":     []string{"2001:db8:2:abcd::/64"},
+				"hostname":     "foo.example.com",
+			},
+		}, client.Services.DHCP6),
+		[]client.CommandResponse{{Result: client.ResultSuccess, Text: "Host added."}},
+	)
+
+	err := ReservationAdd(mockClient, Reservation6{
+		SubnetID:    66,
+		DUID:        "01:02:03:04:05:06:07:08:09:0a",
+		IPAddresses: []string{"2001:db8:1::cafe"},
+		Prefixes:    []string{"2001:db8:2:abcd::/64"},
+		Hostname:    "foo.example.com",
+	})
+	if err != nil {
+		t.Fatalf("ReservationAdd() error = %v", err)
+	}
+}
+
+// TestReservationGet decodes a reservation-get reply and reports not found.
+func TestReservationGet(t *testing.T) {
+	t.Parallel()
+
+	mockClient := testenv.NewSequenceMockClient(t,
+		testenv.MockStep{
+			Validate: testenv.ExpectCommandArgs(t, "reservation-get", map[string]any{
+				"subnet-id":       66,
+				"identifier-type": "duid",
+				"identifier":      "01:02:03:04:05:06:07:08:09:0a",
+			}, client.Services.DHCP6),
+			Responses: []client.CommandResponse{{
+				Result: client.ResultSuccess,
+				Arguments: json.RawMessage(`{
+					"client-classes": [], "duid": "01:02:03:04:05:06:07:08:09:0a", "hostname": "foo.example.com",
+					"ip-addresses": ["2001:db8:1::cafe"], "option-data": [], "prefixes": ["2001:db8:2:abcd::/64"], "subnet-id": 66
+				}`),
+			}},
+		},
+		testenv.MockStep{Responses: []client.CommandResponse{{Result: client.ResultNotFound, Text: "Host not found."}}},
+	)
+
+	q := types.ReservationQuery{SubnetID: 66, IdentifierType: "duid", Identifier: "01:02:03:04:05:06:07:08:09:0a"}
+	got, err := ReservationGet(mockClient, q)
+	if err != nil {
+		t.Fatalf("ReservationGet() error = %v", err)
+	}
+	want := Reservation6{
+		SubnetID:      66,
+		DUID:          "01:02:03:04:05:06:07:08:09:0a",
+		IPAddresses:   []string{"2001:db8:1::cafe"},
+		Prefixes:      []string{"2001:db8:2:abcd::/64"},
+		Hostname:      "foo.example.com",
+		ClientClasses: []string{},
+		OptionData:    []types.OptionData{},
+	}
+	if !testenv.EqualExported(got, want) {
+		t.Errorf("ReservationGet() = %+v, want %+v", got, want)
+	}
+
+	if _, err := ReservationGet(mockClient, q); !client.IsNotFound(err) {
+		t.Errorf("ReservationGet() error = %v, want not found", err)
+	}
+}
+
+// TestReservationIter checks paging across all subnets.
+func TestReservationIter(t *testing.T) {
+	t.Parallel()
+
+	mockClient := testenv.NewSequenceMockClient(t,
+		testenv.MockStep{
+			Validate: testenv.ExpectCommandArgs(t, "reservation-get-page", map[string]any{"limit": 100}, client.Services.DHCP6),
+			Responses: []client.CommandResponse{{
+				Result: client.ResultSuccess,
+				Arguments: testenv.MustEncodeRawJSON(t, Reservation6Page{
+					Count: 1,
+					Hosts: []Reservation6{{SubnetID: 1, DUID: "01:02"}},
+					Next:  &types.ReservationPageCursor{From: 7, SourceIndex: 1},
+				}),
+			}},
+		},
+		testenv.MockStep{
+			Validate:  testenv.ExpectCommandArgs(t, "reservation-get-page", map[string]any{"limit": 100, "source-index": 1, "from": 7}, client.Services.DHCP6),
+			Responses: []client.CommandResponse{{Result: client.ResultNotFound, Text: "0 IPv6 host(s) found."}},
+		},
+	)
+
+	count := 0
+	for _, err := range ReservationIter(context.Background(), mockClient, types.ReservationPageOptions{AllSubnets: true}) {
+		if err != nil {
+			t.Fatalf("ReservationIter() error = %v", err)
+		}
+		count++
+	}
+	if count != 1 {
+		t.Errorf("ReservationIter() yielded %d hosts, want 1", count)
+	}
+}
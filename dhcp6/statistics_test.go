@@ -0,0 +1,101 @@
+package dhcp6
+
+import (
+	"encoding/json"
+	"testing"
+	"time"
+
+	"github.com/rannday/kea-api/client"
+	"github.com/rannday/kea-api/internal/testenv"
+	"github.com/rannday/kea-api/types"
+)
+
+// TestStatisticGet decodes the samples of one statistic and handles an unknown name.
+func TestStatisticGet(t *testing.T) {
+	t.Parallel()
+
+	mockClient := testenv.NewSequenceMockClient(t,
+		testenv.MockStep{
+			Validate: testenv.ExpectCommandArgs(t, "statistic-get", map[string]any{"name": "pkt6-received"}, client.Services.DHCP6),
+			Responses: []client.CommandResponse{{
+				Result:    client.ResultSuccess,
+				Arguments: json.RawMessage(`{"pkt6-received": [[125, "2019-07-30 10:11:19.498739"], [100, "2019-07-30 10:11:09.498739"]]}`),
+			}},
+		},
+		testenv.MockStep{Responses: []client.CommandResponse{{Result: client.ResultSuccess, Arguments: json.RawMessage(`{}`)}}},
+	)
+
+	got, err := StatisticGet(mockClient, "pkt6-received")
+	if err != nil {
+		t.Fatalf("StatisticGet() error = %v", err)
+	}
+	if got.Name != "pkt6-received" || len(got.Samples) != 2 {
+		t.Fatalf("StatisticGet() = %+v", got)
+	}
+	if v, ok := got.Samples[1].Int64(); !ok || v != 100 {
+		t.Errorf("Samples[1] = %+v", got.Samples[1])
+	}
+
+	got, err = StatisticGet(mockClient, "no-such-statistic")
+	if err != nil || len(got.Samples) != 0 {
+		t.Errorf("StatisticGet() = %+v, %v, want no samples", got, err)
+	}
+}
+
+// TestStatisticGetAll returns statistics sorted by name with typed values.
+func TestStatisticGetAll(t *testing.T) {
+	t.Parallel()
+
+	mockClient := testenv.NewMockClient(t,
+		testenv.ExpectCommandArgs(t, "statistic-get-all", nil, client.Services.DHCP6),
+		[]client.CommandResponse{{
+			Result: client.ResultSuccess,
+			Arguments: json.RawMessage(`{
+				"subnet[1].assigned-addresses": [[3, "2019-07-30 10:04:28.386740"]],
+				"pkt6-received": [[0, "2019-07-30 10:04:28.386733"]]
+			}`),
+		}},
+	)
+
+	got, err := StatisticGetAll(mockClient)
+	if err != nil {
+		t.Fatalf("StatisticGetAll() error = %v", err)
+	}
+	if len(got) != 2 || got[0].Name != "pkt6-received" || got[1].Name != types.SubnetStatistic(1, "assigned-addresses") {
+		t.Errorf("StatisticGetAll() = %+v", got)
+	}
+}
+
+// TestStatisticCommands sends the expected arguments for the reset, remove and sample limit commands.
+func TestStatisticCommands(t *testing.T) {
+	t.Parallel()
+
+	ok := []client.CommandResponse{{Result: client.ResultSuccess}}
+	name := types.PDPoolStatistic(1, 0, "assigned-pds")
+	mockClient := testenv.NewSequenceMockClient(t,
+		testenv.MockStep{Validate: testenv.ExpectCommandArgs(t, "statistic-reset", map[string]any{"name": name}, client.Services.DHCP6), Responses: ok},
+		testenv.MockStep{Validate: testenv.ExpectCommandArgs(t, "statistic-reset-all", nil, client.Services.DHCP6), Responses: ok},
+		testenv.MockStep{Validate: testenv.ExpectCommandArgs(t, "statistic-remove", map[string]any{"name": name}, client.Services.DHCP6), Responses: ok},
+		testenv.MockStep{Validate: testenv.ExpectCommandArgs(t, "statistic-remove-all", nil, client.Services.DHCP6), Responses: ok},
+		testenv.MockStep{Validate: testenv.ExpectCommandArgs(t, "statistic-sample-age-set", map[string]any{"name": name, "duration": 1245}, client.Services.DHCP6), Responses: ok},
+		testenv.MockStep{Validate: testenv.ExpectCommandArgs(t, "statistic-sample-age-set-all", map[string]any{"duration": 60}, client.Services.DHCP6), Responses: ok},
+		testenv.MockStep{Validate: testenv.ExpectCommandArgs(t, "statistic-sample-count-set", map[string]any{"name": name, "max-samples": 100}, client.Services.DHCP6), Responses: ok},
+		testenv.MockStep{Validate: testenv.ExpectCommandArgs(t, "statistic-sample-count-set-all", map[string]any{"max-samples": 10}, client.Services.DHCP6), Responses: ok},
+	)
+
+	steps := []func() error{
+		func() error { return StatisticReset(mockClient, name) },
+		func() error { return StatisticResetAll(mockClient) },
+		func() error { return StatisticRemove(mockClient, name) },
+		func() error { return StatisticRemoveAll(mockClient) },
+		func() error { return StatisticSampleAgeSet(mockClient, name, 1245*time.Second) },
+		func() error { return StatisticSampleAgeSetAll(mockClient, time.Minute) },
+		func() error { return StatisticSampleCountSet(mockClient, name, 100) },
+		func() error { return StatisticSampleCountSetAll(mockClient, 10) },
+	}
+	for i, step := range steps {
+		if err := step(); err != nil {
+			t.Errorf("step %d: %v", i, err)
+		}
+	}
+}
@@ -0,0 +1,127 @@
+package dhcp6
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/rannday/kea-api/client"
+	"github.com/rannday/kea-api/types"
+)
+
+/*
+ * Runtime subnet management via the subnet_cmds hook library (libdhcp_subnet_cmds.so).
+ * Changes apply to the running configuration only; use config-write to persist them.
+ */
+
+// Subnet6List lists the ID and prefix of every configured subnet.
+func Subnet6List(c *client.Client) ([]types.SubnetRef, error) {
+	return Subnet6ListContext(context.Background(), c)
+}
+
+// Subnet6ListContext is like Subnet6List but honours ctx.
+func Subnet6ListContext(ctx context.Context, c *client.Client) ([]types.SubnetRef, error) {
+	list, err := client.DecodeFirstWithArgsContext[types.SubnetRefList](ctx, c, "subnet6-list", nil, client.Services.DHCP6)
+	if client.IsNotFound(err) {
+		return []types.SubnetRef{}, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	return list.Subnets, nil
+}
+
+// Subnet6Get fetches the full definition of a subnet by ID.
+func Subnet6Get(c *client.Client, id int) (Subnet6, error) {
+	return Subnet6GetContext(context.Background(), c, id)
+}
+
+// Subnet6GetContext is like Subnet6Get but honours ctx.
+func Subnet6GetContext(ctx context.Context, c *client.Client, id int) (Subnet6, error) {
+	return getSubnet6(ctx, c, map[string]interface{}{"id": id})
+}
+
+// Subnet6GetByPrefix fetches the full definition of a subnet by prefix, e.g. "2001:db8:1::/64".
+func Subnet6GetByPrefix(c *client.Client, prefix string) (Subnet6, error) {
+	return Subnet6GetByPrefixContext(context.Background(), c, prefix)
+}
+
+// Subnet6GetByPrefixContext is like Subnet6GetByPrefix but honours ctx.
+func Subnet6GetByPrefixContext(ctx context.Context, c *client.Client, prefix string) (Subnet6, error) {
+	return getSubnet6(ctx, c, map[string]interface{}{"subnet": prefix})
+}
+
+// Subnet6Add adds a new subnet and returns its ID and prefix as assigned by the server.
+func Subnet6Add(c *client.Client, s Subnet6) (types.SubnetRef, error) {
+	return Subnet6AddContext(context.Background(), c, s)
+}
+
+// Subnet6AddContext is like Subnet6Add but honours ctx.
+func Subnet6AddContext(ctx context.Context, c *client.Client, s Subnet6) (types.SubnetRef, error) {
+	return changeSubnet6(ctx, c, "subnet6-add", Subnet6Args{Subnet6: []Subnet6{s}})
+}
+
+// Subnet6Update replaces an existing subnet, matched by ID. Omitted parameters revert to their defaults.
+func Subnet6Update(c *client.Client, s Subnet6) (types.SubnetRef, error) {
+	return Subnet6UpdateContext(context.Background(), c, s)
+}
+
+// Subnet6UpdateContext is like Subnet6Update but honours ctx.
+func Subnet6UpdateContext(ctx context.Context, c *client.Client, s Subnet6) (types.SubnetRef, error) {
+	return changeSubnet6(ctx, c, "subnet6-update", Subnet6Args{Subnet6: []Subnet6{s}})
+}
+
+// Subnet6Del removes a subnet by ID. Its leases are left in the lease database.
+func Subnet6Del(c *client.Client, id int) (types.SubnetRef, error) {
+	return Subnet6DelContext(context.Background(), c, id)
+}
+
+// Subnet6DelContext is like Subnet6Del but honours ctx.
+func Subnet6DelContext(ctx context.Context, c *client.Client, id int) (types.SubnetRef, error) {
+	return changeSubnet6(ctx, c, "subnet6-del", map[string]interface{}{"id": id})
+}
+
+// Subnet6DeltaAdd adds or overwrites the given parameters, pools and options of an existing
+// subnet while leaving everything else untouched. s must carry the subnet's ID and prefix.
+func Subnet6DeltaAdd(c *client.Client, s Subnet6) (types.SubnetRef, error) {
+	return Subnet6DeltaAddContext(context.Background(), c, s)
+}
+
+// Subnet6DeltaAddContext is like Subnet6DeltaAdd but honours ctx.
+func Subnet6DeltaAddContext(ctx context.Context, c *client.Client, s Subnet6) (types.SubnetRef, error) {
+	return changeSubnet6(ctx, c, "subnet6-delta-add", Subnet6Args{Subnet6: []Subnet6{s}})
+}
+
+// Subnet6DeltaDel removes the given parameters, pools and options from an existing subnet.
+// s must carry the subnet's ID and prefix.
+func Subnet6DeltaDel(c *client.Client, s Subnet6) (types.SubnetRef, error) {
+	return Subnet6DeltaDelContext(context.Background(), c, s)
+}
+
+// Subnet6DeltaDelContext is like Subnet6DeltaDel but honours ctx.
+func Subnet6DeltaDelContext(ctx context.Context, c *client.Client, s Subnet6) (types.SubnetRef, error) {
+	return changeSubnet6(ctx, c, "subnet6-delta-del", Subnet6Args{Subnet6: []Subnet6{s}})
+}
+
+// getSubnet6 runs subnet6-get and unwraps the single-element "subnet6" list.
+func getSubnet6(ctx context.Context, c *client.Client, args map[string]interface{}) (Subnet6, error) {
+	res, err := client.DecodeFirstWithArgsContext[Subnet6Args](ctx, c, "subnet6-get", args, client.Services.DHCP6)
+	if err != nil {
+		return Subnet6{}, err
+	}
+	if len(res.Subnet6) == 0 {
+		return Subnet6{}, fmt.Errorf("subnet6-get returned no subnet")
+	}
+	return res.Subnet6[0], nil
+}
+
+// changeSubnet6 runs a subnet-modifying command and returns the affected subnet.
+func changeSubnet6(ctx context.Context, c *client.Client, cmd string, args interface{}) (types.SubnetRef, error) {
+	res, err := client.DecodeFirstWithArgsContext[types.SubnetRefList](ctx, c, cmd, args, client.Services.DHCP6)
+	if err != nil {
+		return types.SubnetRef{}, err
+	}
+	if len(res.Subnets) == 0 {
+		return types.SubnetRef{}, fmt.Errorf("%s returned no subnet", cmd)
+	}
+	return res.Subnets[0], nil
+}
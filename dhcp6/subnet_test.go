@@ -0,0 +1,97 @@
+package dhcp6
+
+import (
+	"encoding/json"
+	"reflect"
+	"testing"
+
+	"github.com/rannday/kea-api/client"
+	"github.com/rannday/kea-api/internal/testenv"
+	"github.com/rannday/kea-api/types"
+)
+
+// TestSubnet6Get decodes address and prefix delegation pools from subnet6-get.
+func TestSubnet6Get(t *testing.T) {
+	t.Parallel()
+
+	mockClient := testenv.NewMockClient(t,
+		testenv.ExpectCommandArgs(t, "subnet6-get", map[string]any{"subnet": "2001:db8:1::/64"}, client.Services.DHCP6),
+		[]client.CommandResponse{{
+			Result: client.ResultSuccess,
+			Arguments: json.RawMessage(`{"subnet6": [{
+				"id": 11, "subnet": "2001:db8:1::/64", "preferred-lifetime": 3000, "valid-lifetime": 4000,
+				"rapid-commit": false, "shared-network-name": "floor13",
+				"pools": [{"pool": "2001:db8:1::/80"}],
+				"pd-pools": [{"prefix": "3000::", "prefix-len": 48, "delegated-len": 64, "excluded-prefix": "3000:0:0:0:1000::", "excluded-prefix-len": 72}]
+			}]}`),
+		}},
+	)
+
+	got, err := Subnet6GetByPrefix(mockClient, "2001:db8:1::/64")
+	if err != nil {
+		t.Fatalf("Subnet6GetByPrefix() error = %v", err)
+	}
+	rapid := false
+	want := Subnet6{
+		ID:                11,
+		Subnet:            "2001:db8:1::/64",
+		PreferredLifetime: 3000,
+		ValidLifetime:     4000,
+		RapidCommit:       &rapid,
+		SharedNetworkName: "floor13",
+		Pools:             []Pool6{{Pool: "2001:db8:1::/80"}},
+		PDPools: []PDPool{{
+			Prefix: "3000::", PrefixLen: 48, DelegatedLen: 64,
+			ExcludedPrefix: "3000:0:0:0:1000::", ExcludedPrefixLen: 72,
+		}},
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("Subnet6GetByPrefix() = %+v, want %+v", got, want)
+	}
+}
+
+// TestSubnet6DeltaAdd checks that only the given pool is sent alongside the subnet identity.
+func TestSubnet6DeltaAdd(t *testing.T) {
+	t.Parallel()
+
+	mockClient := testenv.NewMockClient(t,
+		testenv.ExpectCommandArgs(t, "subnet6-delta-add", map[string]any{"subnet6": []any{map[string]any{
+			"id":       11,
+			"subnet":   "2001:db8:1::/64",
+			"pd-pools": []any{map[string]any{"prefix": "3001::", "prefix-len": 48, "delegated-len": 56}},
+		}}}, client.Services.DHCP6),
+		[]client.CommandResponse{{
+			Result:    client.ResultSuccess,
+			Arguments: json.RawMessage(`{"subnets": [{"id": 11, "subnet": "2001:db8:1::/64"}]}`),
+		}},
+	)
+
+	got, err := Subnet6DeltaAdd(mockClient, Subnet6{
+		ID:      11,
+		Subnet:  "2001:db8:1::/64",
+		PDPools: []PDPool{{Prefix: "3001::", PrefixLen: 48, DelegatedLen: 56}},
+	})
+	if err != nil {
+		t.Fatalf("Subnet6DeltaAdd() error = %v", err)
+	}
+	if got != (types.SubnetRef{ID: 11, Subnet: "2001:db8:1::/64"}) {
+		t.Errorf("Subnet6DeltaAdd() = %+v", got)
+	}
+}
+
+// TestSubnet6Del checks deletion by ID.
+func TestSubnet6Del(t *testing.T) {
+	t.Parallel()
+
+	mockClient := testenv.NewMockClient(t,
+		testenv.ExpectCommandArgs(t, "subnet6-del", map[string]any{"id": 11}, client.Services.DHCP6),
+		[]client.CommandResponse{{
+			Result:    client.ResultSuccess,
+			Arguments: json.RawMessage(`{"subnets": [{"id": 11, "subnet": "2001:db8:1::/64"}]}`),
+		}},
+	)
+
+	if _, err := Subnet6Del(mockClient, 11); err != nil {
+		t.Fatalf("Subnet6Del() error = %v", err)
+	}
+}
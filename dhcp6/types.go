@@ -1,6 +1,14 @@
 package dhcp6
 
-import "github.com/rannday/kea-api/types"
+import (
+	"encoding/json"
+	"fmt"
+	"net/netip"
+
+	"github.com/rannday/kea-api/client"
+	"github.com/rannday/kea-api/internal/utils"
+	"github.com/rannday/kea-api/types"
+)
 
 // DHCP6Status represents the result of status-get for dhcp6.
 type DHCP6Status struct {
@@ -14,6 +22,7 @@ type DHCP6Status struct {
 	Sockets               map[string]interface{} `json:"sockets"`
 	DHCPState             types.DHCPState        `json:"dhcp-state"`
 	ExtendedInfoTables    bool                   `json:"extended-info-tables"`
+	HighAvailability      []types.HAStatus       `json:"high-availability,omitempty"` // Present when the ha hook library is loaded
 }
 
 // DHCP6Version is the response type for version-get on kea-dhcp6.
@@ -23,8 +32,10 @@ type DHCP6Version struct {
 
 // Dhcp6Config is the typed response from config-get on the dhcp6 service.
 type Dhcp6Config struct {
-	Dhcp6 Dhcp6Block `json:"Dhcp6"`
-	Hash  string     `json:"hash"`
+	Dhcp6  Dhcp6Block                 `json:"Dhcp6"`
+	Hash   string                     `json:"hash"`
+	Extra  map[string]json.RawMessage `json:"-"`
+	absent utils.Absent
 }
 
 // Dhcp6Block contains all DHCPv6 configuration parameters as returned by config-get.
@@ -57,14 +68,15 @@ type Dhcp6Block struct {
 	Loggers                    []types.LoggerConfig       `json:"loggers"`
 	MacSources                 []string                   `json:"mac-sources"`
 	MultiThreading             types.MultiThreadingConfig `json:"multi-threading"`
-	OptionData                 []interface{}              `json:"option-data"`
-	OptionDef                  []interface{}              `json:"option-def"`
+	OptionData                 []types.OptionData         `json:"option-data"`
+	OptionDef                  []types.OptionDef          `json:"option-def"`
 	ParkedPacketLimit          int                        `json:"parked-packet-limit"`
 	PDAllocator                string                     `json:"pd-allocator"`
 	PreferredLifetime          int                        `json:"preferred-lifetime"`
 	RebindTimer                int                        `json:"rebind-timer"`
 	RelaySuppliedOptions       []string                   `json:"relay-supplied-options"`
 	RenewTimer                 int                        `json:"renew-timer"`
+	Reservations               []Reservation6             `json:"reservations,omitempty"`
 	ReservationsGlobal         bool                       `json:"reservations-global"`
 	ReservationsInSubnet       bool                       `json:"reservations-in-subnet"`
 	ReservationsLookupFirst    bool                       `json:"reservations-lookup-first"`
@@ -72,51 +84,301 @@ type Dhcp6Block struct {
 	SanityChecks               types.SanityChecks         `json:"sanity-checks"`
 	ServerID                   ServerID                   `json:"server-id"`
 	ServerTag                  string                     `json:"server-tag"`
-	SharedNetworks             []interface{}              `json:"shared-networks"`
+	SharedNetworks             []SharedNetwork6           `json:"shared-networks"`
 	StatisticSampleAge         int                        `json:"statistic-default-sample-age"`
 	StatisticSampleCount       int                        `json:"statistic-default-sample-count"`
 	StoreExtendedInfo          bool                       `json:"store-extended-info"`
-	Subnet6                    []interface{}              `json:"subnet6"`
+	Subnet6                    []Subnet6                  `json:"subnet6"`
 	T1Percent                  float64                    `json:"t1-percent"`
 	T2Percent                  float64                    `json:"t2-percent"`
 	ValidLifetime              int                        `json:"valid-lifetime"`
+	Extra                      map[string]json.RawMessage `json:"-"`
+	absent                     utils.Absent
 }
 
 // DhcpDDNSConfig defines how and where to send DDNS updates.
 type DhcpDDNSConfig struct {
-	EnableUpdates bool   `json:"enable-updates"`
-	MaxQueueSize  int    `json:"max-queue-size"`
-	NCRFormat     string `json:"ncr-format"`
-	NCRProtocol   string `json:"ncr-protocol"`
-	SenderIP      string `json:"sender-ip"`
-	SenderPort    int    `json:"sender-port"`
-	ServerIP      string `json:"server-ip"`
-	ServerPort    int    `json:"server-port"`
+	EnableUpdates bool                       `json:"enable-updates"`
+	MaxQueueSize  int                        `json:"max-queue-size"`
+	NCRFormat     string                     `json:"ncr-format"`
+	NCRProtocol   string                     `json:"ncr-protocol"`
+	SenderIP      string                     `json:"sender-ip"`
+	SenderPort    int                        `json:"sender-port"`
+	ServerIP      string                     `json:"server-ip"`
+	ServerPort    int                        `json:"server-port"`
+	Extra         map[string]json.RawMessage `json:"-"`
+	absent        utils.Absent
 }
 
 // DhcpQueueControl controls queue behavior for asynchronous packet processing.
 type DhcpQueueControl struct {
-	Capacity    int    `json:"capacity"`
-	EnableQueue bool   `json:"enable-queue"`
-	QueueType   string `json:"queue-type"`
+	Capacity    int                        `json:"capacity"`
+	EnableQueue bool                       `json:"enable-queue"`
+	QueueType   string                     `json:"queue-type"`
+	Extra       map[string]json.RawMessage `json:"-"`
+	absent      utils.Absent
 }
 
 // ExpiredLeasesProcessing defines how expired or released leases are reclaimed.
 type ExpiredLeasesProcessing struct {
-	FlushReclaimedTimerWaitTime int `json:"flush-reclaimed-timer-wait-time"`
-	HoldReclaimedTime           int `json:"hold-reclaimed-time"`
-	MaxReclaimLeases            int `json:"max-reclaim-leases"`
-	MaxReclaimTime              int `json:"max-reclaim-time"`
-	ReclaimTimerWaitTime        int `json:"reclaim-timer-wait-time"`
-	UnwarnedReclaimCycles       int `json:"unwarned-reclaim-cycles"`
+	FlushReclaimedTimerWaitTime int                        `json:"flush-reclaimed-timer-wait-time"`
+	HoldReclaimedTime           int                        `json:"hold-reclaimed-time"`
+	MaxReclaimLeases            int                        `json:"max-reclaim-leases"`
+	MaxReclaimTime              int                        `json:"max-reclaim-time"`
+	ReclaimTimerWaitTime        int                        `json:"reclaim-timer-wait-time"`
+	UnwarnedReclaimCycles       int                        `json:"unwarned-reclaim-cycles"`
+	Extra                       map[string]json.RawMessage `json:"-"`
+	absent                      utils.Absent
 }
 
 // ServerID identifies the DHCPv6 server in transactions.
 type ServerID struct {
-	EnterpriseID int    `json:"enterprise-id"`
-	HType        int    `json:"htype"`
-	Identifier   string `json:"identifier"`
-	Persist      bool   `json:"persist"`
-	Time         int    `json:"time"`
-	Type         string `json:"type"`
+	EnterpriseID int                        `json:"enterprise-id"`
+	HType        int                        `json:"htype"`
+	Identifier   string                     `json:"identifier"`
+	Persist      bool                       `json:"persist"`
+	Time         int                        `json:"time"`
+	Type         string                     `json:"type"`
+	Extra        map[string]json.RawMessage `json:"-"`
+	absent       utils.Absent
+}
+
+// LeaseType distinguishes address leases from delegated prefixes.
+type LeaseType string
+
+// Lease types accepted by the lease6-* commands.
+const (
+	LeaseTypeNA LeaseType = "IA_NA" // Non-temporary address
+	LeaseTypeTA LeaseType = "IA_TA" // Temporary address
+	LeaseTypePD LeaseType = "IA_PD" // Delegated prefix
+)
+
+// Lease6 is an IPv6 lease as handled by the lease6-* commands of the lease_cmds hook.
+// For IA_PD leases IPAddress holds the delegated prefix and PrefixLen its length.
+// Expire is only sent on lease6-add and lease6-update; Kea reports CLTT instead.
+type Lease6 struct {
+	IPAddress    string                 `json:"ip-address"`
+	DUID         string                 `json:"duid"`
+	IAID         uint32                 `json:"iaid"`
+	Type         LeaseType              `json:"type,omitempty"`
+	PrefixLen    int                    `json:"prefix-len,omitempty"`
+	HWAddress    string                 `json:"hw-address,omitempty"`
+	PreferredLft int                    `json:"preferred-lft,omitempty"`
+	ValidLft     int                    `json:"valid-lft,omitempty"`
+	CLTT         int64                  `json:"cltt,omitempty"`
+	Expire       int64                  `json:"expire,omitempty"`
+	SubnetID     int                    `json:"subnet-id,omitempty"`
+	PoolID       int                    `json:"pool-id,omitempty"`
+	FQDNFwd      bool                   `json:"fqdn-fwd,omitempty"`
+	FQDNRev      bool                   `json:"fqdn-rev,omitempty"`
+	Hostname     string                 `json:"hostname,omitempty"`
+	State        types.LeaseState       `json:"state"`
+	UserContext  map[string]interface{} `json:"user-context,omitempty"`
+}
+
+// Lease6Query selects a single lease for lease6-get and lease6-del.
+// Set either IPAddress (and Type for a prefix), or IdentifierType ("duid"),
+// Identifier, IAID, SubnetID and Type.
+type Lease6Query struct {
+	IPAddress      string    `json:"ip-address,omitempty"`
+	Type           LeaseType `json:"type,omitempty"`
+	IdentifierType string    `json:"identifier-type,omitempty"`
+	Identifier     string    `json:"identifier,omitempty"`
+	IAID           uint32    `json:"iaid,omitempty"`
+	SubnetID       int       `json:"subnet-id,omitempty"`
+	UpdateDDNS     bool      `json:"update-ddns,omitempty"` // lease6-del only: remove the DNS entries too
+}
+
+// Lease6List is the arguments block returned by the lease6-get-all and lease6-get-by-* commands.
+type Lease6List struct {
+	Leases []Lease6 `json:"leases"`
+}
+
+// Lease6Ref identifies a lease to delete in a lease6-bulk-apply request.
+type Lease6Ref struct {
+	IPAddress string    `json:"ip-address"`
+	Type      LeaseType `json:"type,omitempty"`
+}
+
+// Lease6BulkRequest is the request for lease6-bulk-apply: leases to delete and leases to add or update,
+// applied in a single call.
+type Lease6BulkRequest struct {
+	DeletedLeases []Lease6Ref `json:"deleted-leases,omitempty"`
+	Leases        []Lease6    `json:"leases,omitempty"`
+}
+
+// Lease6BulkFailure describes a lease that lease6-bulk-apply could not process.
+type Lease6BulkFailure struct {
+	IPAddress    string            `json:"ip-address"`
+	Type         LeaseType         `json:"type"`
+	Result       client.ResultCode `json:"result"`
+	ErrorMessage string            `json:"error-message"`
+}
+
+// Lease6BulkApplyResult lists the leases lease6-bulk-apply failed to delete or update.
+type Lease6BulkApplyResult struct {
+	FailedDeletedLeases []Lease6BulkFailure `json:"failed-deleted-leases"`
+	FailedLeases        []Lease6BulkFailure `json:"failed-leases"`
+}
+
+// Lease6Page is the arguments block returned by lease6-get-page.
+type Lease6Page struct {
+	Leases []Lease6 `json:"leases"`
+	Count  int      `json:"count"`
+}
+
+// Reservation6 is an IPv6 host reservation, as handled by the reservation-* commands of the
+// host_cmds hook. Exactly one identifier field should be set. SubnetID is 0 for global
+// reservations and is always sent by ReservationAdd and ReservationUpdate.
+type Reservation6 struct {
+	SubnetID      int                        `json:"subnet-id,omitempty"`
+	DUID          string                     `json:"duid,omitempty"`
+	HWAddress     string                     `json:"hw-address,omitempty"`
+	FlexID        string                     `json:"flex-id,omitempty"`
+	IPAddresses   []string                   `json:"ip-addresses,omitempty"`
+	Prefixes      []string                   `json:"prefixes,omitempty"`
+	Hostname      string                     `json:"hostname,omitempty"`
+	ClientClasses []string                   `json:"client-classes,omitempty"`
+	OptionData    []types.OptionData         `json:"option-data,omitempty"`
+	UserContext   map[string]interface{}     `json:"user-context,omitempty"`
+	Extra         map[string]json.RawMessage `json:"-"`
+	absent        utils.Absent
+}
+
+// Identifier returns the identifier type and value of the reservation, or empty strings if none is set.
+func (r Reservation6) Identifier() (string, string) {
+	switch {
+	case r.DUID != "":
+		return "duid", r.DUID
+	case r.HWAddress != "":
+		return "hw-address", r.HWAddress
+	case r.FlexID != "":
+		return "flex-id", r.FlexID
+	}
+	return "", ""
+}
+
+// Reservation6List is the arguments block returned by reservation-get-all and reservation-get-by-*.
+type Reservation6List struct {
+	Hosts []Reservation6 `json:"hosts"`
+}
+
+// Reservation6Page is the arguments block returned by reservation-get-page.
+type Reservation6Page struct {
+	Count int                          `json:"count"`
+	Hosts []Reservation6               `json:"hosts"`
+	Next  *types.ReservationPageCursor `json:"next,omitempty"`
+}
+
+// Subnet6 is an IPv6 subnet, as used by the subnet6-* commands of the subnet_cmds hook
+// and in the subnet6 and shared-networks lists of the configuration.
+// Zero values are omitted so the server-level defaults apply.
+type Subnet6 struct {
+	ID                    int                    `json:"id,omitempty"`
+	Subnet                string                 `json:"subnet"`
+	Pools                 []Pool6                `json:"pools,omitempty"`
+	PDPools               []PDPool               `json:"pd-pools,omitempty"`
+	OptionData            []types.OptionData     `json:"option-data,omitempty"`
+	Reservations          []Reservation6         `json:"reservations,omitempty"`
+	SharedNetworkName     string                 `json:"shared-network-name,omitempty"`
+	Interface             string                 `json:"interface,omitempty"`
+	InterfaceID           string                 `json:"interface-id,omitempty"`
+	Relay                 *types.Relay           `json:"relay,omitempty"`
+	PreferredLifetime     int                    `json:"preferred-lifetime,omitempty"`
+	MinPreferredLifetime  int                    `json:"min-preferred-lifetime,omitempty"`
+	MaxPreferredLifetime  int                    `json:"max-preferred-lifetime,omitempty"`
+	ValidLifetime         int                    `json:"valid-lifetime,omitempty"`
+	MinValidLifetime      int                    `json:"min-valid-lifetime,omitempty"`
+	MaxValidLifetime      int                    `json:"max-valid-lifetime,omitempty"`
+	RenewTimer            int                    `json:"renew-timer,omitempty"`
+	RebindTimer           int                    `json:"rebind-timer,omitempty"`
+	CalculateTeeTimes     *bool                  `json:"calculate-tee-times,omitempty"`
+	T1Percent             float64                `json:"t1-percent,omitempty"`
+	T2Percent             float64                `json:"t2-percent,omitempty"`
+	RapidCommit           *bool                  `json:"rapid-commit,omitempty"`
+	ReservationsGlobal    *bool                  `json:"reservations-global,omitempty"`
+	ReservationsInSubnet  *bool                  `json:"reservations-in-subnet,omitempty"`
+	ReservationsOutOfPool *bool                  `json:"reservations-out-of-pool,omitempty"`
+	UserContext           map[string]interface{} `json:"user-context,omitempty"`
+	types.ClientClassRestriction
+	Extra  map[string]json.RawMessage `json:"-"`
+	absent utils.Absent
+}
+
+// Pool6 is an address pool within a Subnet6, given as "first - last" or in CIDR notation.
+type Pool6 struct {
+	Pool        string                 `json:"pool"`
+	PoolID      int                    `json:"pool-id,omitempty"`
+	OptionData  []types.OptionData     `json:"option-data,omitempty"`
+	UserContext map[string]interface{} `json:"user-context,omitempty"`
+	types.ClientClassRestriction
+	Extra  map[string]json.RawMessage `json:"-"`
+	absent utils.Absent
+}
+
+// Range returns the first and last address of the pool.
+func (p Pool6) Range() (netip.Addr, netip.Addr, error) {
+	return types.ParsePool(p.Pool)
+}
+
+// PDPool is a prefix delegation pool: Prefix/PrefixLen is carved into prefixes of DelegatedLen.
+type PDPool struct {
+	Prefix            string                 `json:"prefix"`
+	PrefixLen         int                    `json:"prefix-len"`
+	DelegatedLen      int                    `json:"delegated-len"`
+	ExcludedPrefix    string                 `json:"excluded-prefix,omitempty"`
+	ExcludedPrefixLen int                    `json:"excluded-prefix-len,omitempty"`
+	PoolID            int                    `json:"pool-id,omitempty"`
+	OptionData        []types.OptionData     `json:"option-data,omitempty"`
+	UserContext       map[string]interface{} `json:"user-context,omitempty"`
+	types.ClientClassRestriction
+	Extra  map[string]json.RawMessage `json:"-"`
+	absent utils.Absent
+}
+
+// Network returns the prefix the pool delegates from.
+func (p PDPool) Network() (netip.Prefix, error) {
+	addr, err := netip.ParseAddr(p.Prefix)
+	if err != nil {
+		return netip.Prefix{}, fmt.Errorf("invalid pd-pool prefix %q: %w", p.Prefix, err)
+	}
+	return addr.Prefix(p.PrefixLen)
+}
+
+// Subnet6Args is the "subnet6" list sent by subnet6-add/update/delta-* and returned by subnet6-get.
+type Subnet6Args struct {
+	Subnet6 []Subnet6 `json:"subnet6"`
+}
+
+// SharedNetwork6 groups IPv6 subnets that share a physical link, as used by the network6-* commands.
+// Parameters set here are inherited by the member subnets unless they override them.
+type SharedNetwork6 struct {
+	Name                  string                 `json:"name"`
+	Subnet6               []Subnet6              `json:"subnet6,omitempty"`
+	Interface             string                 `json:"interface,omitempty"`
+	InterfaceID           string                 `json:"interface-id,omitempty"`
+	Relay                 *types.Relay           `json:"relay,omitempty"`
+	OptionData            []types.OptionData     `json:"option-data,omitempty"`
+	PreferredLifetime     int                    `json:"preferred-lifetime,omitempty"`
+	MinPreferredLifetime  int                    `json:"min-preferred-lifetime,omitempty"`
+	MaxPreferredLifetime  int                    `json:"max-preferred-lifetime,omitempty"`
+	ValidLifetime         int                    `json:"valid-lifetime,omitempty"`
+	MinValidLifetime      int                    `json:"min-valid-lifetime,omitempty"`
+	MaxValidLifetime      int                    `json:"max-valid-lifetime,omitempty"`
+	RenewTimer            int                    `json:"renew-timer,omitempty"`
+	RebindTimer           int                    `json:"rebind-timer,omitempty"`
+	CalculateTeeTimes     *bool                  `json:"calculate-tee-times,omitempty"`
+	T1Percent             float64                `json:"t1-percent,omitempty"`
+	T2Percent             float64                `json:"t2-percent,omitempty"`
+	RapidCommit           *bool                  `json:"rapid-commit,omitempty"`
+	ReservationsOutOfPool *bool                  `json:"reservations-out-of-pool,omitempty"`
+	UserContext           map[string]interface{} `json:"user-context,omitempty"`
+	types.ClientClassRestriction
+	Extra  map[string]json.RawMessage `json:"-"`
+	absent utils.Absent
+}
+
+// SharedNetwork6Args is the "shared-networks" list sent by network6-add and returned by network6-get.
+type SharedNetwork6Args struct {
+	SharedNetworks []SharedNetwork6 `json:"shared-networks"`
 }
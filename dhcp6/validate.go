@@ -0,0 +1,132 @@
+package dhcp6
+
+import (
+	"fmt"
+	"net/netip"
+
+	"github.com/rannday/kea-api/configfile"
+	"github.com/rannday/kea-api/internal/validate"
+	"github.com/rannday/kea-api/types"
+)
+
+/*
+ * Offline validation. Validate catches common mistakes without a running server;
+ * it complements config-test rather than replacing it.
+ */
+
+// Validate checks b for duplicate subnet ids, pools and pd-pools outside their subnet
+// or overlapping each other, reservations outside their subnet or inside a pool when
+// reservations-out-of-pool is set, misordered lease timers, unknown option names and,
+// when opts.ControlSockets is set, a control socket the Control Agent does not use.
+// Diagnostics point into the configuration as "/Dhcp6/...".
+func Validate(b Dhcp6Block, opts types.ValidateOptions) types.Diagnostics {
+	const root = "/Dhcp6"
+	c := validate.New()
+	known := validate.NewOptionSet("dhcp6", standardOptions, b.OptionDef)
+
+	global := validate.Timers{
+		Renew: b.RenewTimer, Rebind: b.RebindTimer, Valid: b.ValidLifetime,
+		T1Percent: b.T1Percent, T2Percent: b.T2Percent,
+	}
+	c.Timers(root, global, global)
+	c.Options(root, b.OptionData, known)
+	for i, r := range b.Reservations {
+		c.Options(fmt.Sprintf("%s/reservations/%d", root, i), r.OptionData, known)
+	}
+
+	for i, sn := range b.SharedNetworks {
+		p := fmt.Sprintf("%s/shared-networks/%d", root, i)
+		own := validate.Timers{
+			Renew: sn.RenewTimer, Rebind: sn.RebindTimer, Valid: sn.ValidLifetime,
+			T1Percent: sn.T1Percent, T2Percent: sn.T2Percent,
+		}
+		effective := own.Inherit(global)
+		c.Timers(p, own, effective)
+		c.Options(p, sn.OptionData, known)
+		outOfPool := b.ReservationsOutOfPool
+		if sn.ReservationsOutOfPool != nil {
+			outOfPool = *sn.ReservationsOutOfPool
+		}
+		for j, s := range sn.Subnet6 {
+			validateSubnet(c, fmt.Sprintf("%s/subnet6/%d", p, j), s, effective, outOfPool, known)
+		}
+	}
+	for i, s := range b.Subnet6 {
+		validateSubnet(c, fmt.Sprintf("%s/subnet6/%d", root, i), s, global, b.ReservationsOutOfPool, known)
+	}
+
+	c.ControlSocket(root+"/control-socket", "dhcp6", b.ControlSocket, opts.ControlSockets)
+	return c.Finish()
+}
+
+// validateSubnet checks one subnet, inheriting timers from parent and
+// reservations-out-of-pool from parentOutOfPool.
+func validateSubnet(c *validate.Checker, p string, s Subnet6, parent validate.Timers, parentOutOfPool bool, known validate.OptionSet) {
+	c.SubnetID(p, s.ID)
+	prefix, _ := c.Subnet(p, s.Subnet, true)
+
+	own := validate.Timers{
+		Renew: s.RenewTimer, Rebind: s.RebindTimer, Valid: s.ValidLifetime,
+		T1Percent: s.T1Percent, T2Percent: s.T2Percent,
+	}
+	c.Timers(p, own, own.Inherit(parent))
+	c.Options(p, s.OptionData, known)
+
+	var pools, pdPools []validate.Range
+	for i, pool := range s.Pools {
+		pp := fmt.Sprintf("%s/pools/%d", p, i)
+		if r, ok := c.Pool(pp, prefix, pool.Pool); ok {
+			pools = append(pools, r)
+		}
+		c.Options(pp, pool.OptionData, known)
+	}
+	for i, pd := range s.PDPools {
+		pp := fmt.Sprintf("%s/pd-pools/%d", p, i)
+		network, err := pd.Network()
+		if r, ok := c.PDPool(pp, network, err); ok {
+			pdPools = append(pdPools, r)
+		}
+		c.Options(pp, pd.OptionData, known)
+	}
+
+	outOfPool := parentOutOfPool
+	if s.ReservationsOutOfPool != nil {
+		outOfPool = *s.ReservationsOutOfPool
+	}
+	for i, r := range s.Reservations {
+		rp := fmt.Sprintf("%s/reservations/%d", p, i)
+		c.Options(rp, r.OptionData, known)
+		for j, a := range r.IPAddresses {
+			ap := fmt.Sprintf("%s/ip-addresses/%d", rp, j)
+			addr, err := netip.ParseAddr(a)
+			if err != nil || !addr.Is6() {
+				c.Errorf(types.DiagInvalidValue, ap, "invalid reserved address %q", a)
+				continue
+			}
+			c.Reserved(validate.Range{Pointer: ap, First: addr, Last: addr}, "address "+a, prefix, pools, outOfPool)
+		}
+		for j, pfx := range r.Prefixes {
+			ap := fmt.Sprintf("%s/prefixes/%d", rp, j)
+			network, err := netip.ParsePrefix(pfx)
+			if err != nil || !network.Addr().Is6() {
+				c.Errorf(types.DiagInvalidValue, ap, "invalid reserved prefix %q", pfx)
+				continue
+			}
+			// Delegated prefixes need not lie within the subnet.
+			c.Reserved(validate.PrefixRange(ap, network), "prefix "+pfx, netip.Prefix{}, pdPools, outOfPool)
+		}
+	}
+}
+
+// ValidateConfigFile loads a kea-dhcp6.conf file and validates its Dhcp6 block,
+// locating each diagnostic in the file. The error reports a file that cannot be loaded.
+func ValidateConfigFile(path string, opts types.ValidateOptions) (types.Diagnostics, error) {
+	var cfg Dhcp6Config
+	doc, err := configfile.Load(path, &cfg)
+	if err != nil {
+		return nil, err
+	}
+	diags := Validate(cfg.Dhcp6, opts)
+	validate.Locate(diags, doc)
+	return diags, nil
+}
@@ -0,0 +1,88 @@
+package dhcp6
+
+import (
+	"testing"
+
+	"github.com/rannday/kea-api/types"
+)
+
+// TestValidate covers the DHCPv6-specific checks: pd-pools and reserved prefixes.
+func TestValidate(t *testing.T) {
+	t.Parallel()
+
+	b := Dhcp6Block{
+		ReservationsOutOfPool: true,
+		OptionData:            []types.OptionData{{Name: "dns-servers", Data: "2001:db8::53"}, {Name: "domain-name-servers", Data: "2001:db8::53"}},
+		Subnet6: []Subnet6{
+			{
+				ID: 1, Subnet: "2001:db8:1::/64",
+				Pools: []Pool6{{Pool: "2001:db8:1::10 - 2001:db8:1::ffff"}, {Pool: "2001:db8:2::/120"}},
+				PDPools: []PDPool{
+					{Prefix: "2001:db8:8::", PrefixLen: 48, DelegatedLen: 56},
+					{Prefix: "2001:db8:8:100::", PrefixLen: 56, DelegatedLen: 64},
+				},
+				Reservations: []Reservation6{{
+					DUID:        "01:02:03:04",
+					IPAddresses: []string{"2001:db8:1::20", "2001:db8:1:0:1::"},
+					Prefixes:    []string{"2001:db8:8:200::/56", "2001:db8:9::/56"},
+				}},
+			},
+			{ID: 1, Subnet: "2001:db8:3::/64", T1Percent: 0.5, T2Percent: 0.5},
+		},
+	}
+
+	want := []diagKey{
+		{types.DiagUnknownOption, "/Dhcp6/option-data/1/name"},
+		{types.DiagPoolOutsideSubnet, "/Dhcp6/subnet6/0/pools/1/pool"},
+		{types.DiagReservationInPool, "/Dhcp6/subnet6/0/reservations/0/ip-addresses/0"},
+		{types.DiagReservationInPool, "/Dhcp6/subnet6/0/reservations/0/prefixes/0"},
+		{types.DiagDuplicateSubnetID, "/Dhcp6/subnet6/1/id"},
+		{types.DiagTimerOrder, "/Dhcp6/subnet6/1/t1-percent"},
+		{types.DiagPoolOverlap, "/Dhcp6/subnet6/0/pd-pools/1"},
+	}
+	diags := Validate(b, types.ValidateOptions{})
+	var got []diagKey
+	for _, d := range diags {
+		got = append(got, diagKey{d.Code, d.Pointer})
+	}
+	if len(got) != len(want) {
+		t.Fatalf("Validate() =\n%v", diags)
+	}
+	for i := range want {
+		if got[i] != want[i] {
+			t.Errorf("Validate()[%d] = %v, want %v", i, got[i], want[i])
+		}
+	}
+}
+
+// TestValidate_SharedNetwork inherits reservations-out-of-pool from the shared network.
+func TestValidate_SharedNetwork(t *testing.T) {
+	t.Parallel()
+
+	subnet := Subnet6{
+		ID: 1, Subnet: "2001:db8:1::/64",
+		Pools:        []Pool6{{Pool: "2001:db8:1::10 - 2001:db8:1::ffff"}},
+		Reservations: []Reservation6{{DUID: "01:02:03:04", IPAddresses: []string{"2001:db8:1::20"}}},
+	}
+	outOfPool, inPool := true, false
+	b := Dhcp6Block{SharedNetworks: []SharedNetwork6{
+		{Name: "net", ReservationsOutOfPool: &outOfPool, Subnet6: []Subnet6{subnet}},
+	}}
+	diags := Validate(b, types.ValidateOptions{})
+	want := diagKey{types.DiagReservationInPool, "/Dhcp6/shared-networks/0/subnet6/0/reservations/0/ip-addresses/0"}
+	if len(diags) != 1 || (diagKey{diags[0].Code, diags[0].Pointer}) != want {
+		t.Errorf("Validate() =\n%v, want %v", diags, want)
+	}
+
+	b.ReservationsOutOfPool = true
+	b.SharedNetworks[0].ReservationsOutOfPool = &inPool
+	if diags := Validate(b, types.ValidateOptions{}); len(diags) != 0 {
+		t.Errorf("Validate() =\n%v, want no diagnostics", diags)
+	}
+}
+
+// diagKey is the part of a diagnostic the tests compare.
+type diagKey struct {
+	Code    types.DiagnosticCode
+	Pointer string
+}
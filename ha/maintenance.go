@@ -0,0 +1,257 @@
+package ha
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"slices"
+	"time"
+
+	"github.com/rannday/kea-api/client"
+	"github.com/rannday/kea-api/types"
+)
+
+// Defaults of the maintenance options.
+const (
+	DefaultPollInterval      = time.Second
+	DefaultTransitionTimeout = time.Minute
+)
+
+// MaintenancePhase is a step of Pair.Maintain.
+type MaintenancePhase string
+
+// Phases of Pair.Maintain, in the order they run.
+const (
+	PhasePreflight MaintenancePhase = "preflight" // both servers are observed in their normal state
+	PhaseStart     MaintenancePhase = "start"     // ha-maintenance-start is sent and the transition awaited
+	PhaseWork      MaintenancePhase = "work"      // the target is in maintenance and the work runs
+	PhaseCancel    MaintenancePhase = "cancel"    // ha-maintenance-cancel is sent if the partner still expects it
+	PhaseRecover   MaintenancePhase = "recover"   // both servers are awaited back in their normal state
+)
+
+// MaintenanceError is returned by Pair.Maintain. Phase is the phase that failed and Err
+// the reason. If maintenance had started, Maintain cancelled it; RollbackErr is set
+// when that failed too, in which case the servers may need attention.
+type MaintenanceError struct {
+	Phase       MaintenancePhase
+	Err         error
+	RollbackErr error
+}
+
+func (e *MaintenanceError) Error() string {
+	msg := fmt.Sprintf("ha maintenance %s: %v", e.Phase, e.Err)
+	if e.RollbackErr != nil {
+		msg += fmt.Sprintf(" (rollback failed: %v)", e.RollbackErr)
+	}
+	return msg
+}
+
+// Unwrap returns Err and RollbackErr.
+func (e *MaintenanceError) Unwrap() []error {
+	if e.RollbackErr != nil {
+		return []error{e.Err, e.RollbackErr}
+	}
+	return []error{e.Err}
+}
+
+// MaintenanceOption configures Pair.Maintain.
+type MaintenanceOption func(*maintenance)
+
+// WithPollInterval sets how often the servers are observed while a transition is
+// awaited; DefaultPollInterval if not set.
+func WithPollInterval(d time.Duration) MaintenanceOption {
+	return func(m *maintenance) {
+		if d > 0 {
+			m.poll = d
+		}
+	}
+}
+
+// WithTransitionTimeout bounds the wait for each transition, including the recovery of
+// a target that was restarted during the work; DefaultTransitionTimeout if not set.
+func WithTransitionTimeout(d time.Duration) MaintenanceOption {
+	return func(m *maintenance) {
+		if d > 0 {
+			m.timeout = d
+		}
+	}
+}
+
+// WithProgress calls fn as each phase begins, with the topology observed last.
+func WithProgress(fn func(phase MaintenancePhase, topo Topology)) MaintenanceOption {
+	return func(m *maintenance) {
+		m.progress = fn
+	}
+}
+
+// maintenance is the state of one Pair.Maintain run.
+type maintenance struct {
+	pair     *Pair
+	target   int
+	poll     time.Duration
+	timeout  time.Duration
+	progress func(MaintenancePhase, Topology)
+	normal   [2]types.HAState // states observed in preflight
+}
+
+// Maintain takes peer target (0 or 1) out of service, runs work, and brings it back:
+//
+//   - preflight: both servers must be reachable and in load-balancing or hot-standby
+//   - start: ha-maintenance-start is sent to the partner, which then serves all clients
+//     in partner-in-maintenance while the target waits in in-maintenance
+//   - work: work runs; it may stop or restart the target's Kea
+//   - cancel: ha-maintenance-cancel is sent to the partner, unless the target was
+//     stopped meanwhile and the partner moved on to partner-down
+//   - recover: both servers must return to the states seen in preflight
+//
+// Each transition is verified with status-get and must complete within the transition
+// timeout. If the start or the work fails, or ctx is cancelled, maintenance is
+// cancelled before returning, even though ctx is done. The error is a
+// *MaintenanceError.
+func (p *Pair) Maintain(ctx context.Context, target int, work func(ctx context.Context) error, opts ...MaintenanceOption) error {
+	if target != 0 && target != 1 {
+		return &MaintenanceError{Phase: PhasePreflight, Err: fmt.Errorf("invalid peer index %d", target)}
+	}
+	m := &maintenance{
+		pair:    p,
+		target:  target,
+		poll:    DefaultPollInterval,
+		timeout: DefaultTransitionTimeout,
+	}
+	for _, opt := range opts {
+		opt(m)
+	}
+	return m.run(ctx, work)
+}
+
+func (m *maintenance) run(ctx context.Context, work func(ctx context.Context) error) error {
+	target, partner := m.target, 1-m.target
+
+	topo, err := m.pair.Refresh(ctx)
+	m.report(PhasePreflight, topo)
+	if err == nil {
+		err = m.preflight(topo)
+	}
+	if err != nil {
+		return &MaintenanceError{Phase: PhasePreflight, Err: err}
+	}
+	m.normal = [2]types.HAState{topo.Peers[0].State, topo.Peers[1].State}
+
+	m.report(PhaseStart, topo)
+	err = client.HAMaintenanceStartContext(ctx, m.pair.PeerClient(partner), m.pair.service, m.pair.peers[target].Name)
+	if err == nil {
+		topo, err = m.await(ctx, "maintenance to start", func(t Topology) bool {
+			return t.Peers[partner].State == types.HAStatePartnerInMaintenance &&
+				t.Peers[target].State == types.HAStateInMaintenance
+		})
+	}
+	if err != nil {
+		return m.fail(ctx, PhaseStart, err)
+	}
+
+	m.report(PhaseWork, topo)
+	if err := work(ctx); err != nil {
+		return m.fail(ctx, PhaseWork, err)
+	}
+	if err := ctx.Err(); err != nil {
+		return m.fail(ctx, PhaseWork, err)
+	}
+
+	if err := m.finish(ctx); err != nil {
+		var me *MaintenanceError
+		if errors.As(err, &me) {
+			return err
+		}
+		return &MaintenanceError{Phase: PhaseRecover, Err: err}
+	}
+	return nil
+}
+
+// preflight checks that maintenance can start from topo.
+func (m *maintenance) preflight(topo Topology) error {
+	for i, s := range topo.Peers {
+		if s.Err != nil {
+			return fmt.Errorf("%s is unreachable: %w", peerLabel(m.pair.peers[i], i), s.Err)
+		}
+		if !slices.Contains([]types.HAState{types.HAStateLoadBalancing, types.HAStateHotStandby}, s.State) {
+			return fmt.Errorf("%s is in state %s, want load-balancing or hot-standby", peerLabel(m.pair.peers[i], i), s.State)
+		}
+	}
+	return nil
+}
+
+// finish cancels maintenance if the partner still expects it and waits for both
+// servers to return to their normal states.
+func (m *maintenance) finish(ctx context.Context) error {
+	partner := 1 - m.target
+
+	topo, err := m.pair.Refresh(ctx)
+	m.report(PhaseCancel, topo)
+	if err == nil && topo.Peers[partner].State == types.HAStatePartnerInMaintenance {
+		err = client.HAMaintenanceCancelContext(ctx, m.pair.PeerClient(partner), m.pair.service, m.pair.peers[m.target].Name)
+	}
+	if err != nil {
+		return &MaintenanceError{Phase: PhaseCancel, Err: err}
+	}
+
+	m.report(PhaseRecover, topo)
+	_, err = m.await(ctx, "servers to recover", func(t Topology) bool {
+		return t.Peers[0].State == m.normal[0] && t.Peers[1].State == m.normal[1]
+	})
+	return err
+}
+
+// fail cancels maintenance after phase failed with err. The rollback runs even if ctx
+// is done, bounded by the transition timeout.
+func (m *maintenance) fail(ctx context.Context, phase MaintenancePhase, err error) error {
+	rollbackErr := m.finish(context.WithoutCancel(ctx))
+	var me *MaintenanceError
+	if errors.As(rollbackErr, &me) {
+		rollbackErr = me.Err
+	}
+	return &MaintenanceError{Phase: phase, Err: err, RollbackErr: rollbackErr}
+}
+
+// await observes the servers until done reports true, for at most the transition timeout.
+func (m *maintenance) await(ctx context.Context, what string, done func(Topology) bool) (Topology, error) {
+	ctx, cancel := context.WithTimeout(ctx, m.timeout)
+	defer cancel()
+
+	ticker := time.NewTicker(m.poll)
+	defer ticker.Stop()
+	for {
+		topo, err := m.pair.Refresh(ctx)
+		if err == nil && done(topo) {
+			return topo, nil
+		}
+		select {
+		case <-ctx.Done():
+			return topo, fmt.Errorf("waiting for %s: %w (last seen %s)", what, ctx.Err(), describe(topo))
+		case <-ticker.C:
+		}
+	}
+}
+
+// report calls the progress callback, if any.
+func (m *maintenance) report(phase MaintenancePhase, topo Topology) {
+	if m.progress != nil {
+		m.progress(phase, topo)
+	}
+}
+
+// describe summarizes the states of topo for errors.
+func describe(topo Topology) string {
+	var parts [2]string
+	for i, s := range topo.Peers {
+		name := s.Name
+		if name == "" {
+			name = fmt.Sprintf("peer %d", i)
+		}
+		if s.Err != nil {
+			parts[i] = name + " unreachable"
+		} else {
+			parts[i] = fmt.Sprintf("%s %s", name, s.State)
+		}
+	}
+	return parts[0] + ", " + parts[1]
+}
@@ -0,0 +1,205 @@
+package ha
+
+import (
+	"context"
+	"errors"
+	"slices"
+	"testing"
+	"time"
+
+	"github.com/rannday/kea-api/client"
+	"github.com/rannday/kea-api/types"
+)
+
+// fastMaintenance are options short enough for tests.
+var fastMaintenance = []MaintenanceOption{WithPollInterval(5 * time.Millisecond), WithTransitionTimeout(500 * time.Millisecond)}
+
+// maintenanceHandler makes the servers of h move through the maintenance states the
+// way Kea does. With stuck, ha-maintenance-start is accepted but nothing changes.
+func maintenanceHandler(stuck bool) func(h *fakeHA, i int, cmd string) (client.CommandResponse, bool) {
+	return func(h *fakeHA, i int, cmd string) (client.CommandResponse, bool) {
+		local, remote := h.peers[i], h.peers[1-i]
+		switch cmd {
+		case "ha-maintenance-start":
+			if !stuck {
+				local.state, remote.state = types.HAStatePartnerInMaintenance, types.HAStateInMaintenance
+			}
+		case "ha-maintenance-cancel":
+			if local.state != types.HAStatePartnerInMaintenance {
+				return client.CommandResponse{Result: client.ResultGeneralFailure, Text: "Unable to cancel the maintenance for the server not in the partner-in-maintenance state."}, true
+			}
+			local.state, remote.state = types.HAState(h.mode), types.HAState(h.mode)
+		default:
+			return client.CommandResponse{}, false
+		}
+		return client.CommandResponse{Result: client.ResultSuccess, Text: local.name}, true
+	}
+}
+
+// setStates sets the states of both servers of h.
+func (h *fakeHA) setStates(s0, s1 types.HAState) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	h.peers[0].state, h.peers[1].state = s0, s1
+}
+
+// states returns the states of both servers of h.
+func (h *fakeHA) states() [2]types.HAState {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	return [2]types.HAState{h.peers[0].state, h.peers[1].state}
+}
+
+// TestMaintain runs the whole sequence and routes commands to the partner meanwhile.
+func TestMaintain(t *testing.T) {
+	t.Parallel()
+
+	h := newFakeHA(t, "hot-standby", types.HARolePrimary, types.HAStateHotStandby, types.HARoleStandby, types.HAStateHotStandby)
+	h.handle = maintenanceHandler(false)
+	p := h.pair()
+
+	var phases []MaintenancePhase
+	opts := append(slices.Clone(fastMaintenance), WithProgress(func(phase MaintenancePhase, topo Topology) {
+		phases = append(phases, phase)
+	}))
+	err := p.Maintain(context.Background(), 0, func(ctx context.Context) error {
+		if got := h.states(); got != [2]types.HAState{types.HAStateInMaintenance, types.HAStatePartnerInMaintenance} {
+			t.Errorf("states during work = %v", got)
+		}
+		if _, err := client.CallCommand(p.Client(), "lease4-add", client.Services.DHCP4); err != nil {
+			t.Errorf("lease4-add during work: %v", err)
+		}
+		return nil
+	}, opts...)
+	if err != nil {
+		t.Fatalf("Maintain() error = %v", err)
+	}
+
+	if got := h.states(); got != [2]types.HAState{types.HAStateHotStandby, types.HAStateHotStandby} {
+		t.Errorf("states after Maintain() = %v", got)
+	}
+	want := []MaintenancePhase{PhasePreflight, PhaseStart, PhaseWork, PhaseCancel, PhaseRecover}
+	if !slices.Equal(phases, want) {
+		t.Errorf("phases = %v, want %v", phases, want)
+	}
+	got := h.commands(1)
+	if !slices.Contains(got, "ha-maintenance-start") || !slices.Contains(got, "ha-maintenance-cancel") || !slices.Contains(got, "lease4-add") {
+		t.Errorf("partner received %v", got)
+	}
+	if slices.Contains(h.commands(0), "lease4-add") {
+		t.Errorf("target in maintenance received lease4-add: %v", h.commands(0))
+	}
+}
+
+// TestMaintain_WorkFails cancels maintenance and reports the work's error.
+func TestMaintain_WorkFails(t *testing.T) {
+	t.Parallel()
+
+	h := newFakeHA(t, "load-balancing", types.HARolePrimary, types.HAStateLoadBalancing, types.HARoleSecondary, types.HAStateLoadBalancing)
+	h.handle = maintenanceHandler(false)
+
+	boom := errors.New("upgrade failed")
+	err := h.pair().Maintain(context.Background(), 1, func(ctx context.Context) error { return boom }, fastMaintenance...)
+	var me *MaintenanceError
+	if !errors.As(err, &me) || me.Phase != PhaseWork || !errors.Is(err, boom) || me.RollbackErr != nil {
+		t.Fatalf("Maintain() error = %v, want the work's error with a clean rollback", err)
+	}
+	if got := h.states(); got != [2]types.HAState{types.HAStateLoadBalancing, types.HAStateLoadBalancing} {
+		t.Errorf("states after rollback = %v", got)
+	}
+	if !slices.Contains(h.commands(0), "ha-maintenance-cancel") {
+		t.Errorf("partner received %v, want ha-maintenance-cancel", h.commands(0))
+	}
+}
+
+// TestMaintain_StartTimeout gives up when the servers do not enter maintenance.
+func TestMaintain_StartTimeout(t *testing.T) {
+	t.Parallel()
+
+	h := newFakeHA(t, "hot-standby", types.HARolePrimary, types.HAStateHotStandby, types.HARoleStandby, types.HAStateHotStandby)
+	h.handle = maintenanceHandler(true)
+
+	worked := false
+	err := h.pair().Maintain(context.Background(), 1, func(ctx context.Context) error {
+		worked = true
+		return nil
+	}, WithPollInterval(5*time.Millisecond), WithTransitionTimeout(50*time.Millisecond))
+
+	var me *MaintenanceError
+	if !errors.As(err, &me) || me.Phase != PhaseStart || !errors.Is(err, context.DeadlineExceeded) {
+		t.Fatalf("Maintain() error = %v, want a start timeout", err)
+	}
+	if worked {
+		t.Error("work ran although maintenance never started")
+	}
+	if slices.Contains(h.commands(0), "ha-maintenance-cancel") {
+		t.Errorf("partner received %v, want no cancel outside partner-in-maintenance", h.commands(0))
+	}
+}
+
+// TestMaintain_Preflight refuses to start unless both servers are in their normal state.
+func TestMaintain_Preflight(t *testing.T) {
+	t.Parallel()
+
+	h := newFakeHA(t, "hot-standby", types.HARolePrimary, types.HAStatePartnerDown, types.HARoleStandby, types.HAStateWaiting)
+	h.handle = maintenanceHandler(false)
+
+	err := h.pair().Maintain(context.Background(), 1, func(ctx context.Context) error { return nil }, fastMaintenance...)
+	var me *MaintenanceError
+	if !errors.As(err, &me) || me.Phase != PhasePreflight {
+		t.Fatalf("Maintain() error = %v, want a preflight failure", err)
+	}
+	for i := range 2 {
+		if got := h.commands(i); slices.Contains(got, "ha-maintenance-start") {
+			t.Errorf("server %d received %v", i, got)
+		}
+	}
+
+	if err := h.pair().Maintain(context.Background(), 2, nil); err == nil {
+		t.Error("Maintain() accepted peer index 2")
+	}
+}
+
+// TestMaintain_TargetRestarted waits for recovery without cancelling when the target
+// was stopped during the work and the partner moved on to partner-down.
+func TestMaintain_TargetRestarted(t *testing.T) {
+	t.Parallel()
+
+	h := newFakeHA(t, "hot-standby", types.HARolePrimary, types.HAStateHotStandby, types.HARoleStandby, types.HAStateHotStandby)
+	h.handle = maintenanceHandler(false)
+
+	err := h.pair().Maintain(context.Background(), 1, func(ctx context.Context) error {
+		h.setStates(types.HAStatePartnerDown, types.HAStateWaiting)
+		time.AfterFunc(30*time.Millisecond, func() {
+			h.setStates(types.HAStateHotStandby, types.HAStateHotStandby)
+		})
+		return nil
+	}, fastMaintenance...)
+	if err != nil {
+		t.Fatalf("Maintain() error = %v", err)
+	}
+	if slices.Contains(h.commands(0), "ha-maintenance-cancel") {
+		t.Errorf("partner received %v, want no cancel after partner-down", h.commands(0))
+	}
+}
+
+// TestMaintain_Cancelled rolls back when ctx is cancelled during the work.
+func TestMaintain_Cancelled(t *testing.T) {
+	t.Parallel()
+
+	h := newFakeHA(t, "hot-standby", types.HARolePrimary, types.HAStateHotStandby, types.HARoleStandby, types.HAStateHotStandby)
+	h.handle = maintenanceHandler(false)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	err := h.pair().Maintain(ctx, 1, func(ctx context.Context) error {
+		cancel()
+		return nil
+	}, fastMaintenance...)
+	var me *MaintenanceError
+	if !errors.As(err, &me) || me.Phase != PhaseWork || !errors.Is(err, context.Canceled) || me.RollbackErr != nil {
+		t.Fatalf("Maintain() error = %v, want a cancelled work phase with a clean rollback", err)
+	}
+	if got := h.states(); got != [2]types.HAState{types.HAStateHotStandby, types.HAStateHotStandby} {
+		t.Errorf("states after rollback = %v", got)
+	}
+}
@@ -0,0 +1,266 @@
+// Package ha drives pairs of Kea DHCP servers running the High Availability hook
+// library: a Pair routes commands to whichever server is currently serving clients,
+// and Pair.Maintain takes one of them out of service and back.
+package ha
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"sync"
+	"time"
+
+	"github.com/rannday/kea-api/client"
+	"github.com/rannday/kea-api/types"
+)
+
+// DefaultMaxAge is how long a Pair trusts the topology it learned before asking the
+// servers again.
+const DefaultMaxAge = 30 * time.Second
+
+// Peer is one server of an HA pair.
+type Peer struct {
+	Name      string           // server-name of the peer in the HA configuration; may be empty if neither peer is in hub-and-spoke mode
+	Transport client.Transport // reaches the peer, typically through its Control Agent
+}
+
+// PeerState is what a Pair last learned about a peer from its status-get.
+type PeerState struct {
+	Name   string
+	Role   types.HARole
+	State  types.HAState
+	Scopes []string
+	Err    error // why the peer's status could not be read; the fields above are then zero
+}
+
+// Topology is the state of a pair as last observed.
+type Topology struct {
+	Mode    string       // "load-balancing", "hot-standby" or "passive-backup"
+	Peers   [2]PeerState // in the order given to NewPair
+	Active  int          // index of the peer that receives commands
+	Updated time.Time    // zero until the pair has been observed
+}
+
+// ActivePeer returns the state of the peer that receives commands.
+func (t Topology) ActivePeer() PeerState {
+	return t.Peers[t.Active]
+}
+
+// Pair is a client.Transport for the two servers of an HA pair. It learns the role and
+// state of both servers from status-get and sends every command to the one serving
+// clients: the primary in hot-standby and passive-backup mode, preferably the primary
+// in load-balancing mode, and the survivor when one server is in partner-down or
+// partner-in-maintenance.
+//
+// If the chosen server cannot be reached, the command is sent to its partner instead.
+// Read-only commands (see client.IsReadOnlyCommand) fail over on any transport
+// failure; other commands only when the connection could not be opened, so a write
+// that may have reached one server is never repeated on the other. A failover makes
+// the pair observe the servers again before the next command.
+type Pair struct {
+	service client.Service
+	peers   [2]Peer
+	maxAge  time.Duration
+
+	mu   sync.Mutex
+	topo Topology
+}
+
+// PairOption configures a Pair.
+type PairOption func(*Pair)
+
+// WithMaxAge sets how long the learned topology is trusted; DefaultMaxAge if not set.
+func WithMaxAge(d time.Duration) PairOption {
+	return func(p *Pair) {
+		if d > 0 {
+			p.maxAge = d
+		}
+	}
+}
+
+// NewPair returns a Pair of the servers a and b running service, which must be
+// client.Services.DHCP4 or client.Services.DHCP6. Until the servers have been
+// observed, a is assumed to be the active one.
+func NewPair(service client.Service, a, b Peer, opts ...PairOption) *Pair {
+	p := &Pair{
+		service: service,
+		peers:   [2]Peer{a, b},
+		maxAge:  DefaultMaxAge,
+	}
+	for _, opt := range opts {
+		opt(p)
+	}
+	return p
+}
+
+// Client returns a client sending its commands through the pair.
+func (p *Pair) Client(opts ...client.ClientOption) *client.Client {
+	return client.NewClient(p, opts...)
+}
+
+// PeerClient returns a client talking to peer i (0 or 1) only.
+func (p *Pair) PeerClient(i int) *client.Client {
+	return client.NewClient(p.peers[i].Transport)
+}
+
+// Service returns the service the pair runs.
+func (p *Pair) Service() client.Service {
+	return p.service
+}
+
+// Topology returns the topology as last observed, without contacting the servers.
+func (p *Pair) Topology() Topology {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	return p.topo
+}
+
+// Refresh observes both servers and returns the resulting topology. The error is
+// non-nil only when neither server could be observed.
+func (p *Pair) Refresh(ctx context.Context) (Topology, error) {
+	var topo Topology
+	var modes [2]string
+	var wg sync.WaitGroup
+	for i := range p.peers {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			topo.Peers[i], modes[i] = p.observe(ctx, i)
+		}()
+	}
+	wg.Wait()
+
+	for _, mode := range modes {
+		if mode != "" {
+			topo.Mode = mode
+		}
+	}
+	topo.Active = activePeer(topo.Peers)
+	topo.Updated = time.Now()
+
+	p.mu.Lock()
+	p.topo = topo
+	p.mu.Unlock()
+
+	if topo.Peers[0].Err != nil && topo.Peers[1].Err != nil {
+		return topo, errors.Join(topo.Peers[0].Err, topo.Peers[1].Err)
+	}
+	return topo, nil
+}
+
+// observe reads the state of peer i, and the mode of its relationship with the other
+// peer, from its status-get.
+func (p *Pair) observe(ctx context.Context, i int) (PeerState, string) {
+	peer, partner := p.peers[i], p.peers[1-i]
+	state := PeerState{Name: peer.Name}
+
+	statuses, err := client.HAStatusGetContext(ctx, p.PeerClient(i), p.service)
+	if err != nil {
+		state.Err = err
+		return state, ""
+	}
+	rel, ok := types.HARelationship(statuses, partner.Name)
+	if !ok {
+		state.Err = fmt.Errorf("%s: no HA relationship with %q", peerLabel(peer, i), partner.Name)
+		return state, ""
+	}
+
+	local := rel.Servers.Local
+	if state.Name == "" {
+		state.Name = local.ServerName
+	}
+	state.Role, state.State, state.Scopes = local.Role, local.State, local.Scopes
+	return state, rel.Mode
+}
+
+// Call implements client.Transport.
+func (p *Pair) Call(req client.CommandRequest, out interface{}) error {
+	return p.CallContext(context.Background(), req, out)
+}
+
+// CallContext implements client.ContextTransport. It observes the servers first when the
+// topology is unknown or older than the pair's maximum age.
+func (p *Pair) CallContext(ctx context.Context, req client.CommandRequest, out interface{}) error {
+	topo := p.Topology()
+	if topo.Updated.IsZero() || time.Since(topo.Updated) > p.maxAge {
+		topo, _ = p.Refresh(ctx)
+	}
+
+	readOnly := client.IsReadOnlyCommand(req.Command)
+	var err error
+	for _, i := range []int{topo.Active, 1 - topo.Active} {
+		err = p.PeerClient(i).CallContext(ctx, req, out)
+		if err == nil || ctx.Err() != nil || !canFailOver(err, readOnly) {
+			return err
+		}
+		p.invalidate()
+	}
+	return err
+}
+
+// invalidate makes the next command observe the servers again.
+func (p *Pair) invalidate() {
+	p.mu.Lock()
+	p.topo.Updated = time.Time{}
+	p.mu.Unlock()
+}
+
+// canFailOver reports whether a command that failed with err may be sent to the partner.
+func canFailOver(err error, readOnly bool) bool {
+	if client.IsConnectError(err) {
+		return true
+	}
+	if !readOnly {
+		return false
+	}
+	var trErr *client.TransportError
+	var statusErr *client.HTTPStatusError
+	switch {
+	case errors.As(err, &trErr):
+		return true
+	case errors.As(err, &statusErr):
+		return statusErr.StatusCode == http.StatusBadGateway ||
+			statusErr.StatusCode == http.StatusServiceUnavailable ||
+			statusErr.StatusCode == http.StatusGatewayTimeout
+	}
+	return false
+}
+
+// activePeer returns the index of the peer that should receive commands. Ties go to
+// the first peer.
+func activePeer(peers [2]PeerState) int {
+	if servingRank(peers[1]) > servingRank(peers[0]) {
+		return 1
+	}
+	return 0
+}
+
+// servingRank orders peers by how suitable they are to receive commands.
+func servingRank(s PeerState) int {
+	if s.Err != nil {
+		return -1
+	}
+	switch s.State {
+	case types.HAStatePartnerDown, types.HAStatePartnerInMaintenance:
+		return 3 // the only server serving clients
+	case types.HAStateHotStandby, types.HAStatePassiveBackup:
+		if s.Role == types.HARolePrimary {
+			return 2
+		}
+	case types.HAStateLoadBalancing:
+		if s.Role == types.HARolePrimary {
+			return 2
+		}
+		return 1
+	}
+	return 0
+}
+
+// peerLabel names peer i in errors.
+func peerLabel(peer Peer, i int) string {
+	if peer.Name != "" {
+		return peer.Name
+	}
+	return fmt.Sprintf("peer %d", i)
+}
@@ -0,0 +1,268 @@
+package ha
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"slices"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/rannday/kea-api/client"
+	"github.com/rannday/kea-api/dhcp4"
+	"github.com/rannday/kea-api/types"
+)
+
+// fakeServer is one Control Agent of a fakeHA pair.
+type fakeServer struct {
+	name     string
+	role     types.HARole
+	state    types.HAState
+	srv      *httptest.Server
+	commands []string       // commands received, in order
+	fail     map[string]int // commands answered with this HTTP status instead
+}
+
+// fakeHA is a pair of Control Agents fronting kea-dhcp4 servers in an HA relationship.
+// Commands that handle does not answer succeed with the name of the server as text,
+// and status-get reports the roles and states of the two servers.
+type fakeHA struct {
+	mode   string
+	mu     sync.Mutex
+	peers  [2]*fakeServer
+	handle func(h *fakeHA, i int, cmd string) (client.CommandResponse, bool) // called with mu held
+}
+
+// newFakeHA starts a pair of servers named server1 and server2.
+func newFakeHA(t *testing.T, mode string, role1 types.HARole, state1 types.HAState, role2 types.HARole, state2 types.HAState) *fakeHA {
+	t.Helper()
+	h := &fakeHA{mode: mode, peers: [2]*fakeServer{
+		{name: "server1", role: role1, state: state1, fail: map[string]int{}},
+		{name: "server2", role: role2, state: state2, fail: map[string]int{}},
+	}}
+	for i, s := range h.peers {
+		s.srv = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			h.serve(t, i, w, r)
+		}))
+		t.Cleanup(s.srv.Close)
+	}
+	return h
+}
+
+func (h *fakeHA) serve(t *testing.T, i int, w http.ResponseWriter, r *http.Request) {
+	var req client.CommandRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		t.Errorf("failed to decode request: %v", err)
+		http.Error(w, "bad request", http.StatusBadRequest)
+		return
+	}
+	if len(req.Service) != 1 || req.Service[0] != client.Services.DHCP4 {
+		t.Errorf("%s sent to %v", req.Command, req.Service)
+	}
+
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	local, remote := h.peers[i], h.peers[1-i]
+	local.commands = append(local.commands, req.Command)
+	if status, ok := local.fail[req.Command]; ok {
+		http.Error(w, "failed", status)
+		return
+	}
+
+	resp := client.CommandResponse{Result: client.ResultSuccess, Text: local.name}
+	if r, ok := h.answer(i, req.Command); ok {
+		resp = r
+	} else if req.Command == "status-get" {
+		status := types.HAStatus{Mode: h.mode, Servers: types.HAServers{
+			Local:  types.HALocalServer{ServerName: local.name, Role: local.role, State: local.state},
+			Remote: types.HARemoteServer{ServerName: remote.name, Role: remote.role, LastState: remote.state, InTouch: true},
+		}}
+		resp.Arguments, _ = json.Marshal(map[string]any{"pid": 1, "high-availability": []types.HAStatus{status}})
+	}
+	json.NewEncoder(w).Encode([]client.CommandResponse{resp})
+}
+
+// answer lets handle answer a command, if set.
+func (h *fakeHA) answer(i int, cmd string) (client.CommandResponse, bool) {
+	if h.handle == nil {
+		return client.CommandResponse{}, false
+	}
+	return h.handle(h, i, cmd)
+}
+
+// pair returns a Pair of the two servers.
+func (h *fakeHA) pair(opts ...PairOption) *Pair {
+	return NewPair(client.Services.DHCP4,
+		Peer{Name: "server1", Transport: client.NewHTTPTransport(h.peers[0].srv.URL)},
+		Peer{Name: "server2", Transport: client.NewHTTPTransport(h.peers[1].srv.URL)},
+		opts...,
+	)
+}
+
+// commands returns the commands server i received.
+func (h *fakeHA) commands(i int) []string {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	return slices.Clone(h.peers[i].commands)
+}
+
+// lease is a lease for the write commands of the tests.
+var lease = dhcp4.Lease4{IPAddress: "192.0.2.10", HWAddress: "00:11:22:33:44:55", SubnetID: 1}
+
+// TestPair_HotStandby sends commands to the primary and reports the topology.
+func TestPair_HotStandby(t *testing.T) {
+	t.Parallel()
+
+	h := newFakeHA(t, "hot-standby", types.HARoleStandby, types.HAStateHotStandby, types.HARolePrimary, types.HAStateHotStandby)
+	p := h.pair()
+
+	if err := dhcp4.Lease4Add(p.Client(), lease); err != nil {
+		t.Fatalf("Lease4Add() error = %v", err)
+	}
+	if got := h.commands(1); !slices.Equal(got, []string{"status-get", "lease4-add"}) {
+		t.Errorf("primary received %v", got)
+	}
+	if got := h.commands(0); !slices.Equal(got, []string{"status-get"}) {
+		t.Errorf("standby received %v", got)
+	}
+
+	topo := p.Topology()
+	if topo.Mode != "hot-standby" || topo.Active != 1 || topo.Updated.IsZero() {
+		t.Errorf("Topology() = %+v", topo)
+	}
+	want := [2]PeerState{
+		{Name: "server1", Role: types.HARoleStandby, State: types.HAStateHotStandby},
+		{Name: "server2", Role: types.HARolePrimary, State: types.HAStateHotStandby},
+	}
+	if !reflect.DeepEqual(topo.Peers, want) {
+		t.Errorf("Topology().Peers = %+v, want %+v", topo.Peers, want)
+	}
+	if topo.ActivePeer().Name != "server2" {
+		t.Errorf("ActivePeer() = %+v", topo.ActivePeer())
+	}
+}
+
+// TestPair_MaxAge observes the servers again only once the topology is too old.
+func TestPair_MaxAge(t *testing.T) {
+	t.Parallel()
+
+	h := newFakeHA(t, "load-balancing", types.HARolePrimary, types.HAStateLoadBalancing, types.HARoleSecondary, types.HAStateLoadBalancing)
+	p := h.pair(WithMaxAge(time.Hour))
+	c := p.Client()
+
+	for range 3 {
+		if _, err := dhcp4.StatusGet(c); err != nil {
+			t.Fatalf("StatusGet() error = %v", err)
+		}
+	}
+	if got := h.commands(0); !slices.Equal(got, []string{"status-get", "status-get", "status-get", "status-get"}) {
+		t.Errorf("primary received %v, want one observation and three commands", got)
+	}
+	if got := h.commands(1); len(got) != 1 {
+		t.Errorf("secondary received %v, want one observation", got)
+	}
+
+	if _, err := p.Refresh(context.Background()); err != nil {
+		t.Fatalf("Refresh() error = %v", err)
+	}
+	if got := h.commands(1); len(got) != 2 {
+		t.Errorf("secondary received %v after Refresh", got)
+	}
+}
+
+// TestPair_Failover sends commands to the partner when the active server is unreachable.
+func TestPair_Failover(t *testing.T) {
+	t.Parallel()
+
+	h := newFakeHA(t, "hot-standby", types.HARolePrimary, types.HAStateHotStandby, types.HARoleStandby, types.HAStateHotStandby)
+	p := h.pair()
+	c := p.Client()
+
+	if _, err := dhcp4.StatusGet(c); err != nil {
+		t.Fatalf("StatusGet() error = %v", err)
+	}
+	if p.Topology().Active != 0 {
+		t.Fatalf("Topology() = %+v, want the primary active", p.Topology())
+	}
+
+	h.peers[0].srv.Close()
+	if err := dhcp4.Lease4Add(c, lease); err != nil {
+		t.Fatalf("Lease4Add() error = %v", err)
+	}
+	if got := h.commands(1); !slices.Contains(got, "lease4-add") {
+		t.Errorf("standby received %v, want the failed-over lease4-add", got)
+	}
+	if !p.Topology().Updated.IsZero() {
+		t.Error("failover did not invalidate the topology")
+	}
+
+	if _, err := dhcp4.StatusGet(c); err != nil {
+		t.Fatalf("StatusGet() error = %v", err)
+	}
+	topo := p.Topology()
+	if topo.Active != 1 || topo.Peers[0].Err == nil || !client.IsConnectError(topo.Peers[0].Err) {
+		t.Errorf("Topology() = %+v, want the unreachable primary replaced by the standby", topo)
+	}
+
+	h.peers[1].srv.Close()
+	if _, err := p.Refresh(context.Background()); err == nil {
+		t.Error("Refresh() succeeded with both servers down")
+	}
+}
+
+// TestPair_NoFailoverAfterDelivery keeps writes that may have reached the active server
+// on it, while reads fail over.
+func TestPair_NoFailoverAfterDelivery(t *testing.T) {
+	t.Parallel()
+
+	h := newFakeHA(t, "hot-standby", types.HARolePrimary, types.HAStateHotStandby, types.HARoleStandby, types.HAStateHotStandby)
+	h.peers[0].fail["lease4-add"] = http.StatusServiceUnavailable
+	h.peers[0].fail["lease4-get"] = http.StatusServiceUnavailable
+	c := h.pair().Client()
+
+	err := dhcp4.Lease4Add(c, lease)
+	var statusErr *client.HTTPStatusError
+	if !errors.As(err, &statusErr) || statusErr.StatusCode != http.StatusServiceUnavailable {
+		t.Errorf("Lease4Add() error = %v, want the primary's 503", err)
+	}
+	if got := h.commands(1); slices.Contains(got, "lease4-add") {
+		t.Errorf("lease4-add was repeated on the standby: %v", got)
+	}
+
+	if _, err := client.CallWithArgs(c, "lease4-get", map[string]any{"ip-address": "192.0.2.10"}, client.Services.DHCP4); err != nil {
+		t.Errorf("lease4-get error = %v, want the standby's answer", err)
+	}
+	if got := h.commands(1); !slices.Contains(got, "lease4-get") {
+		t.Errorf("standby received %v, want the failed-over lease4-get", got)
+	}
+}
+
+// TestActivePeer prefers the server serving clients on its own, then the primary.
+func TestActivePeer(t *testing.T) {
+	t.Parallel()
+
+	peer := func(role types.HARole, state types.HAState) PeerState { return PeerState{Role: role, State: state} }
+	down := PeerState{Err: errors.New("connect: connection refused")}
+	tests := []struct {
+		name  string
+		peers [2]PeerState
+		want  int
+	}{
+		{"unknown", [2]PeerState{}, 0},
+		{"hot-standby", [2]PeerState{peer(types.HARoleStandby, types.HAStateHotStandby), peer(types.HARolePrimary, types.HAStateHotStandby)}, 1},
+		{"load-balancing", [2]PeerState{peer(types.HARoleSecondary, types.HAStateLoadBalancing), peer(types.HARolePrimary, types.HAStateLoadBalancing)}, 1},
+		{"partner-down", [2]PeerState{peer(types.HARolePrimary, types.HAStateWaiting), peer(types.HARoleStandby, types.HAStatePartnerDown)}, 1},
+		{"maintenance", [2]PeerState{peer(types.HARolePrimary, types.HAStateInMaintenance), peer(types.HARoleSecondary, types.HAStatePartnerInMaintenance)}, 1},
+		{"passive-backup", [2]PeerState{peer(types.HARolePrimary, types.HAStatePassiveBackup), peer(types.HARoleBackup, types.HAStateBackup)}, 0},
+		{"unreachable", [2]PeerState{down, peer(types.HARoleStandby, types.HAStateWaiting)}, 1},
+	}
+	for _, tt := range tests {
+		if got := activePeer(tt.peers); got != tt.want {
+			t.Errorf("%s: activePeer() = %d, want %d", tt.name, got, tt.want)
+		}
+	}
+}
@@ -2,10 +2,14 @@ package testenv
 
 import (
 	"encoding/json"
+	"net"
 	"net/http"
 	"net/http/httptest"
 	"os"
+	"reflect"
+	"sync"
 	"testing"
+	"time"
 
 	"github.com/rannday/kea-api/client"
 )
@@ -51,6 +55,122 @@ func NewMockClient(
 	return client.NewHTTP(server.URL)
 }
 
+// MockStep is one expected request and its canned responses in a NewSequenceMockClient exchange.
+type MockStep struct {
+	Validate  func(t *testing.T, req client.CommandRequest)
+	Responses []client.CommandResponse
+}
+
+// NewSequenceMockClient returns a mock *client.Client that answers successive
+// requests with successive steps. Requests beyond the last step fail the test.
+func NewSequenceMockClient(t *testing.T, steps ...MockStep) *client.Client {
+	t.Helper()
+
+	var mu sync.Mutex
+	next := 0
+
+	handler := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var req client.CommandRequest
+		if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+			t.Errorf("failed to decode request: %v", err)
+			http.Error(w, "bad request", http.StatusBadRequest)
+			return
+		}
+
+		mu.Lock()
+		i := next
+		next++
+		mu.Unlock()
+
+		if i >= len(steps) {
+			t.Errorf("unexpected request #%d: %s", i+1, req.Command)
+			http.Error(w, "unexpected request", http.StatusInternalServerError)
+			return
+		}
+
+		if steps[i].Validate != nil {
+			steps[i].Validate(t, req)
+		}
+
+		if err := json.NewEncoder(w).Encode(steps[i].Responses); err != nil {
+			t.Errorf("failed to encode mock response: %v", err)
+			http.Error(w, "internal error", http.StatusInternalServerError)
+		}
+	})
+
+	server := httptest.NewServer(handler)
+	t.Cleanup(func() {
+		server.Close()
+		mu.Lock()
+		defer mu.Unlock()
+		if next < len(steps) {
+			t.Errorf("expected %d requests, got %d", len(steps), next)
+		}
+	})
+
+	return client.NewHTTP(server.URL)
+}
+
+// NewSequenceSocketMockClient is like NewSequenceMockClient but serves the steps over a
+// TCP socket through client.SocketTransport, one connection per request, the way the
+// control socket of service does: requests must not carry a service field and a single
+// response is sent as an object rather than a list. Validate sees the request with
+// service filled in, so the usual ExpectCommand checks apply.
+func NewSequenceSocketMockClient(t *testing.T, service client.Service, steps ...MockStep) *client.Client {
+	t.Helper()
+
+	l, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatalf("failed to start listener: %v", err)
+	}
+
+	served := make(chan struct{})
+	go func() {
+		defer close(served)
+		for i := range steps {
+			conn, err := l.Accept()
+			if err != nil {
+				t.Errorf("expected %d requests, got %d", len(steps), i)
+				return
+			}
+			serveSocketStep(t, conn, service, steps[i])
+		}
+	}()
+
+	t.Cleanup(func() {
+		l.Close()
+		<-served
+	})
+
+	return client.NewClient(client.NewSocketTransport("tcp", l.Addr().String(), 2*time.Second))
+}
+
+// serveSocketStep answers a single request on conn and closes it.
+func serveSocketStep(t *testing.T, conn net.Conn, service client.Service, step MockStep) {
+	defer conn.Close()
+
+	var req client.CommandRequest
+	if err := json.NewDecoder(conn).Decode(&req); err != nil {
+		t.Errorf("failed to decode request: %v", err)
+		return
+	}
+	if req.Service != nil {
+		t.Errorf("control socket request carries service %v", req.Service)
+	}
+	if step.Validate != nil {
+		req.Service = []client.Service{service}
+		step.Validate(t, req)
+	}
+
+	var reply interface{} = step.Responses
+	if len(step.Responses) == 1 {
+		reply = step.Responses[0]
+	}
+	if err := json.NewEncoder(conn).Encode(reply); err != nil {
+		t.Errorf("failed to encode mock response: %v", err)
+	}
+}
+
 // ExpectCommand returns a function that can be used to validate a CommandRequest.
 func ExpectCommand(t *testing.T, wantCommand string, wantService ...client.Service) func(*testing.T, client.CommandRequest) {
 	return func(t *testing.T, req client.CommandRequest) {
@@ -71,6 +191,105 @@ func ExpectCommand(t *testing.T, wantCommand string, wantService ...client.Servi
 	}
 }
 
+// ExpectCommandArgs is like ExpectCommand but also checks that the request arguments
+// are JSON-equivalent to wantArgs. A nil wantArgs expects no arguments at all.
+func ExpectCommandArgs(t *testing.T, wantCommand string, wantArgs interface{}, wantService ...client.Service) func(*testing.T, client.CommandRequest) {
+	expectCommand := ExpectCommand(t, wantCommand, wantService...)
+	return func(t *testing.T, req client.CommandRequest) {
+		t.Helper()
+		expectCommand(t, req)
+
+		if wantArgs == nil {
+			if len(req.Arguments) != 0 {
+				t.Errorf("expected no arguments, got %v", req.Arguments)
+			}
+			return
+		}
+
+		got, want := normalizeJSON(t, req.Arguments), normalizeJSON(t, wantArgs)
+		if !reflect.DeepEqual(got, want) {
+			t.Errorf("unexpected arguments:\n got  %v\n want %v", got, want)
+		}
+	}
+}
+
+// ExpectJSONEquivalent fails the test unless got and want encode the same JSON value,
+// ignoring member order and formatting.
+func ExpectJSONEquivalent(t *testing.T, got, want []byte) {
+	t.Helper()
+	var g, w interface{}
+	if err := json.Unmarshal(got, &g); err != nil {
+		t.Fatalf("failed to decode got: %v", err)
+	}
+	if err := json.Unmarshal(want, &w); err != nil {
+		t.Fatalf("failed to decode want: %v", err)
+	}
+	if !reflect.DeepEqual(g, w) {
+		t.Errorf("JSON mismatch:\n got  %s\n want %s", got, want)
+	}
+}
+
+// EqualExported reports whether a and b are deeply equal like reflect.DeepEqual, but
+// ignores unexported struct fields such as the decoding bookkeeping of config types.
+func EqualExported(a, b interface{}) bool {
+	return equalExported(reflect.ValueOf(a), reflect.ValueOf(b))
+}
+
+func equalExported(a, b reflect.Value) bool {
+	if !a.IsValid() || !b.IsValid() {
+		return a.IsValid() == b.IsValid()
+	}
+	if a.Type() != b.Type() {
+		return false
+	}
+	switch a.Kind() {
+	case reflect.Struct:
+		for i := 0; i < a.NumField(); i++ {
+			if a.Type().Field(i).IsExported() && !equalExported(a.Field(i), b.Field(i)) {
+				return false
+			}
+		}
+		return true
+	case reflect.Pointer, reflect.Interface:
+		if a.IsNil() || b.IsNil() {
+			return a.IsNil() == b.IsNil()
+		}
+		return equalExported(a.Elem(), b.Elem())
+	case reflect.Slice, reflect.Array:
+		if a.Kind() == reflect.Slice && a.IsNil() != b.IsNil() || a.Len() != b.Len() {
+			return false
+		}
+		for i := 0; i < a.Len(); i++ {
+			if !equalExported(a.Index(i), b.Index(i)) {
+				return false
+			}
+		}
+		return true
+	case reflect.Map:
+		if a.IsNil() != b.IsNil() || a.Len() != b.Len() {
+			return false
+		}
+		for _, k := range a.MapKeys() {
+			bv := b.MapIndex(k)
+			if !bv.IsValid() || !equalExported(a.MapIndex(k), bv) {
+				return false
+			}
+		}
+		return true
+	}
+	return reflect.DeepEqual(a.Interface(), b.Interface())
+}
+
+// normalizeJSON round-trips v through JSON so values of different Go types compare equal.
+func normalizeJSON(t *testing.T, v interface{}) interface{} {
+	t.Helper()
+	var out interface{}
+	if err := json.Unmarshal(MustEncodeRawJSON(t, v), &out); err != nil {
+		t.Fatalf("failed to normalize JSON: %v", err)
+	}
+	return out
+}
+
 func keaURL() string {
 	if url := os.Getenv("KEA_API_URL"); url != "" {
 		return url
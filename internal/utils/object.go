@@ -0,0 +1,150 @@
+package utils
+
+import (
+	"bytes"
+	"encoding/json"
+	"reflect"
+	"strings"
+	"sync"
+)
+
+// Absent is what DecodeObject records about modelled members whose encoding would
+// not match the decoded object.
+type Absent struct {
+	missing map[string]json.RawMessage // members the object lacked, with the zero encoding the Go type emits anyway
+	empty   map[string]json.RawMessage // members the object held with a value the Go type omits as empty
+}
+
+// DecodeObject unmarshals data into v, a pointer to a struct type without its own
+// UnmarshalJSON method. Members v does not model are stored in extra. Modelled members
+// v's encoding would not reproduce, because data lacks them or holds them empty, are
+// recorded in absent. EncodeObject uses both to reproduce data up to member order.
+//
+// Config types use the pair to stay lossless: each carries an exported Extra map and
+// an unexported Absent, and its UnmarshalJSON and MarshalJSON call DecodeObject and
+// EncodeObject on a copy of the type without methods. Extra holds the members the
+// type's fields do not model; callers may read and edit it, and a field set to a
+// member of the same name wins. A decoded object then re-encodes as Kea sent it,
+// changed only by the fields set since.
+func DecodeObject(data []byte, v interface{}, extra *map[string]json.RawMessage, absent *Absent) error {
+	*extra, *absent = nil, Absent{}
+	if err := json.Unmarshal(data, v); err != nil {
+		return err
+	}
+	var in map[string]json.RawMessage
+	if err := json.Unmarshal(data, &in); err != nil || in == nil {
+		return err
+	}
+
+	out, err := encodeMembers(v)
+	if err != nil {
+		return err
+	}
+	modelled := memberNames(reflect.TypeOf(v).Elem())
+	for k, raw := range in {
+		if _, ok := out[k]; ok {
+			continue
+		}
+		if modelled[k] {
+			absent.empty = addMember(absent.empty, k, raw)
+		} else {
+			*extra = addMember(*extra, k, raw)
+		}
+	}
+	for k, raw := range out {
+		if _, ok := in[k]; !ok {
+			absent.missing = addMember(absent.missing, k, raw)
+		}
+	}
+	return nil
+}
+
+// EncodeObject marshals v, a struct value whose type has no MarshalJSON method of
+// its own, adding the extra members, leaving out missing members that still hold
+// their zero value and restoring empty members that are still empty. Fields set
+// since decoding always win.
+func EncodeObject(v interface{}, extra map[string]json.RawMessage, absent Absent) ([]byte, error) {
+	if len(extra) == 0 && len(absent.missing) == 0 && len(absent.empty) == 0 {
+		return json.Marshal(v)
+	}
+
+	out, err := encodeMembers(v)
+	if err != nil {
+		return nil, err
+	}
+	for k, zero := range absent.missing {
+		if cur, ok := out[k]; ok && bytes.Equal(cur, zero) {
+			delete(out, k)
+		}
+	}
+	for _, m := range []map[string]json.RawMessage{absent.empty, extra} {
+		for k, raw := range m {
+			if _, ok := out[k]; !ok {
+				out[k] = raw
+			}
+		}
+	}
+	return json.Marshal(out)
+}
+
+// addMember sets m[k] to raw, allocating m if needed.
+func addMember(m map[string]json.RawMessage, k string, raw json.RawMessage) map[string]json.RawMessage {
+	if m == nil {
+		m = make(map[string]json.RawMessage)
+	}
+	m[k] = raw
+	return m
+}
+
+// encodeMembers marshals v and splits the resulting object into its members.
+func encodeMembers(v interface{}) (map[string]json.RawMessage, error) {
+	b, err := json.Marshal(v)
+	if err != nil {
+		return nil, err
+	}
+	var out map[string]json.RawMessage
+	if err := json.Unmarshal(b, &out); err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+var memberNameCache sync.Map // reflect.Type -> map[string]bool
+
+// memberNames returns the names of the JSON members struct type t models, including
+// those of embedded structs.
+func memberNames(t reflect.Type) map[string]bool {
+	if names, ok := memberNameCache.Load(t); ok {
+		return names.(map[string]bool)
+	}
+	names := make(map[string]bool)
+	for i := 0; i < t.NumField(); i++ {
+		f := t.Field(i)
+		tag := f.Tag.Get("json")
+		if tag == "-" {
+			continue
+		}
+		name, _, _ := strings.Cut(tag, ",")
+		if f.Anonymous && name == "" {
+			ft := f.Type
+			if ft.Kind() == reflect.Pointer {
+				ft = ft.Elem()
+			}
+			if ft.Kind() == reflect.Struct {
+				for n := range memberNames(ft) {
+					names[n] = true
+				}
+				continue
+			}
+		}
+		if !f.IsExported() {
+			continue
+		}
+		if name == "" {
+			name = f.Name
+		}
+		names[name] = true
+	}
+	memberNameCache.Store(t, names)
+	return names
+}
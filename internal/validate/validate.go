@@ -0,0 +1,306 @@
+// Package validate holds the checks shared by the dhcp4 and dhcp6 offline validators.
+package validate
+
+import (
+	"fmt"
+	"net/netip"
+	"sort"
+	"strings"
+
+	"github.com/rannday/kea-api/configfile"
+	"github.com/rannday/kea-api/types"
+)
+
+// Range is an inclusive address range found at Pointer.
+type Range struct {
+	Pointer     string
+	First, Last netip.Addr
+}
+
+// overlaps reports whether r and o share an address.
+func (r Range) overlaps(o Range) bool {
+	return !r.Last.Less(o.First) && !o.Last.Less(r.First)
+}
+
+// PrefixRange returns the addresses covered by p.
+func PrefixRange(pointer string, p netip.Prefix) Range {
+	first, last, _ := types.ParsePool(p.Masked().String())
+	return Range{Pointer: pointer, First: first, Last: last}
+}
+
+// Checker accumulates diagnostics while a configuration is walked. Pool overlaps
+// are detected across all subnets once the walk is done.
+type Checker struct {
+	diags   types.Diagnostics
+	ids     map[int]string
+	pools   []Range
+	pdPools []Range
+}
+
+// New returns an empty Checker.
+func New() *Checker {
+	return &Checker{ids: make(map[int]string)}
+}
+
+// Errorf records an error at pointer.
+func (c *Checker) Errorf(code types.DiagnosticCode, pointer, format string, args ...interface{}) {
+	c.add(types.SeverityError, code, pointer, fmt.Sprintf(format, args...))
+}
+
+// Warnf records a warning at pointer.
+func (c *Checker) Warnf(code types.DiagnosticCode, pointer, format string, args ...interface{}) {
+	c.add(types.SeverityWarning, code, pointer, fmt.Sprintf(format, args...))
+}
+
+func (c *Checker) add(sev types.Severity, code types.DiagnosticCode, pointer, msg string) {
+	c.diags = append(c.diags, types.Diagnostic{Severity: sev, Code: code, Pointer: pointer, Message: msg})
+}
+
+// Finish runs the checks that need the whole configuration and returns the diagnostics.
+func (c *Checker) Finish() types.Diagnostics {
+	c.overlaps(c.pools, "pool")
+	c.overlaps(c.pdPools, "pd-pool")
+	return c.diags
+}
+
+func (c *Checker) overlaps(ranges []Range, what string) {
+	sorted := append([]Range(nil), ranges...)
+	sort.SliceStable(sorted, func(i, j int) bool { return sorted[i].First.Less(sorted[j].First) })
+	widest := 0 // the range reaching furthest so far
+	for i := 1; i < len(sorted); i++ {
+		if w := sorted[widest]; !w.Last.Less(sorted[i].First) {
+			c.Errorf(types.DiagPoolOverlap, sorted[i].Pointer, "%s %s - %s overlaps %s at %s",
+				what, sorted[i].First, sorted[i].Last, what, w.Pointer)
+		}
+		if sorted[widest].Last.Less(sorted[i].Last) {
+			widest = i
+		}
+	}
+}
+
+// SubnetID records the id of the subnet at pointer. Zero ids are assigned by Kea and skipped.
+func (c *Checker) SubnetID(pointer string, id int) {
+	if id == 0 {
+		return
+	}
+	if first, ok := c.ids[id]; ok {
+		c.Errorf(types.DiagDuplicateSubnetID, pointer+"/id", "subnet id %d is already used by %s", id, first)
+		return
+	}
+	c.ids[id] = pointer
+}
+
+// Subnet parses the subnet prefix at pointer. It reports and returns false if it is not a valid prefix of the given family.
+func (c *Checker) Subnet(pointer, subnet string, v6 bool) (netip.Prefix, bool) {
+	p, err := netip.ParsePrefix(subnet)
+	if err != nil || p.Addr().Is6() != v6 {
+		c.Errorf(types.DiagInvalidValue, pointer+"/subnet", "invalid subnet %q", subnet)
+		return netip.Prefix{}, false
+	}
+	return p.Masked(), true
+}
+
+// Pool parses the pool at pointer, checks it lies within subnet when that is valid
+// and records it for the overlap check.
+func (c *Checker) Pool(pointer string, subnet netip.Prefix, pool string) (Range, bool) {
+	first, last, err := types.ParsePool(pool)
+	if err != nil {
+		c.Errorf(types.DiagInvalidValue, pointer+"/pool", "%v", err)
+		return Range{}, false
+	}
+	r := Range{Pointer: pointer, First: first, Last: last}
+	if subnet.IsValid() && (!subnet.Contains(first) || !subnet.Contains(last)) {
+		c.Errorf(types.DiagPoolOutsideSubnet, pointer+"/pool", "pool %s is outside subnet %s", pool, subnet)
+	}
+	c.pools = append(c.pools, r)
+	return r, true
+}
+
+// PDPool records the prefix delegation pool at pointer for the overlap check.
+func (c *Checker) PDPool(pointer string, network netip.Prefix, err error) (Range, bool) {
+	if err != nil {
+		c.Errorf(types.DiagInvalidValue, pointer+"/prefix", "%v", err)
+		return Range{}, false
+	}
+	r := PrefixRange(pointer, network)
+	c.pdPools = append(c.pdPools, r)
+	return r, true
+}
+
+// Reserved checks a reserved address or prefix r. When subnet is valid r must lie
+// within it; when outOfPool is set it must not fall in any of pools.
+func (c *Checker) Reserved(r Range, what string, subnet netip.Prefix, pools []Range, outOfPool bool) {
+	if subnet.IsValid() && (!subnet.Contains(r.First) || !subnet.Contains(r.Last)) {
+		c.Errorf(types.DiagReservationOutsideSubnet, r.Pointer, "reserved %s is outside subnet %s", what, subnet)
+	}
+	if !outOfPool {
+		return
+	}
+	for _, p := range pools {
+		if p.overlaps(r) {
+			c.Errorf(types.DiagReservationInPool, r.Pointer,
+				"reserved %s is inside pool at %s but reservations-out-of-pool is set", what, p.Pointer)
+			return
+		}
+	}
+}
+
+// Timers are the lease timer parameters of one scope. Zero means unset.
+type Timers struct {
+	Renew, Rebind, Valid int
+	T1Percent, T2Percent float64
+}
+
+// Inherit fills the unset parameters of t from parent.
+func (t Timers) Inherit(parent Timers) Timers {
+	if t.Renew == 0 {
+		t.Renew = parent.Renew
+	}
+	if t.Rebind == 0 {
+		t.Rebind = parent.Rebind
+	}
+	if t.Valid == 0 {
+		t.Valid = parent.Valid
+	}
+	if t.T1Percent == 0 {
+		t.T1Percent = parent.T1Percent
+	}
+	if t.T2Percent == 0 {
+		t.T2Percent = parent.T2Percent
+	}
+	return t
+}
+
+// Timers checks the effective timers of the scope at pointer. Only scopes that set
+// a parameter themselves are checked, so an inherited problem is reported once.
+func (c *Checker) Timers(pointer string, own, effective Timers) {
+	at := func(member string) string { return pointer + "/" + member }
+	if own.Renew != 0 || own.Rebind != 0 || own.Valid != 0 {
+		e := effective
+		switch {
+		case e.Renew > 0 && e.Rebind > 0 && e.Renew > e.Rebind:
+			c.Warnf(types.DiagTimerOrder, at(pick(own.Renew != 0, "renew-timer", "rebind-timer")),
+				"renew-timer %d exceeds rebind-timer %d; Kea will not send it", e.Renew, e.Rebind)
+		case e.Rebind > 0 && e.Valid > 0 && e.Rebind > e.Valid:
+			c.Warnf(types.DiagTimerOrder, at(pick(own.Rebind != 0, "rebind-timer", "valid-lifetime")),
+				"rebind-timer %d exceeds valid-lifetime %d; Kea will not send it", e.Rebind, e.Valid)
+		case e.Rebind == 0 && e.Renew > 0 && e.Valid > 0 && e.Renew > e.Valid:
+			c.Warnf(types.DiagTimerOrder, at(pick(own.Renew != 0, "renew-timer", "valid-lifetime")),
+				"renew-timer %d exceeds valid-lifetime %d; Kea will not send it", e.Renew, e.Valid)
+		}
+	}
+
+	if own.T1Percent != 0 || own.T2Percent != 0 {
+		e := effective
+		for _, p := range []struct {
+			name  string
+			value float64
+		}{{"t1-percent", e.T1Percent}, {"t2-percent", e.T2Percent}} {
+			if p.value < 0 || p.value >= 1 {
+				c.Errorf(types.DiagInvalidValue, at(p.name), "%s %g must be between 0 and 1", p.name, p.value)
+				return
+			}
+		}
+		if e.T1Percent > 0 && e.T2Percent > 0 && e.T1Percent >= e.T2Percent {
+			c.Errorf(types.DiagTimerOrder, at(pick(own.T1Percent != 0, "t1-percent", "t2-percent")),
+				"t1-percent %g must be less than t2-percent %g", e.T1Percent, e.T2Percent)
+		}
+	}
+}
+
+func pick(cond bool, a, b string) string {
+	if cond {
+		return a
+	}
+	return b
+}
+
+// OptionSet knows which option names may appear in option-data.
+type OptionSet struct {
+	defaultSpace string
+	names        map[string]map[string]bool // space -> name
+}
+
+// NewOptionSet returns the standard options of defaultSpace plus the options defined by defs.
+func NewOptionSet(defaultSpace string, standard map[string]int, defs []types.OptionDef) OptionSet {
+	s := OptionSet{defaultSpace: defaultSpace, names: map[string]map[string]bool{defaultSpace: {}}}
+	for name := range standard {
+		s.names[defaultSpace][name] = true
+	}
+	for _, d := range defs {
+		space := d.Space
+		if space == "" {
+			space = defaultSpace
+		}
+		if s.names[space] == nil {
+			s.names[space] = make(map[string]bool)
+		}
+		s.names[space][d.Name] = true
+	}
+	return s
+}
+
+// Options checks the option-data list of the scope at pointer. Options given only
+// by code, and options in spaces with no known definitions, are not checked.
+func (c *Checker) Options(pointer string, opts []types.OptionData, known OptionSet) {
+	for i, o := range opts {
+		if o.Name == "" {
+			continue
+		}
+		space := o.Space
+		if space == "" {
+			space = known.defaultSpace
+		}
+		names, ok := known.names[space]
+		if !ok || names[o.Name] {
+			continue
+		}
+		c.Errorf(types.DiagUnknownOption, fmt.Sprintf("%s/option-data/%d/name", pointer, i),
+			"option %q is not a standard %s option and has no option-def", o.Name, space)
+	}
+}
+
+// ControlSocket checks the server's control-socket at pointer against the Control
+// Agent's entry for service. Nothing is checked when agent is nil.
+func (c *Checker) ControlSocket(pointer, service string, sock types.SocketConfig, agent map[string]types.SocketConfig) {
+	if agent == nil {
+		return
+	}
+	want, ok := agent[service]
+	switch {
+	case !ok:
+		c.Warnf(types.DiagControlSocketMismatch, pointer, "the Control Agent has no control socket for %s", service)
+	case sock.SocketName == "":
+		c.Errorf(types.DiagControlSocketMismatch, pointer,
+			"no control-socket configured but the Control Agent expects %s", want.SocketName)
+	case sock.SocketName != want.SocketName || !strings.EqualFold(socketType(sock), socketType(want)):
+		c.Errorf(types.DiagControlSocketMismatch, pointer+"/socket-name",
+			"control socket %s %s does not match the Control Agent's %s %s for %s",
+			socketType(sock), sock.SocketName, socketType(want), want.SocketName, service)
+	}
+}
+
+func socketType(s types.SocketConfig) string {
+	if s.SocketType == "" {
+		return "unix"
+	}
+	return s.SocketType
+}
+
+// Locate sets the Pos of each diagnostic from the file the configuration was loaded
+// from. A pointer to a member the file does not contain, such as a defaulted
+// parameter, is placed at its nearest enclosing value; a malformed pointer without a
+// leading "/" at the root.
+func Locate(ds types.Diagnostics, doc *configfile.Document) {
+	for i := range ds {
+		for p := ds[i].Pointer; ; p = p[:max(strings.LastIndex(p, "/"), 0)] {
+			if pos, ok := doc.Position(p); ok {
+				ds[i].Pos = types.Position(pos)
+				break
+			}
+			if p == "" {
+				break
+			}
+		}
+	}
+}
@@ -0,0 +1,34 @@
+package validate
+
+import (
+	"testing"
+
+	"github.com/rannday/kea-api/configfile"
+	"github.com/rannday/kea-api/types"
+)
+
+// TestLocate places diagnostics at their value, or the nearest enclosing one.
+func TestLocate(t *testing.T) {
+	t.Parallel()
+
+	doc, err := configfile.ParseBytes("kea.conf", []byte("{\n  \"Dhcp4\": {\n    \"subnet4\": []\n  }\n}"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	diags := types.Diagnostics{
+		{Severity: types.SeverityError, Code: types.DiagDuplicateSubnetID, Pointer: "/Dhcp4/subnet4", Message: "here"},
+		{Severity: types.SeverityWarning, Code: types.DiagControlSocketMismatch, Pointer: "/Dhcp4/control-socket", Message: "parent"},
+		{Severity: types.SeverityWarning, Code: types.DiagInvalidValue, Pointer: "Dhcp4/subnet4", Message: "malformed"},
+	}
+	Locate(diags, doc)
+
+	want := "kea.conf:3:16: error: here [duplicate-subnet-id]\n" +
+		"kea.conf:2:12: warning: parent [control-socket-mismatch]\n" +
+		"kea.conf:1:1: warning: malformed [invalid-value]\n"
+	if got := diags.String(); got != want {
+		t.Errorf("String() =\n%s\nwant\n%s", got, want)
+	}
+	if !diags.HasErrors() || len(diags.Errors()) != 1 {
+		t.Errorf("Errors() = %v", diags.Errors())
+	}
+}
@@ -19,32 +19,47 @@ var (
 
 // Control Agent
 var (
-	BuildReport  = agent.BuildReport
-	ConfigGet    = agent.ConfigGet
-	ListCommands = agent.ListCommands
-	StatusGet    = agent.StatusGet
-	VersionGet   = agent.VersionGet
+	BuildReport         = agent.BuildReport
+	BuildReportContext  = agent.BuildReportContext
+	ConfigGet           = agent.ConfigGet
+	ConfigGetContext    = agent.ConfigGetContext
+	ListCommands        = agent.ListCommands
+	ListCommandsContext = agent.ListCommandsContext
+	StatusGet           = agent.StatusGet
+	StatusGetContext    = agent.StatusGetContext
+	VersionGet          = agent.VersionGet
+	VersionGetContext   = agent.VersionGetContext
 )
 
 // DHCPv4
 var (
-	BuildReport4  = dhcp4.BuildReport
-	ConfigGet4    = dhcp4.ConfigGet
-	ListCommands4 = dhcp4.ListCommands
-	StatusGet4    = dhcp4.StatusGet
-	VersionGet4   = dhcp4.VersionGet
+	BuildReport4         = dhcp4.BuildReport
+	BuildReportContext4  = dhcp4.BuildReportContext
+	ConfigGet4           = dhcp4.ConfigGet
+	ConfigGetContext4    = dhcp4.ConfigGetContext
+	ListCommands4        = dhcp4.ListCommands
+	ListCommandsContext4 = dhcp4.ListCommandsContext
+	StatusGet4           = dhcp4.StatusGet
+	StatusGetContext4    = dhcp4.StatusGetContext
+	VersionGet4          = dhcp4.VersionGet
+	VersionGetContext4   = dhcp4.VersionGetContext
 )
 
 // DHCPv6
 var (
-	BuildReport6  = dhcp6.BuildReport
-	ConfigGet6    = dhcp6.ConfigGet
-	ListCommands6 = dhcp6.ListCommands
-	StatusGet6    = dhcp6.StatusGet
-	VersionGet6   = dhcp6.VersionGet
+	BuildReport6         = dhcp6.BuildReport
+	BuildReportContext6  = dhcp6.BuildReportContext
+	ConfigGet6           = dhcp6.ConfigGet
+	ConfigGetContext6    = dhcp6.ConfigGetContext
+	ListCommands6        = dhcp6.ListCommands
+	ListCommandsContext6 = dhcp6.ListCommandsContext
+	StatusGet6           = dhcp6.StatusGet
+	StatusGetContext6    = dhcp6.StatusGetContext
+	VersionGet6          = dhcp6.VersionGet
+	VersionGetContext6   = dhcp6.VersionGetContext
 )
 
 // DDNS
 /*var (
-	
+
 )*/
@@ -1,69 +1,93 @@
 package types
 
+import (
+	"encoding/json"
+
+	"github.com/rannday/kea-api/internal/utils"
+)
+
 // SocketConfig defines the control socket location and type.
 type SocketConfig struct {
-	SocketName string `json:"socket-name"`
-	SocketType string `json:"socket-type"`
+	SocketName string                     `json:"socket-name"`
+	SocketType string                     `json:"socket-type"`
+	Extra      map[string]json.RawMessage `json:"-"`
+	absent     utils.Absent
 }
 
 // LoggerConfig represents a logger instance for outputting diagnostic information.
 type LoggerConfig struct {
-	DebugLevel    int               `json:"debuglevel"`
-	Name          string            `json:"name"`
-	OutputOptions []LogOutputOption `json:"output-options"`
-	Severity      string            `json:"severity"`
+	DebugLevel    int                        `json:"debuglevel"`
+	Name          string                     `json:"name"`
+	OutputOptions []LogOutputOption          `json:"output-options"`
+	Severity      string                     `json:"severity"`
+	Extra         map[string]json.RawMessage `json:"-"`
+	absent        utils.Absent
 }
 
 // LogOutputOption controls destination, format, and flushing for logger output.
 type LogOutputOption struct {
-	Flush   bool   `json:"flush"`
-	Output  string `json:"output"`
-	Pattern string `json:"pattern"`
+	Flush   bool                       `json:"flush"`
+	Output  string                     `json:"output"`
+	Pattern string                     `json:"pattern"`
+	Extra   map[string]json.RawMessage `json:"-"`
+	absent  utils.Absent
 }
 
 // HookLibrary represents a dynamically loaded Kea hook module.
 type HookLibrary struct {
-	Library string `json:"library"`
+	Library string                     `json:"library"`
+	Extra   map[string]json.RawMessage `json:"-"`
+	absent  utils.Absent
 }
 
 // InterfacesConfig lists interfaces to bind and whether to auto-detect them.
 type InterfacesConfig struct {
-	Interfaces []string `json:"interfaces"`
-	ReDetect   bool     `json:"re-detect"`
+	Interfaces []string                   `json:"interfaces"`
+	ReDetect   bool                       `json:"re-detect"`
+	Extra      map[string]json.RawMessage `json:"-"`
+	absent     utils.Absent
 }
 
 // SanityChecks configures validation levels for leases and configuration.
 type SanityChecks struct {
-	ExtendedInfoChecks string `json:"extended-info-checks"`
-	LeaseChecks        string `json:"lease-checks"`
+	ExtendedInfoChecks string                     `json:"extended-info-checks"`
+	LeaseChecks        string                     `json:"lease-checks"`
+	Extra              map[string]json.RawMessage `json:"-"`
+	absent             utils.Absent
 }
 
 // DatabaseConfig represents a generic database connection config (used in config-databases or hosts-databases).
 type DatabaseConfig struct {
-	Host     string `json:"host"`
-	Name     string `json:"name"`
-	Password string `json:"password"`
-	Port     int    `json:"port"`
-	Type     string `json:"type"` // e.g. "mysql", "postgresql"
-	User     string `json:"user"`
+	Host     string                     `json:"host"`
+	Name     string                     `json:"name"`
+	Password string                     `json:"password"`
+	Port     int                        `json:"port"`
+	Type     string                     `json:"type"` // e.g. "mysql", "postgresql"
+	User     string                     `json:"user"`
+	Extra    map[string]json.RawMessage `json:"-"`
+	absent   utils.Absent
 }
 
 // LeaseDatabaseConfig represents the lease-database block, which may include additional memfile-specific fields.
 type LeaseDatabaseConfig struct {
-	Host        string `json:"host,omitempty"`
-	Name        string `json:"name,omitempty"`
-	Password    string `json:"password,omitempty"`
-	Port        int    `json:"port,omitempty"`
-	Type        string `json:"type"` // e.g. "mysql", "memfile"
-	User        string `json:"user,omitempty"`
-	LFCInterval int    `json:"lfc-interval,omitempty"` // Only used by memfile backends
+	Host        string                     `json:"host,omitempty"`
+	Name        string                     `json:"name,omitempty"`
+	Password    string                     `json:"password,omitempty"`
+	Port        int                        `json:"port,omitempty"`
+	Type        string                     `json:"type"` // e.g. "mysql", "memfile"
+	User        string                     `json:"user,omitempty"`
+	LFCInterval int                        `json:"lfc-interval,omitempty"` // Only used by memfile backends
+	Extra       map[string]json.RawMessage `json:"-"`
+	absent      utils.Absent
 }
 
 // MultiThreadingConfig controls concurrency behavior of the DHCP server.
 type MultiThreadingConfig struct {
-	EnableMultiThreading bool `json:"enable-multi-threading"`
-	PacketQueueSize      int  `json:"packet-queue-size"`
-	ThreadPoolSize       int  `json:"thread-pool-size"`
+	EnableMultiThreading bool                       `json:"enable-multi-threading"`
+	PacketQueueSize      int                        `json:"packet-queue-size"`
+	ThreadPoolSize       int                        `json:"thread-pool-size"`
+	Extra                map[string]json.RawMessage `json:"-"`
+	absent               utils.Absent
 }
 
 // DHCPState captures the service's enabled/disabled status.
@@ -79,3 +103,185 @@ type DHCPState struct {
 type ListCommandsResponse struct {
 	Arguments []string `json:"arguments"`
 }
+
+// ConfigHash is the arguments block returned by config-hash-get, and by config-set on recent Kea versions.
+type ConfigHash struct {
+	Hash string `json:"hash"`
+}
+
+// ConfigWriteResult is the arguments block returned by config-write.
+type ConfigWriteResult struct {
+	Filename string `json:"filename"`
+	Size     int64  `json:"size"`
+}
+
+// LeaseState is the state of a lease as reported by the lease_cmds hook.
+type LeaseState int
+
+// Lease states defined by Kea.
+const (
+	LeaseStateDefault          LeaseState = 0 // Assigned and in use
+	LeaseStateDeclined         LeaseState = 1 // Declined by the client
+	LeaseStateExpiredReclaimed LeaseState = 2 // Expired and reclaimed by the server
+	LeaseStateReleased         LeaseState = 3 // Released by the client (Kea 2.7+)
+	LeaseStateRegistered       LeaseState = 4 // Registered address (DHCPv6 only, Kea 2.7+)
+)
+
+// DefaultLeasePageLimit is the page size used by the lease iterators when none is given.
+const DefaultLeasePageLimit = 1000
+
+// LeasePageOptions controls iteration over lease4-get-page and lease6-get-page.
+type LeasePageOptions struct {
+	From      string // Address to resume after; empty starts from the first lease
+	Limit     int    // Leases fetched per page; 0 uses DefaultLeasePageLimit
+	SubnetIDs []int  // Only yield leases in these subnets; Kea cannot filter pages, so this is applied client-side
+}
+
+// PageLimit returns the effective page size.
+func (o LeasePageOptions) PageLimit() int {
+	if o.Limit <= 0 {
+		return DefaultLeasePageLimit
+	}
+	return o.Limit
+}
+
+// PageStart returns the "from" cursor for the first page.
+func (o LeasePageOptions) PageStart() string {
+	if o.From == "" {
+		return "start"
+	}
+	return o.From
+}
+
+// MatchSubnet reports whether a lease in the given subnet passes the SubnetIDs filter.
+func (o LeasePageOptions) MatchSubnet(subnetID int) bool {
+	if len(o.SubnetIDs) == 0 {
+		return true
+	}
+	for _, id := range o.SubnetIDs {
+		if id == subnetID {
+			return true
+		}
+	}
+	return false
+}
+
+// OptionData is a DHCP option value, as used in global, subnet, pool and reservation scopes.
+// CSVFormat is a pointer because Kea defaults it to true when omitted.
+type OptionData struct {
+	Name       string                     `json:"name,omitempty"`
+	Code       int                        `json:"code,omitempty"`
+	Space      string                     `json:"space,omitempty"`
+	CSVFormat  *bool                      `json:"csv-format,omitempty"`
+	Data       string                     `json:"data,omitempty"`
+	AlwaysSend bool                       `json:"always-send,omitempty"`
+	NeverSend  bool                       `json:"never-send,omitempty"`
+	Extra      map[string]json.RawMessage `json:"-"`
+	absent     utils.Absent
+}
+
+// OptionDef defines a custom option so that option-data can refer to it by name.
+// RecordTypes is a comma-separated list of field types when Type is "record".
+type OptionDef struct {
+	Name        string                     `json:"name"`
+	Code        int                        `json:"code"`
+	Type        string                     `json:"type"`
+	Space       string                     `json:"space,omitempty"`
+	Array       bool                       `json:"array,omitempty"`
+	RecordTypes string                     `json:"record-types,omitempty"`
+	Encapsulate string                     `json:"encapsulate,omitempty"`
+	Extra       map[string]json.RawMessage `json:"-"`
+	absent      utils.Absent
+}
+
+// Relay lists the relay agent addresses through which clients of a subnet or shared network are reached.
+type Relay struct {
+	IPAddresses []string                   `json:"ip-addresses"`
+	Extra       map[string]json.RawMessage `json:"-"`
+	absent      utils.Absent
+}
+
+// ClientClassRestriction limits a subnet, shared network or pool to clients of the given classes.
+// Kea 2.7 replaced client-class and require-client-classes with client-classes and
+// evaluate-additional-classes; both spellings are decoded.
+type ClientClassRestriction struct {
+	ClientClass               string   `json:"client-class,omitempty"`
+	ClientClasses             []string `json:"client-classes,omitempty"`
+	RequireClientClasses      []string `json:"require-client-classes,omitempty"`
+	EvaluateAdditionalClasses []string `json:"evaluate-additional-classes,omitempty"`
+}
+
+// ReservationQuery selects a single host reservation for reservation-get and reservation-del.
+// Set SubnetID (0 targets global reservations) and either IPAddress, or IdentifierType
+// ("hw-address", "duid", "client-id", "circuit-id" or "flex-id") and Identifier.
+type ReservationQuery struct {
+	SubnetID       int    `json:"subnet-id"`
+	IPAddress      string `json:"ip-address,omitempty"`
+	IdentifierType string `json:"identifier-type,omitempty"`
+	Identifier     string `json:"identifier,omitempty"`
+}
+
+// ReservationPageCursor is the position returned in "next" by reservation-get-page.
+type ReservationPageCursor struct {
+	From        uint64 `json:"from"`
+	SourceIndex int    `json:"source-index"`
+}
+
+// DefaultReservationPageLimit is the page size used by the reservation iterators when none is given.
+const DefaultReservationPageLimit = 100
+
+// ReservationPageOptions controls iteration over reservation-get-page.
+type ReservationPageOptions struct {
+	SubnetID   int  // Subnet to list; 0 lists global reservations
+	AllSubnets bool // Omit subnet-id so Kea pages through every subnet
+	Limit      int  // Hosts fetched per page; 0 uses DefaultReservationPageLimit
+}
+
+// PageArguments returns the reservation-get-page arguments for the page after cursor.
+// A nil cursor requests the first page.
+func (o ReservationPageOptions) PageArguments(cursor *ReservationPageCursor) map[string]interface{} {
+	limit := o.Limit
+	if limit <= 0 {
+		limit = DefaultReservationPageLimit
+	}
+	args := map[string]interface{}{"limit": limit}
+	if !o.AllSubnets {
+		args["subnet-id"] = o.SubnetID
+	}
+	if cursor != nil {
+		args["source-index"] = cursor.SourceIndex
+		args["from"] = cursor.From
+	}
+	return args
+}
+
+// SubnetRef identifies a subnet by ID and prefix, as listed by subnet4-list/subnet6-list
+// and returned by the subnet add, update and delete commands.
+type SubnetRef struct {
+	ID     int    `json:"id"`
+	Subnet string `json:"subnet"`
+}
+
+// SubnetRefList is the arguments block holding a list of SubnetRef values.
+type SubnetRefList struct {
+	Subnets []SubnetRef `json:"subnets"`
+}
+
+// SharedNetworkRef names a shared network, as listed by network4-list/network6-list.
+type SharedNetworkRef struct {
+	Name string `json:"name"`
+}
+
+// SharedNetworkRefList is the arguments block holding a list of SharedNetworkRef values.
+type SharedNetworkRefList struct {
+	SharedNetworks []SharedNetworkRef `json:"shared-networks"`
+}
+
+// SubnetsAction tells network4-del/network6-del what to do with the subnets of the deleted network.
+type SubnetsAction string
+
+// Subnet actions accepted on shared network deletion.
+const (
+	SubnetsKeep   SubnetsAction = "keep"   // Detach the subnets and keep them as plain subnets
+	SubnetsDelete SubnetsAction = "delete" // Delete the subnets together with the network
+)
@@ -0,0 +1,395 @@
+package types
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"reflect"
+	"sort"
+	"strings"
+)
+
+// ChangeOp says whether a ConfigChange adds, removes or modifies a value.
+type ChangeOp string
+
+// Change operations.
+const (
+	ChangeAdded   ChangeOp = "added"
+	ChangeRemoved ChangeOp = "removed"
+	ChangeChanged ChangeOp = "changed"
+)
+
+// ChangeKind is the kind of configuration object a ConfigChange applies to.
+type ChangeKind string
+
+// Kinds of configuration objects. Anything not listed, including members of
+// subnets, pools and shared networks, is a parameter.
+const (
+	KindSubnet        ChangeKind = "subnet"
+	KindSharedNetwork ChangeKind = "shared-network"
+	KindPool          ChangeKind = "pool"
+	KindPDPool        ChangeKind = "pd-pool"
+	KindReservation   ChangeKind = "reservation"
+	KindOption        ChangeKind = "option"
+	KindOptionDef     ChangeKind = "option-def"
+	KindClientClass   ChangeKind = "client-class"
+	KindHookLibrary   ChangeKind = "hook-library"
+	KindLogger        ChangeKind = "logger"
+	KindParameter     ChangeKind = "parameter"
+)
+
+// ConfigChange is one difference between two configurations.
+type ConfigChange struct {
+	Op      ChangeOp
+	Kind    ChangeKind
+	Path    string          // readable location, e.g. Dhcp4.subnet4[id=1].pools[192.0.2.10-192.0.2.20]
+	Pointer string          // RFC 6901 location, valid when the changes are applied in order
+	Old     json.RawMessage // nil for added values
+	New     json.RawMessage // nil for removed values
+}
+
+// ConfigDiff is the ordered list of changes that turns one configuration into another.
+type ConfigDiff []ConfigChange
+
+// String renders the diff one change per line: "+" added, "-" removed, "~" changed.
+func (d ConfigDiff) String() string {
+	var b strings.Builder
+	for _, c := range d {
+		switch c.Op {
+		case ChangeAdded:
+			fmt.Fprintf(&b, "+ %s: %s\n", c.Path, c.New)
+		case ChangeRemoved:
+			fmt.Fprintf(&b, "- %s: %s\n", c.Path, c.Old)
+		default:
+			fmt.Fprintf(&b, "~ %s: %s -> %s\n", c.Path, c.Old, c.New)
+		}
+	}
+	return b.String()
+}
+
+// PatchOp is a single RFC 6902 JSON Patch operation.
+type PatchOp struct {
+	Op    string          `json:"op"`
+	Path  string          `json:"path"`
+	Value json.RawMessage `json:"value,omitempty"`
+}
+
+// Patch converts the diff into JSON Patch operations against the old configuration.
+// Keyed lists such as subnets are matched by identity, so the patched document equals
+// the new one up to the order of those lists.
+func (d ConfigDiff) Patch() []PatchOp {
+	ops := make([]PatchOp, 0, len(d))
+	for _, c := range d {
+		switch c.Op {
+		case ChangeAdded:
+			ops = append(ops, PatchOp{Op: "add", Path: c.Pointer, Value: c.New})
+		case ChangeRemoved:
+			ops = append(ops, PatchOp{Op: "remove", Path: c.Pointer})
+		default:
+			ops = append(ops, PatchOp{Op: "replace", Path: c.Pointer, Value: c.New})
+		}
+	}
+	return ops
+}
+
+// JSONPatch renders the diff as an RFC 6902 JSON Patch document.
+func (d ConfigDiff) JSONPatch() ([]byte, error) {
+	return json.Marshal(d.Patch())
+}
+
+// keyedList describes how elements of a list member are matched between two configurations.
+type keyedList struct {
+	kind   ChangeKind
+	key    func(map[string]interface{}) (string, bool)
+	atomic bool // report changed elements whole instead of member by member
+}
+
+// keyedLists maps list member names to their matching rules. Lists not listed here,
+// or whose elements cannot all be keyed uniquely, are compared as plain values.
+var keyedLists = map[string]keyedList{
+	"subnet4":         {kind: KindSubnet, key: subnetKey},
+	"subnet6":         {kind: KindSubnet, key: subnetKey},
+	"shared-networks": {kind: KindSharedNetwork, key: memberKey("name")},
+	"pools":           {kind: KindPool, key: poolKey},
+	"pd-pools":        {kind: KindPDPool, key: pdPoolKey},
+	"reservations":    {kind: KindReservation, key: reservationKey, atomic: true},
+	"option-data":     {kind: KindOption, key: optionKey, atomic: true},
+	"option-def":      {kind: KindOptionDef, key: optionKey, atomic: true},
+	"client-classes":  {kind: KindClientClass, key: memberKey("name"), atomic: true},
+	"hooks-libraries": {kind: KindHookLibrary, key: memberKey("library")},
+	"loggers":         {kind: KindLogger, key: memberKey("name")},
+}
+
+// DiffConfig compares two configurations, such as two dhcp4.Dhcp4Config values, after
+// encoding them to JSON. Members the Go types do not model are compared too; the
+// top-level hash member is ignored.
+func DiffConfig(from, to interface{}) (ConfigDiff, error) {
+	a, err := toTree(from)
+	if err != nil {
+		return nil, fmt.Errorf("diff config: %w", err)
+	}
+	b, err := toTree(to)
+	if err != nil {
+		return nil, fmt.Errorf("diff config: %w", err)
+	}
+	if m, ok := a.(map[string]interface{}); ok {
+		delete(m, "hash")
+	}
+	if m, ok := b.(map[string]interface{}); ok {
+		delete(m, "hash")
+	}
+
+	var d differ
+	d.value(a, b, "", "", KindParameter)
+	return d.changes, nil
+}
+
+type differ struct {
+	changes ConfigDiff
+}
+
+func (d *differ) add(op ChangeOp, kind ChangeKind, path, pointer string, a, b interface{}) {
+	c := ConfigChange{Op: op, Kind: kind, Path: path, Pointer: pointer}
+	if op != ChangeAdded {
+		c.Old = mustRaw(a)
+	}
+	if op != ChangeRemoved {
+		c.New = mustRaw(b)
+	}
+	d.changes = append(d.changes, c)
+}
+
+// value compares a and b located at path/pointer.
+func (d *differ) value(a, b interface{}, path, pointer string, kind ChangeKind) {
+	if reflect.DeepEqual(a, b) {
+		return
+	}
+	am, aok := a.(map[string]interface{})
+	bm, bok := b.(map[string]interface{})
+	if aok && bok {
+		d.object(am, bm, path, pointer)
+		return
+	}
+	if path == "" {
+		path = "(root)"
+	}
+	d.add(ChangeChanged, kind, path, pointer, a, b)
+}
+
+func (d *differ) object(a, b map[string]interface{}, path, pointer string) {
+	keys := make([]string, 0, len(a)+len(b))
+	for k := range a {
+		keys = append(keys, k)
+	}
+	for k := range b {
+		if _, ok := a[k]; !ok {
+			keys = append(keys, k)
+		}
+	}
+	sort.Strings(keys)
+
+	for _, k := range keys {
+		p, ptr := joinPath(path, k), pointer+"/"+escapePointer(k)
+		av, aok := a[k]
+		bv, bok := b[k]
+		kind := KindParameter
+		if kl, ok := keyedLists[k]; ok {
+			kind = kl.kind
+		}
+		switch {
+		case !aok:
+			d.add(ChangeAdded, kind, p, ptr, nil, bv)
+		case !bok:
+			d.add(ChangeRemoved, kind, p, ptr, av, nil)
+		default:
+			if kl, ok := keyedLists[k]; ok && d.list(kl, av, bv, p, ptr) {
+				continue
+			}
+			d.value(av, bv, p, ptr, kind)
+		}
+	}
+}
+
+// list compares two keyed lists element by element. It returns false, leaving the
+// comparison to the caller, if either value is not a list of uniquely keyed objects.
+func (d *differ) list(kl keyedList, a, b interface{}, path, pointer string) bool {
+	ak, ok := keyElements(kl, a)
+	if !ok {
+		return false
+	}
+	bk, ok := keyElements(kl, b)
+	if !ok {
+		return false
+	}
+
+	bIndex := make(map[string]int, len(bk))
+	for i, e := range bk {
+		bIndex[e.key] = i
+	}
+	aIndex := make(map[string]int, len(ak))
+	for i, e := range ak {
+		aIndex[e.key] = i
+	}
+
+	// Removals first, from the end, so the indices of earlier elements stay valid.
+	for i := len(ak) - 1; i >= 0; i-- {
+		if _, ok := bIndex[ak[i].key]; !ok {
+			d.add(ChangeRemoved, kl.kind, elementPath(path, ak[i].key), fmt.Sprintf("%s/%d", pointer, i), ak[i].value, nil)
+		}
+	}
+
+	kept := 0
+	for _, e := range ak {
+		j, ok := bIndex[e.key]
+		if !ok {
+			continue
+		}
+		p, ptr := elementPath(path, e.key), fmt.Sprintf("%s/%d", pointer, kept)
+		kept++
+		if reflect.DeepEqual(e.value, bk[j].value) {
+			continue
+		}
+		if kl.atomic {
+			d.add(ChangeChanged, kl.kind, p, ptr, e.value, bk[j].value)
+			continue
+		}
+		d.object(e.value.(map[string]interface{}), bk[j].value.(map[string]interface{}), p, ptr)
+	}
+
+	for _, e := range bk {
+		if _, ok := aIndex[e.key]; !ok {
+			d.add(ChangeAdded, kl.kind, elementPath(path, e.key), pointer+"/-", nil, e.value)
+		}
+	}
+	return true
+}
+
+type keyedElement struct {
+	key   string
+	value interface{}
+}
+
+func keyElements(kl keyedList, v interface{}) ([]keyedElement, bool) {
+	list, ok := v.([]interface{})
+	if !ok {
+		return nil, false
+	}
+	out := make([]keyedElement, 0, len(list))
+	seen := make(map[string]bool, len(list))
+	for _, e := range list {
+		m, ok := e.(map[string]interface{})
+		if !ok {
+			return nil, false
+		}
+		k, ok := kl.key(m)
+		if !ok || seen[k] {
+			return nil, false
+		}
+		seen[k] = true
+		out = append(out, keyedElement{key: k, value: m})
+	}
+	return out, true
+}
+
+func memberKey(name string) func(map[string]interface{}) (string, bool) {
+	return func(m map[string]interface{}) (string, bool) {
+		if v, ok := m[name]; ok {
+			return name + "=" + scalarString(v), true
+		}
+		return "", false
+	}
+}
+
+func subnetKey(m map[string]interface{}) (string, bool) {
+	if id, ok := m["id"]; ok {
+		return "id=" + scalarString(id), true
+	}
+	return memberKey("subnet")(m)
+}
+
+func poolKey(m map[string]interface{}) (string, bool) {
+	pool, ok := m["pool"].(string)
+	if !ok {
+		return "", false
+	}
+	return strings.Join(strings.Fields(pool), ""), true
+}
+
+func pdPoolKey(m map[string]interface{}) (string, bool) {
+	prefix, ok := m["prefix"].(string)
+	if !ok {
+		return "", false
+	}
+	return prefix + "/" + scalarString(m["prefix-len"]), true
+}
+
+func reservationKey(m map[string]interface{}) (string, bool) {
+	for _, id := range []string{"hw-address", "duid", "client-id", "circuit-id", "flex-id"} {
+		if v, ok := m[id]; ok {
+			return id + "=" + scalarString(v), true
+		}
+	}
+	return "", false
+}
+
+// optionKey identifies options and option definitions by space and code, or name if no code is given.
+func optionKey(m map[string]interface{}) (string, bool) {
+	space := ""
+	if s, ok := m["space"].(string); ok {
+		space = s + "/"
+	}
+	if code, ok := m["code"]; ok {
+		return "code=" + space + scalarString(code), true
+	}
+	if name, ok := m["name"]; ok {
+		return "name=" + space + scalarString(name), true
+	}
+	return "", false
+}
+
+func scalarString(v interface{}) string {
+	switch v := v.(type) {
+	case string:
+		return v
+	case json.Number:
+		return v.String()
+	}
+	return string(mustRaw(v))
+}
+
+func joinPath(path, member string) string {
+	if path == "" {
+		return member
+	}
+	return path + "." + member
+}
+
+func elementPath(path, key string) string {
+	return path + "[" + key + "]"
+}
+
+// escapePointer escapes a member name for use in an RFC 6901 JSON Pointer.
+func escapePointer(s string) string {
+	return strings.NewReplacer("~", "~0", "/", "~1").Replace(s)
+}
+
+// toTree encodes v to JSON and decodes it into generic values, keeping numbers exact.
+func toTree(v interface{}) (interface{}, error) {
+	b, err := json.Marshal(v)
+	if err != nil {
+		return nil, err
+	}
+	var out interface{}
+	dec := json.NewDecoder(bytes.NewReader(b))
+	dec.UseNumber()
+	if err := dec.Decode(&out); err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+// mustRaw encodes a value taken from a decoded tree, which always succeeds.
+func mustRaw(v interface{}) json.RawMessage {
+	b, _ := json.Marshal(v)
+	return b
+}
@@ -0,0 +1,172 @@
+package types
+
+import (
+	"encoding/json"
+	"reflect"
+	"strconv"
+	"strings"
+	"testing"
+)
+
+const diffOld = `{
+	"Dhcp4": {
+		"valid-lifetime": 4000,
+		"renew-timer": 1000,
+		"option-data": [{"space": "dhcp4", "code": 6, "data": "192.0.2.1"}],
+		"subnet4": [
+			{"id": 1, "subnet": "192.0.2.0/24", "valid-lifetime": 4000,
+			 "pools": [{"pool": "192.0.2.10 - 192.0.2.20"}, {"pool": "192.0.2.30-192.0.2.40"}],
+			 "reservations": [{"hw-address": "aa:bb:cc:dd:ee:ff", "ip-address": "192.0.2.5"}]},
+			{"id": 2, "subnet": "198.51.100.0/24"}
+		]
+	},
+	"hash": "OLD"
+}`
+
+const diffNew = `{
+	"Dhcp4": {
+		"valid-lifetime": 3600,
+		"rebind-timer": 2000,
+		"option-data": [{"space": "dhcp4", "code": 6, "data": "192.0.2.2"}],
+		"subnet4": [
+			{"id": 1, "subnet": "192.0.2.0/24", "valid-lifetime": 7200,
+			 "pools": [{"pool": "192.0.2.10-192.0.2.20"}, {"pool": "192.0.2.50-192.0.2.60"}],
+			 "reservations": [{"hw-address": "aa:bb:cc:dd:ee:ff", "ip-address": "192.0.2.6"}]},
+			{"id": 3, "subnet": "203.0.113.0/24"}
+		]
+	},
+	"hash": "NEW"
+}`
+
+// TestDiffConfig matches subnets, pools, reservations and options by identity.
+func TestDiffConfig(t *testing.T) {
+	t.Parallel()
+
+	diff, err := DiffConfig(json.RawMessage(diffOld), json.RawMessage(diffNew))
+	if err != nil {
+		t.Fatalf("DiffConfig() error = %v", err)
+	}
+
+	type change struct {
+		op      ChangeOp
+		kind    ChangeKind
+		path    string
+		pointer string
+	}
+	want := []change{
+		{ChangeChanged, KindOption, "Dhcp4.option-data[code=dhcp4/6]", "/Dhcp4/option-data/0"},
+		{ChangeAdded, KindParameter, "Dhcp4.rebind-timer", "/Dhcp4/rebind-timer"},
+		{ChangeRemoved, KindParameter, "Dhcp4.renew-timer", "/Dhcp4/renew-timer"},
+		{ChangeRemoved, KindSubnet, "Dhcp4.subnet4[id=2]", "/Dhcp4/subnet4/1"},
+		{ChangeRemoved, KindPool, "Dhcp4.subnet4[id=1].pools[192.0.2.30-192.0.2.40]", "/Dhcp4/subnet4/0/pools/1"},
+		{ChangeChanged, KindParameter, "Dhcp4.subnet4[id=1].pools[192.0.2.10-192.0.2.20].pool", "/Dhcp4/subnet4/0/pools/0/pool"},
+		{ChangeAdded, KindPool, "Dhcp4.subnet4[id=1].pools[192.0.2.50-192.0.2.60]", "/Dhcp4/subnet4/0/pools/-"},
+		{ChangeChanged, KindReservation, "Dhcp4.subnet4[id=1].reservations[hw-address=aa:bb:cc:dd:ee:ff]", "/Dhcp4/subnet4/0/reservations/0"},
+		{ChangeChanged, KindParameter, "Dhcp4.subnet4[id=1].valid-lifetime", "/Dhcp4/subnet4/0/valid-lifetime"},
+		{ChangeAdded, KindSubnet, "Dhcp4.subnet4[id=3]", "/Dhcp4/subnet4/-"},
+		{ChangeChanged, KindParameter, "Dhcp4.valid-lifetime", "/Dhcp4/valid-lifetime"},
+	}
+	var got []change
+	for _, c := range diff {
+		got = append(got, change{c.Op, c.Kind, c.Path, c.Pointer})
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Fatalf("DiffConfig() =\n%v\nwant\n%v", got, want)
+	}
+
+	if s := diff.String(); !strings.Contains(s, "~ Dhcp4.valid-lifetime: 4000 -> 3600\n") ||
+		!strings.Contains(s, `+ Dhcp4.subnet4[id=3]: {"id":3,"subnet":"203.0.113.0/24"}`) {
+		t.Errorf("String() =\n%s", s)
+	}
+
+	// Applying the patch to the old document must yield the new one.
+	var doc interface{}
+	if err := json.Unmarshal([]byte(diffOld), &doc); err != nil {
+		t.Fatal(err)
+	}
+	for _, op := range diff.Patch() {
+		doc = applyPatchOp(t, doc, op)
+	}
+	var wantDoc interface{}
+	if err := json.Unmarshal([]byte(diffNew), &wantDoc); err != nil {
+		t.Fatal(err)
+	}
+	doc.(map[string]interface{})["hash"] = "NEW"
+	if !reflect.DeepEqual(doc, wantDoc) {
+		got, _ := json.Marshal(doc)
+		t.Errorf("patched document = %s", got)
+	}
+
+	patch, err := diff.JSONPatch()
+	if err != nil || !strings.HasPrefix(string(patch), `[{"op":"replace","path":"/Dhcp4/option-data/0","value":{`) {
+		t.Errorf("JSONPatch() = %s, %v", patch, err)
+	}
+}
+
+// TestDiffConfigUnkeyed compares lists with duplicate or missing keys as whole values.
+func TestDiffConfigUnkeyed(t *testing.T) {
+	t.Parallel()
+
+	from := map[string]any{"subnet4": []any{map[string]any{"id": 1}, map[string]any{"id": 1}}, "interfaces": []any{"eth0"}}
+	to := map[string]any{"subnet4": []any{map[string]any{"id": 1}}, "interfaces": []any{"eth0", "eth1"}}
+
+	diff, err := DiffConfig(from, to)
+	if err != nil {
+		t.Fatalf("DiffConfig() error = %v", err)
+	}
+	if len(diff) != 2 || diff[0].Path != "interfaces" || diff[1].Path != "subnet4" || diff[1].Kind != KindSubnet {
+		t.Errorf("DiffConfig() = %+v", diff)
+	}
+
+	if diff, err := DiffConfig(from, from); err != nil || len(diff) != 0 {
+		t.Errorf("DiffConfig(x, x) = %+v, %v", diff, err)
+	}
+}
+
+// applyPatchOp applies a single add, remove or replace operation.
+func applyPatchOp(t *testing.T, doc interface{}, op PatchOp) interface{} {
+	t.Helper()
+	tokens := strings.Split(op.Path, "/")[1:]
+	var value interface{}
+	if op.Value != nil {
+		if err := json.Unmarshal(op.Value, &value); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	var apply func(node interface{}, tokens []string) interface{}
+	apply = func(node interface{}, tokens []string) interface{} {
+		tok := strings.NewReplacer("~1", "/", "~0", "~").Replace(tokens[0])
+		switch n := node.(type) {
+		case map[string]interface{}:
+			if len(tokens) > 1 {
+				n[tok] = apply(n[tok], tokens[1:])
+			} else if op.Op == "remove" {
+				delete(n, tok)
+			} else {
+				n[tok] = value
+			}
+			return n
+		case []interface{}:
+			if tok == "-" {
+				return append(n, value)
+			}
+			i, err := strconv.Atoi(tok)
+			if err != nil || i >= len(n) {
+				t.Fatalf("bad index in %s", op.Path)
+			}
+			switch {
+			case len(tokens) > 1:
+				n[i] = apply(n[i], tokens[1:])
+			case op.Op == "remove":
+				return append(n[:i], n[i+1:]...)
+			default:
+				n[i] = value
+			}
+			return n
+		}
+		t.Fatalf("cannot apply %s", op.Path)
+		return nil
+	}
+	return apply(doc, tokens)
+}
@@ -0,0 +1,100 @@
+package types
+
+// HAState is the state of a server in a High Availability relationship, as reported by
+// status-get and ha-heartbeat.
+type HAState string
+
+// HA states defined by the ha hook library.
+const (
+	HAStateBackup                HAState = "backup"
+	HAStateCommunicationRecovery HAState = "communication-recovery"
+	HAStateHotStandby            HAState = "hot-standby"
+	HAStateLoadBalancing         HAState = "load-balancing"
+	HAStateInMaintenance         HAState = "in-maintenance"
+	HAStatePartnerDown           HAState = "partner-down"
+	HAStatePartnerInMaintenance  HAState = "partner-in-maintenance"
+	HAStatePassiveBackup         HAState = "passive-backup"
+	HAStateReady                 HAState = "ready"
+	HAStateSyncing               HAState = "syncing"
+	HAStateTerminated            HAState = "terminated"
+	HAStateWaiting               HAState = "waiting"
+	HAStateUnavailable           HAState = "unavailable" // The partner's state is unknown
+)
+
+// HARole is the role of a server in a High Availability relationship.
+type HARole string
+
+// HA roles defined by the ha hook library.
+const (
+	HARolePrimary   HARole = "primary"
+	HARoleSecondary HARole = "secondary"
+	HARoleStandby   HARole = "standby"
+	HARoleBackup    HARole = "backup"
+)
+
+// HAStatus is one relationship in the high-availability list of status-get. Servers
+// configured in hub-and-spoke mode report one relationship per partner.
+type HAStatus struct {
+	Mode    string    `json:"ha-mode"` // "load-balancing", "hot-standby" or "passive-backup"
+	Servers HAServers `json:"ha-servers"`
+}
+
+// HAServers describes the two ends of an HA relationship.
+type HAServers struct {
+	Local  HALocalServer  `json:"local"`
+	Remote HARemoteServer `json:"remote"`
+}
+
+// HALocalServer is the state of the server that answered status-get.
+type HALocalServer struct {
+	ServerName string   `json:"server-name"`
+	Role       HARole   `json:"role"`
+	State      HAState  `json:"state"`
+	Scopes     []string `json:"scopes"` // Scopes the server is serving
+	SystemTime string   `json:"system-time,omitempty"`
+}
+
+// HARemoteServer is the partner as last seen by the local server. Age is the time in
+// seconds since the partner was last heard from. While communication is interrupted,
+// UnackedClients counts the clients the partner has failed to answer; the local server
+// transitions to partner-down once UnackedClientsLeft reaches zero.
+type HARemoteServer struct {
+	ServerName               string   `json:"server-name"`
+	Role                     HARole   `json:"role"`
+	LastState                HAState  `json:"last-state"`
+	LastScopes               []string `json:"last-scopes"`
+	Age                      int      `json:"age"`
+	InTouch                  bool     `json:"in-touch"`
+	CommunicationInterrupted bool     `json:"communication-interrupted"`
+	ConnectingClients        int      `json:"connecting-clients"`
+	UnackedClients           int      `json:"unacked-clients"`
+	UnackedClientsLeft       int      `json:"unacked-clients-left"`
+	AnalyzedPackets          int      `json:"analyzed-packets"`
+	ClockSkew                int      `json:"clock-skew,omitempty"`
+	SystemTime               string   `json:"system-time,omitempty"`
+}
+
+// HAHeartbeat is the arguments block returned by ha-heartbeat.
+type HAHeartbeat struct {
+	State             HAState  `json:"state"`
+	DateTime          string   `json:"date-time"` // The server's clock, in HTTP date format
+	Scopes            []string `json:"scopes"`
+	UnsentUpdateCount uint64   `json:"unsent-update-count"`
+}
+
+// HARelationship returns the relationship whose remote server is partner, or the only
+// relationship when partner is empty and the server has exactly one.
+func HARelationship(statuses []HAStatus, partner string) (HAStatus, bool) {
+	if partner == "" {
+		if len(statuses) == 1 {
+			return statuses[0], true
+		}
+		return HAStatus{}, false
+	}
+	for _, s := range statuses {
+		if s.Servers.Remote.ServerName == partner {
+			return s, true
+		}
+	}
+	return HAStatus{}, false
+}
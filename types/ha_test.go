@@ -0,0 +1,26 @@
+package types
+
+import "testing"
+
+// TestHARelationship picks a relationship by partner name, or the only one.
+func TestHARelationship(t *testing.T) {
+	t.Parallel()
+
+	rel := func(partner string) HAStatus {
+		return HAStatus{Mode: "hot-standby", Servers: HAServers{Remote: HARemoteServer{ServerName: partner}}}
+	}
+	hub := []HAStatus{rel("spoke1"), rel("spoke2")}
+
+	if got, ok := HARelationship(hub, "spoke2"); !ok || got.Servers.Remote.ServerName != "spoke2" {
+		t.Errorf("HARelationship(hub, spoke2) = %+v, %v", got, ok)
+	}
+	if _, ok := HARelationship(hub, ""); ok {
+		t.Error("HARelationship(hub, \"\") picked one of several relationships")
+	}
+	if _, ok := HARelationship(hub, "spoke3"); ok {
+		t.Error("HARelationship(hub, spoke3) found an unknown partner")
+	}
+	if got, ok := HARelationship(hub[:1], ""); !ok || got.Servers.Remote.ServerName != "spoke1" {
+		t.Errorf("HARelationship(single, \"\") = %+v, %v", got, ok)
+	}
+}
@@ -0,0 +1,125 @@
+package types
+
+import "github.com/rannday/kea-api/internal/utils"
+
+// JSON codecs keeping the config types of this package lossless, Extra included; see utils.DecodeObject.
+
+func (s *SocketConfig) UnmarshalJSON(data []byte) error {
+	type plain SocketConfig
+	return utils.DecodeObject(data, (*plain)(s), &s.Extra, &s.absent)
+}
+
+func (s SocketConfig) MarshalJSON() ([]byte, error) {
+	type plain SocketConfig
+	return utils.EncodeObject(plain(s), s.Extra, s.absent)
+}
+
+func (l *LoggerConfig) UnmarshalJSON(data []byte) error {
+	type plain LoggerConfig
+	return utils.DecodeObject(data, (*plain)(l), &l.Extra, &l.absent)
+}
+
+func (l LoggerConfig) MarshalJSON() ([]byte, error) {
+	type plain LoggerConfig
+	return utils.EncodeObject(plain(l), l.Extra, l.absent)
+}
+
+func (l *LogOutputOption) UnmarshalJSON(data []byte) error {
+	type plain LogOutputOption
+	return utils.DecodeObject(data, (*plain)(l), &l.Extra, &l.absent)
+}
+
+func (l LogOutputOption) MarshalJSON() ([]byte, error) {
+	type plain LogOutputOption
+	return utils.EncodeObject(plain(l), l.Extra, l.absent)
+}
+
+func (h *HookLibrary) UnmarshalJSON(data []byte) error {
+	type plain HookLibrary
+	return utils.DecodeObject(data, (*plain)(h), &h.Extra, &h.absent)
+}
+
+func (h HookLibrary) MarshalJSON() ([]byte, error) {
+	type plain HookLibrary
+	return utils.EncodeObject(plain(h), h.Extra, h.absent)
+}
+
+func (i *InterfacesConfig) UnmarshalJSON(data []byte) error {
+	type plain InterfacesConfig
+	return utils.DecodeObject(data, (*plain)(i), &i.Extra, &i.absent)
+}
+
+func (i InterfacesConfig) MarshalJSON() ([]byte, error) {
+	type plain InterfacesConfig
+	return utils.EncodeObject(plain(i), i.Extra, i.absent)
+}
+
+func (s *SanityChecks) UnmarshalJSON(data []byte) error {
+	type plain SanityChecks
+	return utils.DecodeObject(data, (*plain)(s), &s.Extra, &s.absent)
+}
+
+func (s SanityChecks) MarshalJSON() ([]byte, error) {
+	type plain SanityChecks
+	return utils.EncodeObject(plain(s), s.Extra, s.absent)
+}
+
+func (d *DatabaseConfig) UnmarshalJSON(data []byte) error {
+	type plain DatabaseConfig
+	return utils.DecodeObject(data, (*plain)(d), &d.Extra, &d.absent)
+}
+
+func (d DatabaseConfig) MarshalJSON() ([]byte, error) {
+	type plain DatabaseConfig
+	return utils.EncodeObject(plain(d), d.Extra, d.absent)
+}
+
+func (l *LeaseDatabaseConfig) UnmarshalJSON(data []byte) error {
+	type plain LeaseDatabaseConfig
+	return utils.DecodeObject(data, (*plain)(l), &l.Extra, &l.absent)
+}
+
+func (l LeaseDatabaseConfig) MarshalJSON() ([]byte, error) {
+	type plain LeaseDatabaseConfig
+	return utils.EncodeObject(plain(l), l.Extra, l.absent)
+}
+
+func (m *MultiThreadingConfig) UnmarshalJSON(data []byte) error {
+	type plain MultiThreadingConfig
+	return utils.DecodeObject(data, (*plain)(m), &m.Extra, &m.absent)
+}
+
+func (m MultiThreadingConfig) MarshalJSON() ([]byte, error) {
+	type plain MultiThreadingConfig
+	return utils.EncodeObject(plain(m), m.Extra, m.absent)
+}
+
+func (o *OptionData) UnmarshalJSON(data []byte) error {
+	type plain OptionData
+	return utils.DecodeObject(data, (*plain)(o), &o.Extra, &o.absent)
+}
+
+func (o OptionData) MarshalJSON() ([]byte, error) {
+	type plain OptionData
+	return utils.EncodeObject(plain(o), o.Extra, o.absent)
+}
+
+func (o *OptionDef) UnmarshalJSON(data []byte) error {
+	type plain OptionDef
+	return utils.DecodeObject(data, (*plain)(o), &o.Extra, &o.absent)
+}
+
+func (o OptionDef) MarshalJSON() ([]byte, error) {
+	type plain OptionDef
+	return utils.EncodeObject(plain(o), o.Extra, o.absent)
+}
+
+func (r *Relay) UnmarshalJSON(data []byte) error {
+	type plain Relay
+	return utils.DecodeObject(data, (*plain)(r), &r.Extra, &r.absent)
+}
+
+func (r Relay) MarshalJSON() ([]byte, error) {
+	type plain Relay
+	return utils.EncodeObject(plain(r), r.Extra, r.absent)
+}
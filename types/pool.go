@@ -0,0 +1,41 @@
+package types
+
+import (
+	"fmt"
+	"net/netip"
+	"strings"
+)
+
+// ParsePool returns the first and last address of an address pool, given either as
+// "first - last" or in CIDR notation (e.g. "192.0.2.0/26").
+func ParsePool(pool string) (first, last netip.Addr, err error) {
+	if lo, hi, ok := strings.Cut(pool, "-"); ok {
+		if first, err = netip.ParseAddr(strings.TrimSpace(lo)); err != nil {
+			return netip.Addr{}, netip.Addr{}, fmt.Errorf("invalid pool %q: %w", pool, err)
+		}
+		if last, err = netip.ParseAddr(strings.TrimSpace(hi)); err != nil {
+			return netip.Addr{}, netip.Addr{}, fmt.Errorf("invalid pool %q: %w", pool, err)
+		}
+		if first.Is4() != last.Is4() || last.Less(first) {
+			return netip.Addr{}, netip.Addr{}, fmt.Errorf("invalid pool %q: bad address range", pool)
+		}
+		return first, last, nil
+	}
+
+	prefix, err := netip.ParsePrefix(strings.TrimSpace(pool))
+	if err != nil {
+		return netip.Addr{}, netip.Addr{}, fmt.Errorf("invalid pool %q: %w", pool, err)
+	}
+	prefix = prefix.Masked()
+	return prefix.Addr(), lastAddr(prefix), nil
+}
+
+// lastAddr returns the highest address in p.
+func lastAddr(p netip.Prefix) netip.Addr {
+	b := p.Addr().AsSlice()
+	for i := p.Bits(); i < len(b)*8; i++ {
+		b[i/8] |= 0x80 >> (i % 8)
+	}
+	addr, _ := netip.AddrFromSlice(b)
+	return addr
+}
@@ -0,0 +1,38 @@
+package types
+
+import (
+	"net/netip"
+	"testing"
+)
+
+// TestParsePool accepts ranges and CIDR notation and rejects malformed pools.
+func TestParsePool(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		pool, first, last string
+	}{
+		{"192.0.2.100 - 192.0.2.199", "192.0.2.100", "192.0.2.199"},
+		{"192.0.2.100-192.0.2.100", "192.0.2.100", "192.0.2.100"},
+		{"192.0.2.64/26", "192.0.2.64", "192.0.2.127"},
+		{"192.0.2.70/26", "192.0.2.64", "192.0.2.127"},
+		{"2001:db8:1::/64", "2001:db8:1::", "2001:db8:1::ffff:ffff:ffff:ffff"},
+		{"2001:db8:1::10 - 2001:db8:1::ff", "2001:db8:1::10", "2001:db8:1::ff"},
+	}
+	for _, tt := range tests {
+		first, last, err := ParsePool(tt.pool)
+		if err != nil {
+			t.Errorf("ParsePool(%q) error = %v", tt.pool, err)
+			continue
+		}
+		if first != netip.MustParseAddr(tt.first) || last != netip.MustParseAddr(tt.last) {
+			t.Errorf("ParsePool(%q) = %v - %v, want %s - %s", tt.pool, first, last, tt.first, tt.last)
+		}
+	}
+
+	for _, bad := range []string{"", "192.0.2.1", "192.0.2.200 - 192.0.2.100", "192.0.2.1 - 2001:db8::1", "192.0.2.0/33"} {
+		if _, _, err := ParsePool(bad); err == nil {
+			t.Errorf("ParsePool(%q) succeeded, want error", bad)
+		}
+	}
+}
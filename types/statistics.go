@@ -0,0 +1,262 @@
+package types
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// StatisticTimeLayout is the timestamp format of statistic samples: Kea always writes
+// six fractional digits.
+const StatisticTimeLayout = "2006-01-02 15:04:05.000000"
+
+// Sample is one observation of a statistic. Value holds an int64, float64,
+// time.Duration or string depending on the statistic's type. Kea reports
+// timestamps in the server's local time without a zone; they are parsed as UTC.
+type Sample struct {
+	Value interface{}
+	Time  time.Time
+}
+
+// durationPattern matches Kea's duration statistics, e.g. "00:00:01.500000".
+var durationPattern = regexp.MustCompile(`^(-)?(\d+):(\d{2}):(\d{2})(?:\.(\d{1,9}))?$`)
+
+// UnmarshalJSON decodes a [value, "timestamp"] pair.
+func (s *Sample) UnmarshalJSON(data []byte) error {
+	var pair []json.RawMessage
+	if err := json.Unmarshal(data, &pair); err != nil {
+		return fmt.Errorf("statistic sample: %w", err)
+	}
+	if len(pair) != 2 {
+		return fmt.Errorf("statistic sample: expected [value, timestamp], got %d elements", len(pair))
+	}
+
+	value, err := decodeSampleValue(pair[0])
+	if err != nil {
+		return err
+	}
+
+	var ts string
+	if err := json.Unmarshal(pair[1], &ts); err != nil {
+		return fmt.Errorf("statistic sample timestamp: %w", err)
+	}
+	t, err := time.Parse(StatisticTimeLayout, ts)
+	if err != nil {
+		return fmt.Errorf("statistic sample timestamp: %w", err)
+	}
+
+	s.Value, s.Time = value, t
+	return nil
+}
+
+// MarshalJSON encodes the sample back into Kea's [value, "timestamp"] form.
+func (s Sample) MarshalJSON() ([]byte, error) {
+	value := s.Value
+	if d, ok := value.(time.Duration); ok {
+		value = formatStatDuration(d)
+	}
+	return json.Marshal([]interface{}{value, s.Time.Format(StatisticTimeLayout)})
+}
+
+// Int64 returns the value of an integer sample.
+func (s Sample) Int64() (int64, bool) {
+	v, ok := s.Value.(int64)
+	return v, ok
+}
+
+// Float64 returns the value of an integer or float sample, or a duration in seconds.
+func (s Sample) Float64() (float64, bool) {
+	switch v := s.Value.(type) {
+	case int64:
+		return float64(v), true
+	case float64:
+		return v, true
+	case time.Duration:
+		return v.Seconds(), true
+	}
+	return 0, false
+}
+
+// Duration returns the value of a duration sample.
+func (s Sample) Duration() (time.Duration, bool) {
+	v, ok := s.Value.(time.Duration)
+	return v, ok
+}
+
+// decodeSampleValue picks the Go type for a raw sample value.
+func decodeSampleValue(raw json.RawMessage) (interface{}, error) {
+	raw = bytes.TrimSpace(raw)
+	if len(raw) > 0 && raw[0] == '"' {
+		var str string
+		if err := json.Unmarshal(raw, &str); err != nil {
+			return nil, fmt.Errorf("statistic sample value: %w", err)
+		}
+		if d, ok := parseStatDuration(str); ok {
+			return d, nil
+		}
+		return str, nil
+	}
+
+	if !bytes.ContainsAny(raw, ".eE") {
+		if i, err := strconv.ParseInt(string(raw), 10, 64); err == nil {
+			return i, nil
+		}
+	}
+	f, err := strconv.ParseFloat(string(raw), 64)
+	if err != nil {
+		return nil, fmt.Errorf("statistic sample value %s: %w", raw, err)
+	}
+	return f, nil
+}
+
+// parseStatDuration parses Kea's "HH:MM:SS.ffffff" duration format.
+func parseStatDuration(s string) (time.Duration, bool) {
+	m := durationPattern.FindStringSubmatch(s)
+	if m == nil {
+		return 0, false
+	}
+	h, _ := strconv.ParseInt(m[2], 10, 64)
+	mins, _ := strconv.ParseInt(m[3], 10, 64)
+	sec, _ := strconv.ParseInt(m[4], 10, 64)
+	d := time.Duration(h)*time.Hour + time.Duration(mins)*time.Minute + time.Duration(sec)*time.Second
+	if m[5] != "" {
+		frac := m[5] + strings.Repeat("0", 9-len(m[5]))
+		ns, _ := strconv.ParseInt(frac, 10, 64)
+		d += time.Duration(ns)
+	}
+	if m[1] == "-" {
+		d = -d
+	}
+	return d, true
+}
+
+// formatStatDuration renders d in Kea's "HH:MM:SS.ffffff" duration format.
+func formatStatDuration(d time.Duration) string {
+	sign := ""
+	if d < 0 {
+		sign, d = "-", -d
+	}
+	h := d / time.Hour
+	d -= h * time.Hour
+	m := d / time.Minute
+	d -= m * time.Minute
+	s := d / time.Second
+	d -= s * time.Second
+	return fmt.Sprintf("%s%02d:%02d:%02d.%06d", sign, h, m, s, d/time.Microsecond)
+}
+
+// Statistic is a named statistic with its samples, newest first as reported by Kea.
+type Statistic struct {
+	Name    string
+	Samples []Sample
+}
+
+// Latest returns the most recent sample.
+func (s Statistic) Latest() (Sample, bool) {
+	if len(s.Samples) == 0 {
+		return Sample{}, false
+	}
+	return s.Samples[0], true
+}
+
+// StatisticSet is the arguments block of statistic-get and statistic-get-all: samples keyed by name.
+type StatisticSet map[string][]Sample
+
+// Statistics returns the set as a slice sorted by name.
+func (s StatisticSet) Statistics() []Statistic {
+	out := make([]Statistic, 0, len(s))
+	for name, samples := range s {
+		out = append(out, Statistic{Name: name, Samples: samples})
+	}
+	sort.Slice(out, func(i, j int) bool { return out[i].Name < out[j].Name })
+	return out
+}
+
+// StatisticScope is one bracketed component of a statistic name, such as subnet[1] or pool[0].
+type StatisticScope struct {
+	Kind string // e.g. "subnet", "pool", "pd-pool", "key"
+	ID   string // the text between the brackets
+}
+
+// StatisticName is a parsed statistic name such as "subnet[1].pool[0].assigned-addresses".
+type StatisticName struct {
+	Scopes []StatisticScope
+	Metric string
+}
+
+// scopePattern matches a leading "kind[id]." component. IDs may contain dots (DNS key names).
+var scopePattern = regexp.MustCompile(`^([a-z0-9-]+)\[([^\]]*)\]\.`)
+
+// ParseStatisticName splits a statistic name into its scopes and metric.
+func ParseStatisticName(name string) (StatisticName, error) {
+	var n StatisticName
+	rest := name
+	for {
+		m := scopePattern.FindStringSubmatch(rest)
+		if m == nil {
+			break
+		}
+		n.Scopes = append(n.Scopes, StatisticScope{Kind: m[1], ID: m[2]})
+		rest = rest[len(m[0]):]
+	}
+	if rest == "" || strings.ContainsAny(rest, "[]") {
+		return StatisticName{}, fmt.Errorf("invalid statistic name %q", name)
+	}
+	n.Metric = rest
+	return n, nil
+}
+
+// String rebuilds the statistic name.
+func (n StatisticName) String() string {
+	var b strings.Builder
+	for _, s := range n.Scopes {
+		fmt.Fprintf(&b, "%s[%s].", s.Kind, s.ID)
+	}
+	b.WriteString(n.Metric)
+	return b.String()
+}
+
+// SubnetID returns the subnet the statistic belongs to, if any.
+func (n StatisticName) SubnetID() (int, bool) {
+	return n.scopeInt("subnet")
+}
+
+// PoolID returns the address pool the statistic belongs to, if any.
+func (n StatisticName) PoolID() (int, bool) {
+	return n.scopeInt("pool")
+}
+
+// PDPoolID returns the prefix delegation pool the statistic belongs to, if any.
+func (n StatisticName) PDPoolID() (int, bool) {
+	return n.scopeInt("pd-pool")
+}
+
+func (n StatisticName) scopeInt(kind string) (int, bool) {
+	for _, s := range n.Scopes {
+		if s.Kind == kind {
+			id, err := strconv.Atoi(s.ID)
+			return id, err == nil
+		}
+	}
+	return 0, false
+}
+
+// SubnetStatistic returns the name of a per-subnet statistic, e.g. "subnet[1].assigned-addresses".
+func SubnetStatistic(subnetID int, metric string) string {
+	return fmt.Sprintf("subnet[%d].%s", subnetID, metric)
+}
+
+// PoolStatistic returns the name of a per-pool statistic, e.g. "subnet[1].pool[0].assigned-addresses".
+func PoolStatistic(subnetID, poolID int, metric string) string {
+	return fmt.Sprintf("subnet[%d].pool[%d].%s", subnetID, poolID, metric)
+}
+
+// PDPoolStatistic returns the name of a per-prefix-pool statistic, e.g. "subnet[1].pd-pool[0].assigned-pds".
+func PDPoolStatistic(subnetID, poolID int, metric string) string {
+	return fmt.Sprintf("subnet[%d].pd-pool[%d].%s", subnetID, poolID, metric)
+}
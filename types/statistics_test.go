@@ -0,0 +1,168 @@
+package types
+
+import (
+	"encoding/json"
+	"reflect"
+	"testing"
+	"time"
+)
+
+// TestSampleUnmarshal decodes each kind of sample value Kea reports.
+func TestSampleUnmarshal(t *testing.T) {
+	t.Parallel()
+
+	ts := time.Date(2019, 7, 30, 10, 11, 19, 498739000, time.UTC)
+	tests := []struct {
+		name string
+		raw  string
+		want interface{}
+	}{
+		{"integer", `[125, "2019-07-30 10:11:19.498739"]`, int64(125)},
+		{"float", `[0.25, "2019-07-30 10:11:19.498739"]`, 0.25},
+		{"duration", `["00:01:02.500000", "2019-07-30 10:11:19.498739"]`, time.Minute + 2500*time.Millisecond},
+		{"string", `["ready", "2019-07-30 10:11:19.498739"]`, "ready"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			var s Sample
+			if err := json.Unmarshal([]byte(tt.raw), &s); err != nil {
+				t.Fatalf("Unmarshal() error = %v", err)
+			}
+			if !reflect.DeepEqual(s.Value, tt.want) {
+				t.Errorf("Value = %#v, want %#v", s.Value, tt.want)
+			}
+			if !s.Time.Equal(ts) {
+				t.Errorf("Time = %v, want %v", s.Time, ts)
+			}
+
+			out, err := json.Marshal(s)
+			if err != nil {
+				t.Fatalf("Marshal() error = %v", err)
+			}
+			var back Sample
+			if err := json.Unmarshal(out, &back); err != nil || !reflect.DeepEqual(back, s) {
+				t.Errorf("round trip = %#v, %v, want %#v", back, err, s)
+			}
+		})
+	}
+}
+
+// TestSampleRoundTrip re-encodes samples exactly as Kea wrote them, keeping all six
+// fractional digits of the timestamp.
+func TestSampleRoundTrip(t *testing.T) {
+	t.Parallel()
+
+	for _, raw := range []string{
+		`[125,"2019-07-30 10:11:19.498739"]`,
+		`[3,"2019-07-30 10:11:19.500000"]`,
+		`["00:01:02.500000","2019-07-30 10:11:19.000000"]`,
+	} {
+		var s Sample
+		if err := json.Unmarshal([]byte(raw), &s); err != nil {
+			t.Fatalf("Unmarshal(%s) error = %v", raw, err)
+		}
+		out, err := json.Marshal(s)
+		if err != nil {
+			t.Fatalf("Marshal() error = %v", err)
+		}
+		if string(out) != raw {
+			t.Errorf("Marshal() = %s, want %s", out, raw)
+		}
+	}
+}
+
+// TestSampleUnmarshalInvalid rejects malformed samples.
+func TestSampleUnmarshalInvalid(t *testing.T) {
+	t.Parallel()
+
+	for _, raw := range []string{`125`, `[125]`, `[125, "yesterday"]`, `[true, "2019-07-30 10:11:19"]`} {
+		var s Sample
+		if err := json.Unmarshal([]byte(raw), &s); err == nil {
+			t.Errorf("Unmarshal(%s) succeeded, want error", raw)
+		}
+	}
+}
+
+// TestStatisticSet sorts statistics by name and exposes the newest sample.
+func TestStatisticSet(t *testing.T) {
+	t.Parallel()
+
+	var set StatisticSet
+	raw := `{
+		"pkt4-received": [[125, "2019-07-30 10:11:19.498739"], [100, "2019-07-30 10:11:09.498739"]],
+		"pkt4-ack-sent": [[42, "2019-07-30 10:11:19.498739"]]
+	}`
+	if err := json.Unmarshal([]byte(raw), &set); err != nil {
+		t.Fatalf("Unmarshal() error = %v", err)
+	}
+
+	stats := set.Statistics()
+	if len(stats) != 2 || stats[0].Name != "pkt4-ack-sent" || stats[1].Name != "pkt4-received" {
+		t.Fatalf("Statistics() = %+v", stats)
+	}
+	latest, ok := stats[1].Latest()
+	if v, _ := latest.Int64(); !ok || v != 125 {
+		t.Errorf("Latest() = %+v, %v", latest, ok)
+	}
+	if _, ok := (Statistic{}).Latest(); ok {
+		t.Error("Latest() on an empty statistic reported a sample")
+	}
+}
+
+// TestParseStatisticName splits names into scopes and metric.
+func TestParseStatisticName(t *testing.T) {
+	t.Parallel()
+
+	n, err := ParseStatisticName("subnet[1].pool[0].assigned-addresses")
+	if err != nil {
+		t.Fatalf("ParseStatisticName() error = %v", err)
+	}
+	if id, ok := n.SubnetID(); !ok || id != 1 {
+		t.Errorf("SubnetID() = %d, %v", id, ok)
+	}
+	if id, ok := n.PoolID(); !ok || id != 0 {
+		t.Errorf("PoolID() = %d, %v", id, ok)
+	}
+	if _, ok := n.PDPoolID(); ok {
+		t.Error("PDPoolID() reported a prefix pool")
+	}
+	if n.Metric != "assigned-addresses" || n.String() != "subnet[1].pool[0].assigned-addresses" {
+		t.Errorf("ParseStatisticName() = %+v", n)
+	}
+
+	n, err = ParseStatisticName("key[foo.example.org.].update-sent")
+	if err != nil {
+		t.Fatalf("ParseStatisticName() error = %v", err)
+	}
+	want := StatisticName{Scopes: []StatisticScope{{Kind: "key", ID: "foo.example.org."}}, Metric: "update-sent"}
+	if !reflect.DeepEqual(n, want) {
+		t.Errorf("ParseStatisticName() = %+v, want %+v", n, want)
+	}
+
+	for _, bad := range []string{"", "subnet[1].", "subnet[1.assigned"} {
+		if _, err := ParseStatisticName(bad); err == nil {
+			t.Errorf("ParseStatisticName(%q) succeeded, want error", bad)
+		}
+	}
+}
+
+// TestStatisticNameBuilders round-trips the per-subnet and per-pool name helpers.
+func TestStatisticNameBuilders(t *testing.T) {
+	t.Parallel()
+
+	tests := map[string]string{
+		SubnetStatistic(3, "total-addresses"):     "subnet[3].total-addresses",
+		PoolStatistic(3, 1, "assigned-addresses"): "subnet[3].pool[1].assigned-addresses",
+		PDPoolStatistic(4, 0, "assigned-pds"):     "subnet[4].pd-pool[0].assigned-pds",
+	}
+	for got, want := range tests {
+		if got != want {
+			t.Errorf("got %q, want %q", got, want)
+		}
+		if n, err := ParseStatisticName(got); err != nil || n.String() != want {
+			t.Errorf("ParseStatisticName(%q) = %v, %v", got, n, err)
+		}
+	}
+}
@@ -0,0 +1,129 @@
+package types
+
+import (
+	"fmt"
+	"strings"
+)
+
+// Severity grades a Diagnostic.
+type Severity int
+
+const (
+	SeverityError   Severity = iota // Kea rejects the configuration or will misbehave
+	SeverityWarning                 // Kea accepts the configuration but likely not as intended
+)
+
+// String returns "error" or "warning".
+func (s Severity) String() string {
+	if s == SeverityWarning {
+		return "warning"
+	}
+	return "error"
+}
+
+// DiagnosticCode classifies a Diagnostic.
+type DiagnosticCode string
+
+const (
+	DiagInvalidValue             DiagnosticCode = "invalid-value"              // unparsable subnet, pool, address or prefix
+	DiagDuplicateSubnetID        DiagnosticCode = "duplicate-subnet-id"        // two subnets share an id
+	DiagPoolOutsideSubnet        DiagnosticCode = "pool-outside-subnet"        // a pool is not within its subnet
+	DiagPoolOverlap              DiagnosticCode = "pool-overlap"               // two pools share addresses or prefixes
+	DiagReservationOutsideSubnet DiagnosticCode = "reservation-outside-subnet" // a reserved address is not within its subnet
+	DiagReservationInPool        DiagnosticCode = "reservation-in-pool"        // a reservation falls in a pool while reservations-out-of-pool is set
+	DiagTimerOrder               DiagnosticCode = "timer-order"                // renew/rebind timers or t1/t2 percentages out of order
+	DiagUnknownOption            DiagnosticCode = "unknown-option"             // option-data names an option that is neither standard nor defined
+	DiagControlSocketMismatch    DiagnosticCode = "control-socket-mismatch"    // the server and Control Agent disagree on the control socket
+)
+
+// Diagnostic is one problem found by an offline validator such as dhcp4.Validate.
+type Diagnostic struct {
+	Severity Severity
+	Code     DiagnosticCode
+	Pointer  string   // RFC 6901 pointer to the offending value, e.g. "/Dhcp4/subnet4/0/pools/1"
+	Pos      Position // position in the file; zero unless validated from a file, e.g. by dhcp4.ValidateConfigFile
+	Message  string
+}
+
+// Position locates a Diagnostic in a configuration file. Line and Col are 1-based; Col counts bytes.
+type Position struct {
+	File string
+	Line int
+	Col  int
+}
+
+// String formats the position as file:line:col.
+func (p Position) String() string {
+	return fmt.Sprintf("%s:%d:%d", p.File, p.Line, p.Col)
+}
+
+// String formats the diagnostic as "where: severity: message [code]", where is
+// file:line:col when known and the pointer otherwise.
+func (d Diagnostic) String() string {
+	where := d.Pointer
+	if d.Pos.File != "" {
+		where = d.Pos.String()
+	}
+	return fmt.Sprintf("%s: %s: %s [%s]", where, d.Severity, d.Message, d.Code)
+}
+
+// ValidateOptions adjusts an offline validator.
+type ValidateOptions struct {
+	// ControlSockets is the Control Agent's control-sockets map. When set, the server's
+	// control-socket is checked against the entry for its service.
+	ControlSockets map[string]SocketConfig
+}
+
+// Diagnostics is the result of an offline validation, in the order the problems were found.
+type Diagnostics []Diagnostic
+
+// HasErrors reports whether any diagnostic has SeverityError.
+func (ds Diagnostics) HasErrors() bool {
+	for _, d := range ds {
+		if d.Severity == SeverityError {
+			return true
+		}
+	}
+	return false
+}
+
+// Errors returns the diagnostics with SeverityError.
+func (ds Diagnostics) Errors() Diagnostics {
+	var out Diagnostics
+	for _, d := range ds {
+		if d.Severity == SeverityError {
+			out = append(out, d)
+		}
+	}
+	return out
+}
+
+// Err returns a *ValidationError holding the error diagnostics, or nil if there are none.
+func (ds Diagnostics) Err() error {
+	if errs := ds.Errors(); len(errs) > 0 {
+		return &ValidationError{Diagnostics: errs}
+	}
+	return nil
+}
+
+// String renders one diagnostic per line.
+func (ds Diagnostics) String() string {
+	var b strings.Builder
+	for _, d := range ds {
+		b.WriteString(d.String())
+		b.WriteByte('\n')
+	}
+	return b.String()
+}
+
+// ValidationError reports a configuration that failed offline validation.
+type ValidationError struct {
+	Diagnostics Diagnostics
+}
+
+func (e *ValidationError) Error() string {
+	if len(e.Diagnostics) == 1 {
+		return "invalid configuration: " + e.Diagnostics[0].String()
+	}
+	return fmt.Sprintf("invalid configuration: %d problems:\n%s", len(e.Diagnostics), strings.TrimSuffix(e.Diagnostics.String(), "\n"))
+}